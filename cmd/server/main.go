@@ -1,19 +1,28 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 
+	"github.com/vera-byte/vgo-iam/internal/api"
 	"github.com/vera-byte/vgo-iam/internal/auth"
 	"github.com/vera-byte/vgo-iam/internal/bootstrap"
+	"github.com/vera-byte/vgo-iam/internal/extauthz"
 	"github.com/vera-byte/vgo-iam/internal/util"
 	"github.com/vera-byte/vgo-iam/internal/version"
 	iamv1 "github.com/vera-byte/vgo-iam/pkg/proto"
+	"github.com/vera-byte/vgo-iam/pkg/signer"
 )
 
 // ServerCmd 代表server命令
@@ -76,25 +85,45 @@ func startServer(cmd *cobra.Command) {
 	defer session.Close()
 	// 由于InitServices不返回错误，我们不需要错误处理
 
-	// 获取accessKeyService并获取其存储实现
+	// 获取accessKeyService，认证中间件通过它解析访问密钥
 	accessKeyService := iamServer.AccessKeyService()
-	accessKeyStore := accessKeyService.GetStore()
+	nonceStore := bootstrap.NewNonceStore(cfg, session)
+	authorizer := auth.NewAuthorizer(iamServer.UserService(), iamServer.PolicyEngine())
+	methodRules, err := auth.NewMethodRules(cfg.Auth)
+	if err != nil {
+		logger.Fatal("Invalid auth configuration", util.Err(err))
+	}
+	authenticator := auth.NewAuthenticator(accessKeyService, iamServer.LoginService(), iamServer.TokenService(), nonceStore, authorizer, methodRules, iamServer.FailureThrottle())
 
 	// 处理命令行请求
 	hasCommand := createUser != "" || getUser != "" || getPolicies != ""
 
 	// 如果没有命令行请求或请求了启动服务器
 	if !hasCommand || !noServer {
-		// 创建gRPC服务器并添加认证中间件
 		// 创建gRPC服务器并添加认证中间件
 		server := grpc.NewServer(
-			grpc.UnaryInterceptor(auth.AccessKeyInterceptor(accessKeyStore)),
+			grpc.UnaryInterceptor(authenticator.UnaryServerInterceptor()),
+			grpc.StreamInterceptor(authenticator.StreamServerInterceptor()),
 		)
 		iamv1.RegisterIAMServer(server, iamServer)
 
 		// 使用从bootstrap.Start()获取的listener
 		logger.Info("Using listener from bootstrap.Start()")
 
+		// 启动访问密钥过期检查和使用记录写入任务
+		jobCtx, cancelJobs := context.WithCancel(context.Background())
+		defer cancelJobs()
+		go accessKeyService.RunExpiryJob(jobCtx)
+		go auth.RunNonceCleanup(jobCtx, nonceStore, signer.MaxClockSkew)
+		go iamServer.LoginService().RunSessionCleanup(jobCtx)
+		go iamServer.LockoutService().RunCleanup(jobCtx)
+		go iamServer.TokenService().RunKeyRotation(jobCtx)
+		usageFlushed := make(chan struct{})
+		go func() {
+			accessKeyService.RunUsageFlusher(jobCtx)
+			close(usageFlushed)
+		}()
+
 		// 启动服务协程
 		go func() {
 			logger.Info("Starting gRPC server on port 50051")
@@ -103,12 +132,62 @@ func startServer(cmd *cobra.Command) {
 			}
 		}()
 
+		// 启动Envoy外部授权服务
+		var extAuthzServer *grpc.Server
+		if cfg.ExtAuthz.Enabled {
+			routes, err := extauthz.NewRoutes(cfg.ExtAuthz)
+			if err != nil {
+				logger.Fatal("Invalid ext_authz configuration", util.Err(err))
+			}
+			extLis, err := net.Listen("tcp", ":"+cfg.ExtAuthz.Port)
+			if err != nil {
+				logger.Fatal("Failed to listen for ext_authz", util.Err(err))
+			}
+			extAuthzServer = grpc.NewServer()
+			authv3.RegisterAuthorizationServer(extAuthzServer, extauthz.NewServer(authenticator, routes, cfg.ExtAuthz))
+			go func() {
+				logger.Info("Starting ext_authz server on port " + cfg.ExtAuthz.Port)
+				if err := extAuthzServer.Serve(extLis); err != nil {
+					logger.Fatal("Failed to serve ext_authz", util.Err(err))
+				}
+			}()
+		}
+
+		// 启动OAuth2令牌端点
+		var tokenServer *http.Server
+		if cfg.Token.Enabled {
+			mux := http.NewServeMux()
+			mux.Handle(api.TokenPath, iamServer.TokenHandler())
+			mux.Handle(api.JWKSPath, iamServer.JWKSHandler())
+			tokenServer = &http.Server{
+				Addr:              ":" + cfg.Token.Port,
+				Handler:           mux,
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+			go func() {
+				logger.Info("Starting token endpoint on port " + cfg.Token.Port)
+				if err := tokenServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					logger.Fatal("Failed to serve token endpoint", util.Err(err))
+				}
+			}()
+		}
+
 		// 优雅关闭
 		quit := make(chan os.Signal, 1)
 		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 		<-quit
 		logger.Info("Shutting down server...")
 		server.GracefulStop()
+		if extAuthzServer != nil {
+			extAuthzServer.GracefulStop()
+		}
+		if tokenServer != nil {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			tokenServer.Shutdown(shutdownCtx)
+			cancel()
+		}
+		cancelJobs()
+		<-usageFlushed
 		logger.Info("Server exiting")
 	} else {
 		logger.Info("Server not started (--no-server flag set)")
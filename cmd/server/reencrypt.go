@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/vera-byte/vgo-iam/internal/bootstrap"
+	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/service"
+	"github.com/vera-byte/vgo-iam/internal/store"
+	"github.com/vera-byte/vgo-iam/internal/util"
+)
+
+// ReEncryptCmd 将访问密钥、MFA设备和令牌签名密钥的密文迁移到当前主密钥
+var ReEncryptCmd = &cobra.Command{
+	Use:   "reencrypt-secrets",
+	Short: "Re-encrypt stored access key, MFA and token signing secrets with the current master key",
+	Long: `Re-encrypt every encrypted_secret_access_key (and the previous secret kept during a
+rotation grace period) with the current master key, in batches. Make the new key current in
+the key provider (keeping the old one available for decryption) before running. Envelope
+ciphertexts only get their data key re-wrapped. Rows already using the current key are
+skipped, so the job can be interrupted and run again, or resumed with --after-id.
+MFA device secrets and token signing keys are re-encrypted afterwards, always from the first row.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runReEncrypt()
+	},
+}
+
+var (
+	reEncryptConfig    string
+	reEncryptAfterID   int
+	reEncryptBatchSize int
+)
+
+func init() {
+	ReEncryptCmd.Flags().StringVar(&reEncryptConfig, "config", "config/config.yaml", "Path to the config file")
+	ReEncryptCmd.Flags().IntVar(&reEncryptAfterID, "after-id", 0, "Resume after this access key row id")
+	ReEncryptCmd.Flags().IntVar(&reEncryptBatchSize, "batch-size", 100, "Number of access keys per batch")
+	ServerCmd.AddCommand(ReEncryptCmd)
+}
+
+func runReEncrypt() {
+	cfg, err := util.LoadConfig(reEncryptConfig)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	logger, err := util.InitLogger(cfg.Log)
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Sync()
+
+	accessKeyService, mfaService, tokenService, closeDB, err := newReEncryptServices(cfg)
+	if err != nil {
+		logger.Fatal("Failed to initialize re-encryption", util.Err(err))
+	}
+	defer closeDB()
+
+	// 收到中断信号时在当前批次结束后停止
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info("Re-encrypting access key secrets",
+		zap.String("key_provider", cfg.Security.KeyProvider),
+		zap.Int("after_id", reEncryptAfterID),
+		zap.Int("batch_size", reEncryptBatchSize),
+	)
+	progress, err := accessKeyService.ReEncryptSecrets(ctx, reEncryptAfterID, reEncryptBatchSize, func(p service.ReEncryptProgress) {
+		logger.Info("Re-encryption progress", progressFields(p)...)
+	})
+	if err != nil {
+		logger.Fatal("Re-encryption stopped, resume with --after-id",
+			append(progressFields(progress), util.Err(err))...)
+	}
+	logger.Info("Re-encryption finished", progressFields(progress)...)
+	if progress.Conflicts > 0 {
+		logger.Warn("Some access keys changed during re-encryption, run the command again to migrate them",
+			zap.Int("conflicts", progress.Conflicts))
+	}
+
+	logger.Info("Re-encrypting MFA device secrets")
+	mfaProgress, err := mfaService.ReEncryptSecrets(ctx, reEncryptBatchSize, func(p service.ReEncryptProgress) {
+		logger.Info("MFA re-encryption progress", progressFields(p)...)
+	})
+	if err != nil {
+		logger.Fatal("MFA re-encryption stopped, run the command again",
+			append(progressFields(mfaProgress), util.Err(err))...)
+	}
+	logger.Info("MFA re-encryption finished", progressFields(mfaProgress)...)
+	if mfaProgress.Conflicts > 0 {
+		logger.Warn("Some MFA devices changed during re-encryption, run the command again to migrate them",
+			zap.Int("conflicts", mfaProgress.Conflicts))
+	}
+
+	logger.Info("Re-encrypting token signing keys")
+	tokenProgress, err := tokenService.ReEncryptSigningKeys(ctx)
+	if err != nil {
+		logger.Fatal("Token signing key re-encryption stopped, run the command again",
+			append(progressFields(tokenProgress), util.Err(err))...)
+	}
+	logger.Info("Token signing key re-encryption finished", progressFields(tokenProgress)...)
+	if tokenProgress.Conflicts > 0 {
+		logger.Warn("Some token signing keys changed during re-encryption, run the command again to migrate them",
+			zap.Int("conflicts", tokenProgress.Conflicts))
+	}
+}
+
+// newReEncryptServices 创建只用于重新加密的访问密钥、MFA和令牌服务，不启动gRPC服务和初始化任务
+func newReEncryptServices(cfg *config.AppConfig) (*service.AccessKeyService, *service.MFAService, *service.TokenService, func(), error) {
+	keyring, err := bootstrap.NewKeyring(cfg)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	sess, err := store.NewPostgresStore(cfg.Database.DSN)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	accessKeyStore := store.NewAccessKeyStore(sess.Session)
+	userStore := store.NewUserStore(sess.Session)
+	mfaDeviceStore := store.NewMFADeviceStore(sess.Session)
+	accessKeyService := service.NewAccessKeyService(accessKeyStore, userStore, keyring, cfg.AccessKey)
+	return accessKeyService,
+		service.NewMFAService(userStore, mfaDeviceStore, keyring, cfg.Login.MFA),
+		service.NewTokenService(accessKeyService, store.NewSigningKeyStore(sess.Session), keyring, cfg.Token),
+		func() { sess.Close() }, nil
+}
+
+func progressFields(p service.ReEncryptProgress) []zap.Field {
+	return []zap.Field{
+		zap.Int("total", p.Total),
+		zap.Int("processed", p.Processed),
+		zap.Int("migrated", p.Migrated),
+		zap.Int("skipped", p.Skipped),
+		zap.Int("conflicts", p.Conflicts),
+		zap.Int("last_id", p.LastID),
+	}
+}
@@ -0,0 +1,402 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vera-byte/vgo-iam/internal/auth"
+	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/crypto"
+	"github.com/vera-byte/vgo-iam/internal/model"
+	"github.com/vera-byte/vgo-iam/internal/policy"
+	"github.com/vera-byte/vgo-iam/internal/service"
+	"github.com/vera-byte/vgo-iam/internal/store"
+	"github.com/vera-byte/vgo-iam/internal/util"
+	iamv1 "github.com/vera-byte/vgo-iam/pkg/proto"
+	"github.com/vera-byte/vgo-iam/pkg/signer"
+)
+
+// memUserStore 内存中的用户存储，同时作为策略引擎的策略来源
+type memUserStore struct {
+	store.UserStore
+	users    []*model.User
+	policies map[int][]*model.Policy
+}
+
+func (f *memUserStore) GetUserPolicies(ctx context.Context, userID int) ([]*model.Policy, error) {
+	return f.policies[userID], nil
+}
+
+func (f *memUserStore) GetByName(name string) (*model.User, error) {
+	for _, u := range f.users {
+		if u.Name == name {
+			return u, nil
+		}
+	}
+	return nil, dbr.ErrNotFound
+}
+
+func (f *memUserStore) GetByID(id int) (*model.User, error) {
+	for _, u := range f.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, dbr.ErrNotFound
+}
+
+// memAccessKeyStore 内存中的访问密钥存储，Create在锁内校验数量上限，与数据库实现锁定用户行的效果相同
+type memAccessKeyStore struct {
+	store.AccessKeyStore
+	mu       sync.Mutex
+	keys     map[string]*model.AccessKey
+	previous map[string][]byte // 轮换前的旧密钥密文
+}
+
+func (f *memAccessKeyStore) Create(ak *model.AccessKey, maxPerUser int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	count := 0
+	for _, key := range f.keys {
+		if key.UserID == ak.UserID {
+			count++
+		}
+	}
+	if maxPerUser > 0 && count >= maxPerUser {
+		return store.ErrAccessKeyQuotaExceeded
+	}
+	copied := *ak
+	f.keys[ak.AccessKeyID] = &copied
+	return nil
+}
+
+func (f *memAccessKeyStore) GetByAccessKeyID(accessKeyID string) (*model.AccessKey, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ak, ok := f.keys[accessKeyID]
+	if !ok {
+		return nil, dbr.ErrNotFound
+	}
+	copied := *ak
+	return &copied, nil
+}
+
+func (f *memAccessKeyStore) GetEncryptedSecrets(accessKeyID string) (*model.AccessKeySecrets, error) {
+	ak, err := f.GetByAccessKeyID(accessKeyID)
+	if err != nil {
+		return nil, err
+	}
+	secrets := &model.AccessKeySecrets{UserID: ak.UserID, Version: ak.SecretVersion, Current: ak.EncryptedSecretKey}
+	if ak.PreviousSecretExpiresAt != nil {
+		secrets.Previous, secrets.PreviousExpiresAt = f.previous[accessKeyID], ak.PreviousSecretExpiresAt
+	}
+	return secrets, nil
+}
+
+// RotateKey 与数据库实现的条件相同：版本一致且没有宽限期内的旧密钥
+func (f *memAccessKeyStore) RotateKey(accessKeyID string, version int, encryptedSecret []byte, graceUntil time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ak, ok := f.keys[accessKeyID]
+	if !ok || ak.SecretVersion != version || (ak.PreviousSecretExpiresAt != nil && ak.PreviousSecretExpiresAt.After(time.Now())) {
+		return store.ErrSecretVersionConflict
+	}
+	f.previous[accessKeyID] = ak.EncryptedSecretKey
+	ak.EncryptedSecretKey = encryptedSecret
+	ak.SecretVersion++
+	ak.PreviousSecretExpiresAt = &graceUntil
+	return nil
+}
+
+// Delete 与数据库实现相同，状态检查和删除在同一次加锁内完成
+func (f *memAccessKeyStore) Delete(accessKeyID string, force bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ak, ok := f.keys[accessKeyID]
+	if !ok {
+		return dbr.ErrNotFound
+	}
+	if ak.Status == "active" && !force {
+		return store.ErrAccessKeyActive
+	}
+	delete(f.keys, accessKeyID)
+	return nil
+}
+
+// update 修改保存的访问密钥
+func (f *memAccessKeyStore) update(accessKeyID string, fn func(ak *model.AccessKey)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fn(f.keys[accessKeyID])
+}
+
+// newAccessKeyTestServer 创建使用内存存储的IAMServer，用户alice、bob、carol的ID依次为1、2、3
+// policies按用户ID给出附加的策略文档
+func newAccessKeyTestServer(t *testing.T, cfg config.AccessKeyConfig, policies ...map[int]string) (*IAMServer, *memAccessKeyStore) {
+	t.Helper()
+	util.Logger = zap.NewNop()
+	local := crypto.NewLocalKeyring("test")
+	if err := local.AddKey("test", []byte("0123456789abcdef0123456789abcdef")); err != nil {
+		t.Fatalf("failed to add master key: %v", err)
+	}
+	userStore := &memUserStore{
+		users:    []*model.User{{ID: 1, Name: "alice"}, {ID: 2, Name: "bob"}, {ID: 3, Name: "carol"}},
+		policies: make(map[int][]*model.Policy),
+	}
+	for _, docs := range policies {
+		for userID, doc := range docs {
+			userStore.policies[userID] = append(userStore.policies[userID], &model.Policy{PolicyDocument: doc})
+		}
+	}
+	akStore := &memAccessKeyStore{keys: make(map[string]*model.AccessKey), previous: make(map[string][]byte)}
+	accessKeyService := service.NewAccessKeyService(akStore, userStore, crypto.NewKeyRotationManager(local), cfg)
+	server := NewIAMServer(service.NewUserService(userStore, nil), nil, accessKeyService, nil, nil, nil, nil, policy.NewPolicyEngine(userStore), nil)
+	return server, akStore
+}
+
+func TestCreateAccessKeyTTL(t *testing.T) {
+	server, _ := newAccessKeyTestServer(t, config.AccessKeyConfig{MaxTTL: 24 * time.Hour})
+	ctx := context.Background()
+
+	for _, ttl := range []int64{-1, 25 * 3600, math.MaxInt64} {
+		_, err := server.CreateAccessKey(ctx, &iamv1.CreateAccessKeyRequest{UserName: "alice", TtlSeconds: ttl})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("ttl_seconds=%d: expected InvalidArgument, got %v", ttl, err)
+		}
+	}
+
+	ak, err := server.CreateAccessKey(ctx, &iamv1.CreateAccessKeyRequest{UserName: "alice", TtlSeconds: 3600})
+	if err != nil {
+		t.Fatalf("CreateAccessKey failed: %v", err)
+	}
+	if expiresAt := ak.ExpiresAt.AsTime(); expiresAt.Before(time.Now().Add(59*time.Minute)) || expiresAt.After(time.Now().Add(time.Hour)) {
+		t.Errorf("unexpected expires_at %v", expiresAt)
+	}
+}
+
+func TestVerifyAccessKeyRejectsExpiredKey(t *testing.T) {
+	server, akStore := newAccessKeyTestServer(t, config.AccessKeyConfig{})
+	ctx := context.Background()
+	ak, err := server.CreateAccessKey(ctx, &iamv1.CreateAccessKeyRequest{UserName: "alice", TtlSeconds: 3600})
+	if err != nil {
+		t.Fatalf("CreateAccessKey failed: %v", err)
+	}
+
+	sign := func() *iamv1.VerifyRequest {
+		now := time.Now()
+		timestamp := signer.FormatTimestamp(now)
+		scope := signer.NewCredentialScope(now, signer.DefaultRegion, "orders")
+		requestData := "GET\n/orders\n\nhost:orders\n\nhost\n" + signer.HashPayload(nil)
+		return &iamv1.VerifyRequest{
+			AccessKeyId:     ak.AccessKeyId,
+			Signature:       signer.CalculateSignature(signer.BuildStringToSign(timestamp, scope, requestData), ak.SecretAccessKey, scope),
+			RequestData:     requestData,
+			Timestamp:       timestamp,
+			CredentialScope: scope.String(),
+		}
+	}
+
+	resp, err := server.VerifyAccessKey(ctx, sign())
+	if err != nil || !resp.Valid || resp.UserName != "alice" {
+		t.Fatalf("VerifyAccessKey = %+v, %v", resp, err)
+	}
+
+	// 签名正确但密钥已过期
+	akStore.update(ak.AccessKeyId, func(key *model.AccessKey) {
+		expired := time.Now().Add(-time.Second)
+		key.ExpiresAt = &expired
+	})
+	if _, err := server.VerifyAccessKey(ctx, sign()); err != auth.ErrAuthenticationFailed {
+		t.Errorf("expected ErrAuthenticationFailed for expired key, got %v", err)
+	}
+}
+
+func TestRotateAccessKeyGracePeriod(t *testing.T) {
+	server, _ := newAccessKeyTestServer(t, config.AccessKeyConfig{RotationGracePeriod: time.Hour})
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalUser, UserID: 1, UserName: "alice"})
+	created, err := server.CreateAccessKey(ctx, &iamv1.CreateAccessKeyRequest{UserName: "alice"})
+	if err != nil {
+		t.Fatalf("CreateAccessKey failed: %v", err)
+	}
+	secrets := func() []string {
+		resolved, err := server.accessKeyService.ResolveSecrets(ctx, created.AccessKeyId)
+		if err != nil {
+			t.Fatalf("ResolveSecrets failed: %v", err)
+		}
+		return resolved
+	}
+
+	// 显式的0表示旧密钥立即失效，可以马上再次轮换
+	zero := int64(0)
+	for i := 0; i < 2; i++ {
+		rotated, err := server.RotateAccessKey(ctx, &iamv1.RotateAccessKeyRequest{AccessKeyId: created.AccessKeyId, GracePeriodSeconds: &zero})
+		if err != nil {
+			t.Fatalf("RotateAccessKey with zero grace failed: %v", err)
+		}
+		if got := secrets(); len(got) != 1 || got[0] != rotated.SecretAccessKey {
+			t.Errorf("expected only the new secret after rotation without grace, got %d secrets", len(got))
+		}
+	}
+
+	// 未设置时使用默认宽限期，旧密钥仍然有效
+	rotated, err := server.RotateAccessKey(ctx, &iamv1.RotateAccessKeyRequest{AccessKeyId: created.AccessKeyId})
+	if err != nil {
+		t.Fatalf("RotateAccessKey failed: %v", err)
+	}
+	if graceUntil := rotated.PreviousSecretExpiresAt.AsTime(); graceUntil.Before(time.Now().Add(59 * time.Minute)) {
+		t.Errorf("expected the default grace period, previous secret expires at %v", graceUntil)
+	}
+	if got := secrets(); len(got) != 2 {
+		t.Errorf("expected new and old secrets during grace period, got %d", len(got))
+	}
+
+	// 宽限期内再次轮换会使承诺有效的旧密钥提前失效，拒绝
+	_, err = server.RotateAccessKey(ctx, &iamv1.RotateAccessKeyRequest{AccessKeyId: created.AccessKeyId, GracePeriodSeconds: &zero})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition while previous secret is in grace, got %v", err)
+	}
+	negative := int64(-1)
+	_, err = server.RotateAccessKey(ctx, &iamv1.RotateAccessKeyRequest{AccessKeyId: created.AccessKeyId, GracePeriodSeconds: &negative})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for negative grace, got %v", err)
+	}
+}
+
+// brokenAccessKeyStore 所有查询都返回数据库错误的访问密钥存储
+type brokenAccessKeyStore struct {
+	store.AccessKeyStore
+}
+
+func (brokenAccessKeyStore) GetByAccessKeyID(string) (*model.AccessKey, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestGetAccessKeyLastUsedErrors(t *testing.T) {
+	server, _ := newAccessKeyTestServer(t, config.AccessKeyConfig{})
+	ctx := context.Background()
+	_, err := server.GetAccessKeyLastUsed(ctx, &iamv1.GetAccessKeyLastUsedRequest{AccessKeyId: "MISSING"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for unknown access key, got %v", err)
+	}
+
+	// 数据库错误不能伪装成密钥不存在
+	server.accessKeyService = service.NewAccessKeyService(brokenAccessKeyStore{}, &memUserStore{}, nil, config.AccessKeyConfig{})
+	_, err = server.GetAccessKeyLastUsed(ctx, &iamv1.GetAccessKeyLastUsedRequest{AccessKeyId: "AKID"})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("expected Internal for store errors, got %v", err)
+	}
+}
+
+func TestCreateAccessKeyQuota(t *testing.T) {
+	const max = 3
+	server, akStore := newAccessKeyTestServer(t, config.AccessKeyConfig{MaxKeysPerUser: max})
+	ctx := context.Background()
+
+	// 并发创建时数量上限在存储层加锁校验，成功数不能超过上限
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := server.CreateAccessKey(ctx, &iamv1.CreateAccessKeyRequest{UserName: "alice"})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	created := 0
+	for err := range errs {
+		switch status.Code(err) {
+		case codes.OK:
+			created++
+		case codes.ResourceExhausted:
+		default:
+			t.Errorf("expected ResourceExhausted over quota, got %v", err)
+		}
+	}
+	if created != max || len(akStore.keys) != max {
+		t.Errorf("expected exactly %d keys, created %d, stored %d", max, created, len(akStore.keys))
+	}
+}
+
+func TestDeleteAccessKey(t *testing.T) {
+	server, akStore := newAccessKeyTestServer(t, config.AccessKeyConfig{})
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalUser, UserID: 1, UserName: "alice"})
+	ak, err := server.CreateAccessKey(ctx, &iamv1.CreateAccessKeyRequest{UserName: "alice"})
+	if err != nil {
+		t.Fatalf("CreateAccessKey failed: %v", err)
+	}
+
+	_, err = server.DeleteAccessKey(ctx, &iamv1.DeleteAccessKeyRequest{AccessKeyId: ak.AccessKeyId})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition deleting an active key, got %v", err)
+	}
+	if _, ok := akStore.keys[ak.AccessKeyId]; !ok {
+		t.Fatal("active key was deleted without force")
+	}
+
+	if _, err := server.DeleteAccessKey(ctx, &iamv1.DeleteAccessKeyRequest{AccessKeyId: ak.AccessKeyId, Force: true}); err != nil {
+		t.Fatalf("forced DeleteAccessKey failed: %v", err)
+	}
+	if _, ok := akStore.keys[ak.AccessKeyId]; ok {
+		t.Error("expected the key to be deleted with force")
+	}
+	_, err = server.DeleteAccessKey(ctx, &iamv1.DeleteAccessKeyRequest{AccessKeyId: ak.AccessKeyId, Force: true})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound deleting a missing key, got %v", err)
+	}
+}
+
+func TestRotateAccessKeyOwnership(t *testing.T) {
+	// carol可以轮换bob的密钥，alice没有附加任何策略
+	server, _ := newAccessKeyTestServer(t, config.AccessKeyConfig{}, map[int]string{
+		3: `{"Version":"1","Statement":[{"Effect":"Allow","Action":["iam:RotateAccessKey"],"Resource":["iam:user:bob"]}]}`,
+	})
+	as := func(userID int, name string) context.Context {
+		return auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalUser, UserID: userID, UserName: name})
+	}
+	scoped := func(userID int, name string, scopes ...string) context.Context {
+		return auth.WithPrincipal(context.Background(), &auth.Principal{Type: auth.PrincipalUser, UserID: userID, UserName: name,
+			AuthMethod: auth.AuthMethodToken, Scopes: scopes})
+	}
+	aliceKey, err := server.CreateAccessKey(context.Background(), &iamv1.CreateAccessKeyRequest{UserName: "alice"})
+	if err != nil {
+		t.Fatalf("CreateAccessKey failed: %v", err)
+	}
+	bobKey, err := server.CreateAccessKey(context.Background(), &iamv1.CreateAccessKeyRequest{UserName: "bob"})
+	if err != nil {
+		t.Fatalf("CreateAccessKey failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		keyID  string
+		expect codes.Code
+	}{
+		{"self rotate without policy", as(1, "alice"), aliceKey.AccessKeyId, codes.OK},
+		{"cross-user rotate without policy", as(1, "alice"), bobKey.AccessKeyId, codes.PermissionDenied},
+		{"cross-user rotate allowed on owner", as(3, "carol"), bobKey.AccessKeyId, codes.OK},
+		{"cross-user rotate on other owner", as(3, "carol"), aliceKey.AccessKeyId, codes.PermissionDenied},
+		{"self rotate with token scope excluding the action", scoped(1, "alice", "iam:GetUser"), aliceKey.AccessKeyId, codes.PermissionDenied},
+		{"self rotate with token scope including the action", scoped(1, "alice", "iam:*"), aliceKey.AccessKeyId, codes.OK},
+	}
+	zero := int64(0)
+	for _, tt := range tests {
+		_, err := server.RotateAccessKey(tt.ctx, &iamv1.RotateAccessKeyRequest{AccessKeyId: tt.keyID, GracePeriodSeconds: &zero})
+		if status.Code(err) != tt.expect {
+			t.Errorf("%s: expected %s, got %v", tt.name, tt.expect, err)
+		}
+	}
+}
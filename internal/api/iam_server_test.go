@@ -5,6 +5,7 @@ import (
 	"net"
 	"testing"
 
+	"github.com/vera-byte/vgo-iam/internal/crypto"
 	"github.com/vera-byte/vgo-iam/internal/policy"
 	"github.com/vera-byte/vgo-iam/internal/service"
 	"github.com/vera-byte/vgo-iam/internal/store"
@@ -45,11 +46,16 @@ func initServer(t *testing.T) iamv1.IAMClient {
 
 	userService := service.NewUserService(userStore, policyStore)
 	policyService := service.NewPolicyService(policyStore)
-	accessKeyService := service.NewAccessKeyService(accessKeyStore, userStore, []byte(cfg.Security.MasterKey))
+	keyProvider, err := crypto.NewKeyProvider(cfg.Security)
+	if err != nil {
+		t.Fatalf("failed to load master key: %v", err)
+	}
+	keyring := crypto.NewKeyRotationManager(keyProvider)
+	accessKeyService := service.NewAccessKeyService(accessKeyStore, userStore, keyring, cfg.AccessKey)
 	policyEngine := policy.NewPolicyEngine(userService)
 	iamv1.RegisterIAMServer(s, NewIAMServer(
-		// 传入 mock userService, policyService, accessKeyService, policyEngine, masterKey
-		userService, policyService, accessKeyService, policyEngine, []byte(cfg.Security.MasterKey),
+		// 传入 mock userService, policyService, accessKeyService, policyEngine
+		userService, policyService, accessKeyService, nil, nil, nil, nil, policyEngine, nil,
 	))
 
 	errChan := make(chan error, 1)
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vera-byte/vgo-iam/internal/auth"
+	"github.com/vera-byte/vgo-iam/internal/crypto"
+	"github.com/vera-byte/vgo-iam/internal/service"
+)
+
+// TokenPath OAuth2令牌端点路径
+const TokenPath = "/oauth2/token"
+
+// JWKSPath 校验访问令牌的公钥集合路径
+const JWKSPath = "/.well-known/jwks.json"
+
+// maxTokenRequestSize 令牌请求体的最大字节数
+const maxTokenRequestSize = 64 << 10
+
+// tokenResponse 令牌端点的成功响应(RFC 6749 5.1)
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
+}
+
+// tokenError 令牌端点的错误响应(RFC 6749 5.2)
+type tokenError struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+// TokenHandler 返回OAuth2令牌端点，只支持client_credentials授权
+// 访问密钥ID和密钥作为client_id和client_secret，通过HTTP Basic认证或表单参数传递；
+// 凭证错误按访问密钥ID和来源IP计入lockoutService
+func (s *IAMServer) TokenHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeTokenError(w, http.StatusMethodNotAllowed, "invalid_request", "token requests must use POST")
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxTokenRequestSize)
+		if err := r.ParseForm(); err != nil {
+			writeTokenError(w, http.StatusBadRequest, "invalid_request", "malformed request body")
+			return
+		}
+
+		switch grantType := r.PostForm.Get("grant_type"); grantType {
+		case "client_credentials":
+		case "":
+			writeTokenError(w, http.StatusBadRequest, "invalid_request", "grant_type is required")
+			return
+		default:
+			writeTokenError(w, http.StatusBadRequest, "unsupported_grant_type", "only client_credentials is supported")
+			return
+		}
+		clientID, clientSecret, ok := clientCredentials(r)
+		if !ok {
+			writeTokenError(w, http.StatusBadRequest, "invalid_request", "client credentials must be sent exactly once")
+			return
+		}
+
+		ctx := r.Context()
+		sourceIP := auth.SourceIPFromHTTPRequest(r)
+		subject := service.AccessKeySubject(clientID)
+		hasFailures, err := s.checkLockout(ctx, subject, sourceIP)
+		if err != nil {
+			errorCode := "server_error"
+			if status.Code(err) == codes.ResourceExhausted {
+				errorCode = "temporarily_unavailable"
+			}
+			writeTokenError(w, auth.HTTPStatusFromCode(status.Code(err)), errorCode, status.Convert(err).Message())
+			return
+		}
+
+		token, err := s.tokenService.IssueToken(ctx, clientID, clientSecret, r.PostForm.Get("scope"))
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrInvalidScope):
+				writeTokenError(w, http.StatusBadRequest, "invalid_scope", err.Error())
+			case errors.Is(err, service.ErrInvalidClient), errors.Is(err, service.ErrAccessKeyInactive):
+				requestLogger(ctx).Warn("Authentication failed",
+					zap.String("access_key_id", clientID),
+					zap.String("source_ip", sourceIP),
+					zap.String("operation", r.Method+" "+TokenPath),
+					zap.String("reason", err.Error()),
+				)
+				if errors.Is(err, service.ErrInvalidClient) {
+					s.recordLockoutFailure(ctx, subject, sourceIP)
+				}
+				w.Header().Set("WWW-Authenticate", `Basic realm="vgo-iam"`)
+				writeTokenError(w, http.StatusUnauthorized, "invalid_client", "client authentication failed")
+			default:
+				requestLogger(ctx).Error("Failed to issue token", zap.String("access_key_id", clientID), zap.Error(err))
+				writeTokenError(w, http.StatusInternalServerError, "server_error", "failed to issue token")
+			}
+			return
+		}
+		if hasFailures {
+			s.clearLockout(ctx, subject)
+		}
+		s.accessKeyService.RecordUsage(clientID, r.Method+" "+TokenPath, sourceIP)
+
+		requestLogger(ctx).Info("Access token issued",
+			zap.String("access_key_id", clientID),
+			zap.String("username", token.Claims.Subject),
+			zap.String("scope", token.Claims.Scope),
+			zap.String("token_id", token.Claims.ID),
+			zap.String("source_ip", sourceIP),
+		)
+		writeTokenJSON(w, http.StatusOK, tokenResponse{
+			AccessToken: token.AccessToken,
+			TokenType:   auth.BearerScheme,
+			ExpiresIn:   token.Claims.ExpiresAt - token.Claims.IssuedAt,
+			Scope:       token.Claims.Scope,
+		})
+	})
+}
+
+// JWKSHandler 返回公钥集合端点，包含next、active和尚未删除的retired密钥
+// 响应允许缓存service.JWKSMaxAge，next密钥至少发布这么久才会用于签发令牌
+func (s *IAMServer) JWKSHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(service.JWKSMaxAge.Seconds())))
+		json.NewEncoder(w).Encode(struct {
+			Keys []crypto.JWK `json:"keys"`
+		}{Keys: s.tokenService.JWKS()})
+	})
+}
+
+// clientCredentials 从HTTP Basic认证或表单参数中读取客户端凭证，两种方式同时使用时返回false
+// Basic认证中的凭证按RFC 6749 2.3.1先做表单编码，这里解码后使用
+func clientCredentials(r *http.Request) (string, string, bool) {
+	formID, formSecret := r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	user, password, ok := r.BasicAuth()
+	if !ok {
+		return formID, formSecret, formID != "" && formSecret != ""
+	}
+	if formSecret != "" {
+		return "", "", false
+	}
+	clientID, err := url.QueryUnescape(user)
+	if err != nil {
+		return "", "", false
+	}
+	clientSecret, err := url.QueryUnescape(password)
+	if err != nil {
+		return "", "", false
+	}
+	return clientID, clientSecret, clientID != "" && clientSecret != ""
+}
+
+// writeTokenError 写入令牌端点的错误响应
+func writeTokenError(w http.ResponseWriter, code int, errorCode, description string) {
+	writeTokenJSON(w, code, tokenError{Error: errorCode, Description: description})
+}
+
+// writeTokenJSON 写入令牌端点的JSON响应，响应不允许缓存
+func writeTokenJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/vera-byte/vgo-iam/internal/config"
+	iamv1 "github.com/vera-byte/vgo-iam/pkg/proto"
+)
+
+func TestMethodRulesCoverService(t *testing.T) {
+	rules, err := NewMethodRules(config.AuthConfig{})
+	if err != nil {
+		t.Fatalf("NewMethodRules failed: %v", err)
+	}
+
+	for _, m := range iamv1.IAM_ServiceDesc.Methods {
+		fullMethod := "/" + iamv1.IAM_ServiceDesc.ServiceName + "/" + m.MethodName
+		rule := rules.Lookup(fullMethod)
+		if rule.Mode == ModeAuthorized && rule.Action == "" {
+			t.Errorf("method %s has no authorization rule", fullMethod)
+		}
+	}
+	if rules.Lookup(iamv1.IAM_VerifyAccessKey_FullMethodName).Mode != ModePublic {
+		t.Error("VerifyAccessKey should be public by default")
+	}
+	if rules.Lookup(iamv1.IAM_VerifyApiKey_FullMethodName).Mode != ModePublic {
+		t.Error("VerifyApiKey should be public by default")
+	}
+	// 访问密钥管理由处理函数按密钥所属用户授权
+	if rules.Lookup(iamv1.IAM_RotateAccessKey_FullMethodName).Mode != ModeAuthenticated {
+		t.Error("RotateAccessKey should only be authenticated by the interceptor")
+	}
+}
+
+func TestMethodRulesFromConfig(t *testing.T) {
+	rules, err := NewMethodRules(config.AuthConfig{
+		DefaultMode: "authenticated",
+		Methods: []config.MethodAuthConfig{
+			{Method: iamv1.IAM_GetUser_FullMethodName, Mode: "authenticated"},
+			{Method: "/svc.v1.Orders/Watch", Mode: "authorized", Action: "orders:Watch", Resource: "orders:stream:*"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewMethodRules failed: %v", err)
+	}
+
+	if got := rules.Lookup(iamv1.IAM_GetUser_FullMethodName).Mode; got != ModeAuthenticated {
+		t.Errorf("GetUser mode = %s, want authenticated", got)
+	}
+	watch := rules.Lookup("/svc.v1.Orders/Watch")
+	if watch.Action != "orders:Watch" || watch.ResolveResource(nil) != "orders:stream:*" {
+		t.Errorf("unexpected rule for Watch: %+v", watch)
+	}
+	if got := rules.Lookup("/svc.v1.Orders/List").Mode; got != ModeAuthenticated {
+		t.Errorf("default mode = %s, want authenticated", got)
+	}
+
+	invalid := []config.AuthConfig{
+		{DefaultMode: "open"},
+		{Methods: []config.MethodAuthConfig{{Method: "/svc.v1.Orders/List", Mode: "authorized"}}},
+		{Methods: []config.MethodAuthConfig{{Mode: "public"}}},
+	}
+	for _, cfg := range invalid {
+		if _, err := NewMethodRules(cfg); err == nil {
+			t.Errorf("expected error for %+v", cfg)
+		}
+	}
+}
+
+func TestMethodPermissionResources(t *testing.T) {
+	tests := []struct {
+		method   string
+		req      interface{}
+		action   string
+		resource string
+	}{
+		{iamv1.IAM_CreateUser_FullMethodName, &iamv1.CreateUserRequest{Name: "alice"}, "iam:CreateUser", "iam:user:alice"},
+		{iamv1.IAM_CreatePolicy_FullMethodName, &iamv1.CreatePolicyRequest{Name: "ReadOnly"}, "iam:CreatePolicy", "iam:policy:ReadOnly"},
+		{iamv1.IAM_AttachUserPolicy_FullMethodName, &iamv1.AttachUserPolicyRequest{UserName: "bob"}, "iam:AttachUserPolicy", "iam:user:bob"},
+		{iamv1.IAM_CreateAccessKey_FullMethodName, &iamv1.CreateAccessKeyRequest{UserName: "bob"}, "iam:CreateAccessKey", "iam:user:bob"},
+	}
+	for _, tt := range tests {
+		perm := methodPermissions[tt.method]
+		if perm.action != tt.action {
+			t.Errorf("%s: action = %s, want %s", tt.method, perm.action, tt.action)
+		}
+		if got := perm.resource(tt.req); got != tt.resource {
+			t.Errorf("%s: resource = %s, want %s", tt.method, got, tt.resource)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vera-byte/vgo-iam/internal/service"
+	"github.com/vera-byte/vgo-iam/internal/util"
+)
+
+// BearerScheme OAuth2访问令牌的Authorization方案，格式: Bearer <token>
+const BearerScheme = "Bearer"
+
+// ParseBearerToken 从Authorization头中取出访问令牌，不是Bearer令牌时返回false
+// 方案名按RFC 7235不区分大小写
+func ParseBearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, BearerScheme) {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// verifyBearer 校验令牌端点签发的访问令牌，返回调用者信息，令牌的scope限制可执行的操作
+// 无效或过期的令牌统一返回ErrAuthenticationFailed，并按来源IP计入限流
+func (a *Authenticator) verifyBearer(ctx context.Context, token, sourceIP, operation string) (*Principal, error) {
+	if !a.throttle.Allow("", sourceIP) {
+		logAuthFailure("", sourceIP, operation, "too many failed attempts")
+		return nil, ErrTooManyFailures
+	}
+
+	principal, err := a.tokenService.ValidateToken(ctx, token)
+	if errors.Is(err, service.ErrInvalidToken) {
+		a.throttle.RecordFailure("", sourceIP)
+		logAuthFailure("", sourceIP, operation, "invalid or expired bearer token")
+		return nil, ErrAuthenticationFailed
+	}
+	if err != nil {
+		util.Logger.Error("Failed to validate bearer token", zap.Error(err))
+		return nil, status.Error(codes.Internal, "failed to validate bearer token")
+	}
+
+	return &Principal{
+		Type:        PrincipalUser,
+		UserID:      principal.UserID,
+		UserName:    principal.UserName,
+		Account:     principal.Claims.Account,
+		AccessKeyID: principal.AccessKeyID,
+		AuthMethod:  AuthMethodToken,
+		SourceIP:    sourceIP,
+		Scopes:      strings.Fields(principal.Claims.Scope),
+	}, nil
+}
@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vera-byte/vgo-iam/internal/util"
+	"github.com/vera-byte/vgo-iam/pkg/signer"
+)
+
+// HTTPMiddleware 返回校验IAM-HMAC-SHA256签名的net/http中间件
+// service为凭证范围中要求的服务名，校验通过后调用者信息写入请求上下文，可通过PrincipalFromContext获取
+func (a *Authenticator) HTTPMiddleware(service string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, err := a.authenticateHTTPRequest(r, service)
+			if err != nil {
+				st := status.Convert(err)
+				util.Logger.Warn("HTTP request rejected",
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.String("reason", st.Message()),
+				)
+				http.Error(w, st.Message(), HTTPStatusFromCode(st.Code()))
+				return
+			}
+
+			// 异步记录密钥使用情况
+			a.RecordUsage(principal, r.Method+" "+r.URL.Path)
+
+			next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// authenticateHTTPRequest 读取请求体并校验HTTP请求签名
+func (a *Authenticator) authenticateHTTPRequest(r *http.Request, service string) (*Principal, error) {
+	// 由服务端根据实际收到的请求体计算哈希
+	payloadHash, err := signer.ReadHTTPPayload(r)
+	if errors.Is(err, signer.ErrPayloadHashMismatch) {
+		// 请求体与签名中声明的哈希不一致，视为签名校验失败
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return a.AuthenticateHTTP(r, service, payloadHash, sourceIPFromRemoteAddr(r.RemoteAddr))
+}
+
+// AuthenticateHTTP 校验HTTP请求签名、会话令牌或Bearer访问令牌，返回调用者信息
+// payloadHash为调用方根据实际请求体得到的哈希，sourceIP为可信的来源地址；返回的错误为gRPC status错误
+func (a *Authenticator) AuthenticateHTTP(r *http.Request, service, payloadHash, sourceIP string) (*Principal, error) {
+	if token, ok := ParseSessionToken(r.Header.Get(signer.HeaderAuthorization)); ok {
+		return a.verifySession(r.Context(), token, sourceIP, r.Method+" "+r.URL.Path)
+	}
+	if token, ok := ParseBearerToken(r.Header.Get(signer.HeaderAuthorization)); ok {
+		return a.verifyBearer(r.Context(), token, sourceIP, r.Method+" "+r.URL.Path)
+	}
+	authorization, timestamp, err := signer.ParseRequest(r)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "missing or malformed authorization")
+	}
+
+	canonical, err := signer.HTTPCanonicalRequest(r, authorization.SignedHeaders, payloadHash)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "failed to build canonical request: %v", err)
+	}
+
+	return a.verify(r.Context(), &signedRequest{
+		authorization: authorization,
+		timestamp:     timestamp,
+		nonce:         r.Header.Get(signer.HeaderNonce),
+		service:       service,
+		canonical:     canonical,
+		sourceIP:      sourceIP,
+		operation:     r.Method + " " + r.URL.Path,
+	})
+}
+
+// RecordUsage 异步记录调用者访问密钥的使用情况
+func (a *Authenticator) RecordUsage(p *Principal, operation string) {
+	if p.AccessKeyID != "" {
+		a.akService.RecordUsage(p.AccessKeyID, operation, p.SourceIP)
+	}
+}
+
+// Authorize 校验调用者是否有权限对资源执行操作
+func (a *Authenticator) Authorize(ctx context.Context, p *Principal, action, resource string) error {
+	return a.authorizer.Authorize(ctx, p, action, resource)
+}
+
+// SourceIPFromHTTPRequest 获取HTTP请求的来源IP，见sourceIPFromRemoteAddr
+func SourceIPFromHTTPRequest(r *http.Request) string {
+	return sourceIPFromRemoteAddr(r.RemoteAddr)
+}
+
+// sourceIPFromRemoteAddr 获取HTTP请求的来源IP，不信任X-Forwarded-For等客户端可伪造的头
+func sourceIPFromRemoteAddr(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+// HTTPStatusFromCode 将认证错误的gRPC状态码转换为HTTP状态码
+func HTTPStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.AlreadyExists:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
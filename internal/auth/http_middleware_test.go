@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vera-byte/vgo-iam/pkg/signer"
+)
+
+// signedHTTPRequest 返回以secret签名的HTTP请求
+func signedHTTPRequest(t *testing.T, method, target, body, secret string) *http.Request {
+	t.Helper()
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	if err := signer.SignHTTPRequest(r, testAccessKeyID, secret, "orders", time.Now()); err != nil {
+		t.Fatalf("SignHTTPRequest failed: %v", err)
+	}
+	return r
+}
+
+// serve 通过HTTPMiddleware处理请求，返回响应状态码、next是否执行及其收到的调用者
+func serve(a *Authenticator, r *http.Request) (code int, ran bool, principal *Principal) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ran = true
+		principal, _ = PrincipalFromContext(r.Context())
+	})
+	w := httptest.NewRecorder()
+	a.HTTPMiddleware("orders")(next).ServeHTTP(w, r)
+	return w.Code, ran, principal
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	a, _ := newTestAuthenticator(t, nil)
+
+	// 签名通过时执行next，上下文中带有调用者
+	code, ran, principal := serve(a, signedHTTPRequest(t, "POST", "http://orders.example.com/orders?dry_run=1", `{"item":"book"}`, testSecret))
+	if code != http.StatusOK || !ran {
+		t.Fatalf("expected signed request to reach next, code=%d ran=%v", code, ran)
+	}
+	if principal == nil || principal.UserID != 1 || principal.AccessKeyID != testAccessKeyID {
+		t.Errorf("unexpected principal %+v", principal)
+	}
+
+	// 篡改请求体或查询参数后返回401
+	tampered := signedHTTPRequest(t, "POST", "http://orders.example.com/orders", `{"item":"book"}`, testSecret)
+	tampered.Body = io.NopCloser(strings.NewReader(`{"item":"car"}`))
+	if code, ran, _ := serve(a, tampered); code != http.StatusUnauthorized || ran {
+		t.Errorf("tampered body: code=%d ran=%v, want 401", code, ran)
+	}
+	tampered = signedHTTPRequest(t, "POST", "http://orders.example.com/orders", `{"item":"book"}`, testSecret)
+	tampered.Body = http.NoBody
+	tampered.Header.Del(signer.HeaderContentSHA256)
+	if code, ran, _ := serve(a, tampered); code != http.StatusUnauthorized || ran {
+		t.Errorf("tampered body: code=%d ran=%v, want 401", code, ran)
+	}
+	tampered = signedHTTPRequest(t, "GET", "http://orders.example.com/orders?limit=10", "", testSecret)
+	tampered.URL.RawQuery = "limit=1000"
+	if code, ran, _ := serve(a, tampered); code != http.StatusUnauthorized || ran {
+		t.Errorf("tampered query: code=%d ran=%v, want 401", code, ran)
+	}
+
+	// 中间件保护的所有路径都需要签名，未签名或签名错误的请求返回401
+	if code, ran, _ := serve(a, httptest.NewRequest("GET", "http://orders.example.com/healthz", nil)); code != http.StatusUnauthorized || ran {
+		t.Errorf("unsigned request: code=%d ran=%v, want 401", code, ran)
+	}
+	if code, ran, _ := serve(a, signedHTTPRequest(t, "GET", "http://orders.example.com/orders", "", "wrong")); code != http.StatusUnauthorized || ran {
+		t.Errorf("bad signature: code=%d ran=%v, want 401", code, ran)
+	}
+}
@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/vera-byte/vgo-iam/internal/config"
+	iamv1 "github.com/vera-byte/vgo-iam/pkg/proto"
+)
+
+// AuthMode 方法的认证方式
+type AuthMode string
+
+const (
+	// ModePublic 不需要认证
+	ModePublic AuthMode = "public"
+	// ModeAuthenticated 只需要有效签名
+	ModeAuthenticated AuthMode = "authenticated"
+	// ModeAuthorized 需要有效签名，并且调用者的策略允许执行对应操作
+	ModeAuthorized AuthMode = "authorized"
+)
+
+// anyResource 无法从请求得到资源时使用的资源
+const anyResource = "*"
+
+// MethodRule 单个gRPC方法的认证授权规则
+type MethodRule struct {
+	Mode     AuthMode
+	Action   string                       // ModeAuthorized时校验的操作
+	Resource string                       // 固定资源，为空时由resource根据请求计算
+	resource func(req interface{}) string // 内置的资源计算函数
+}
+
+// ResolveResource 计算本次调用的资源ARN
+// 流式RPC建立连接时没有请求消息，无法计算时使用"*"
+func (r MethodRule) ResolveResource(req interface{}) string {
+	if r.Resource != "" {
+		return r.Resource
+	}
+	if r.resource != nil && req != nil {
+		return r.resource(req)
+	}
+	return anyResource
+}
+
+// MethodRules 按gRPC完整方法名查找认证授权规则
+type MethodRules struct {
+	defaultMode AuthMode
+	rules       map[string]MethodRule
+}
+
+// NewMethodRules 根据配置创建方法规则
+// 内置规则: IAM RPC按methodPermissions授权，keyOwnerMethods由处理函数授权，VerifyAccessKey、VerifyApiKey、ValidateToken、GetJWKS和Authenticate公开，
+// GetSessionToken只需认证；配置中的同名方法覆盖内置规则
+func NewMethodRules(cfg config.AuthConfig) (*MethodRules, error) {
+	r := &MethodRules{
+		defaultMode: ModeAuthorized,
+		rules:       make(map[string]MethodRule, len(methodPermissions)+len(cfg.Methods)+6),
+	}
+	if cfg.DefaultMode != "" {
+		mode, err := parseAuthMode(cfg.DefaultMode)
+		if err != nil {
+			return nil, err
+		}
+		r.defaultMode = mode
+	}
+
+	for method, perm := range methodPermissions {
+		r.rules[method] = MethodRule{Mode: ModeAuthorized, Action: perm.action, resource: perm.resource}
+	}
+	for _, method := range keyOwnerMethods {
+		r.rules[method] = MethodRule{Mode: ModeAuthenticated}
+	}
+	// VerifyAccessKey、VerifyApiKey、ValidateToken、GetJWKS 供下游服务校验签名、API密钥和访问令牌
+	r.rules[iamv1.IAM_VerifyAccessKey_FullMethodName] = MethodRule{Mode: ModePublic}
+	r.rules[iamv1.IAM_VerifyApiKey_FullMethodName] = MethodRule{Mode: ModePublic}
+	r.rules[iamv1.IAM_ValidateToken_FullMethodName] = MethodRule{Mode: ModePublic}
+	r.rules[iamv1.IAM_GetJWKS_FullMethodName] = MethodRule{Mode: ModePublic}
+	// Authenticate 使用密码登录，换取会话令牌
+	r.rules[iamv1.IAM_Authenticate_FullMethodName] = MethodRule{Mode: ModePublic}
+	// GetSessionToken 为调用者自己换取会话令牌，不需要额外授权
+	r.rules[iamv1.IAM_GetSessionToken_FullMethodName] = MethodRule{Mode: ModeAuthenticated}
+
+	for _, m := range cfg.Methods {
+		if m.Method == "" {
+			return nil, fmt.Errorf("auth method rule without method name")
+		}
+		mode, err := parseAuthMode(m.Mode)
+		if err != nil {
+			return nil, fmt.Errorf("method %s: %w", m.Method, err)
+		}
+		rule := r.rules[m.Method]
+		rule.Mode = mode
+		if m.Action != "" {
+			rule.Action = m.Action
+		}
+		if m.Resource != "" {
+			rule.Resource = m.Resource
+		}
+		if rule.Mode == ModeAuthorized && rule.Action == "" {
+			return nil, fmt.Errorf("method %s: authorized mode requires an action", m.Method)
+		}
+		r.rules[m.Method] = rule
+	}
+
+	return r, nil
+}
+
+// Lookup 返回方法的规则，未配置的方法使用默认认证方式
+// 默认方式为authorized时未配置操作，调用会被拒绝
+func (r *MethodRules) Lookup(fullMethod string) MethodRule {
+	if rule, ok := r.rules[fullMethod]; ok {
+		return rule
+	}
+	return MethodRule{Mode: r.defaultMode}
+}
+
+// parseAuthMode 解析配置中的认证方式
+func parseAuthMode(mode string) (AuthMode, error) {
+	switch m := AuthMode(mode); m {
+	case ModePublic, ModeAuthenticated, ModeAuthorized:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown auth mode %q", mode)
+	}
+}
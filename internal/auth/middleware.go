@@ -2,84 +2,282 @@ package auth
 
 import (
 	"context"
+	"net"
 	"time"
 
+	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
-	"github.com/vera-byte/vgo-iam/internal/store"
+	"github.com/vera-byte/vgo-iam/internal/model"
+	"github.com/vera-byte/vgo-iam/internal/service"
+	"github.com/vera-byte/vgo-iam/internal/util"
+	"github.com/vera-byte/vgo-iam/pkg/signer"
 )
 
-// verifyTimestamp 验证时间戳是否在允许范围内
-func verifyTimestamp(timestamp string) bool {
-	// 解析ISO8601时间戳
-	reqTime, err := time.Parse(time.RFC3339, timestamp)
-	if err != nil {
-		return false
-	}
+// Authenticator gRPC访问密钥认证授权
+// 按方法规则决定是否校验签名、会话令牌或Bearer访问令牌以及调用权限，nonceStore用于拒绝携带x-iam-nonce的重放请求，
+// throttle用于限制同一访问密钥ID在同一来源IP、以及同一来源IP的认证失败次数
+type Authenticator struct {
+	akService    *service.AccessKeyService
+	loginService *service.LoginService
+	tokenService *service.TokenService
+	nonceStore   NonceStore
+	authorizer   *Authorizer
+	rules        *MethodRules
+	throttle     *FailureThrottle
+}
 
-	// 检查时间戳是否在允许范围内（±5分钟）
-	now := time.Now()
-	diff := now.Sub(reqTime)
-	if diff.Abs() > 5*time.Minute {
-		return false
+// NewAuthenticator 创建gRPC认证授权器，throttle为nil时不限制认证失败次数
+func NewAuthenticator(akService *service.AccessKeyService, loginService *service.LoginService, tokenService *service.TokenService, nonceStore NonceStore, authorizer *Authorizer, rules *MethodRules, throttle *FailureThrottle) *Authenticator {
+	return &Authenticator{
+		akService:    akService,
+		loginService: loginService,
+		tokenService: tokenService,
+		nonceStore:   nonceStore,
+		authorizer:   authorizer,
+		rules:        rules,
+		throttle:     throttle,
 	}
-	return true
 }
 
-// AccessKeyInterceptor gRPC访问密钥验证拦截器
-func AccessKeyInterceptor(akStore store.AccessKeyStore) grpc.UnaryServerInterceptor {
+// UnaryServerInterceptor 返回一元RPC认证拦截器
+func (a *Authenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		// 排除登录和密钥验证方法
-		if info.FullMethod == "/iam.v1.IAM/VerifyAccessKey" ||
-			info.FullMethod == "/iam.v1.IAM/CreateAccessKey" {
+		principal, err := a.check(ctx, info.FullMethod, req)
+		if err != nil {
+			return nil, err
+		}
+		if principal == nil {
 			return handler(ctx, req)
 		}
 
-		// 从metadata获取访问密钥
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, status.Error(codes.Unauthenticated, "missing metadata")
+		// 将调用者信息添加到上下文，并记录审计日志
+		resp, err := handler(WithPrincipal(ctx, principal), req)
+		auditLog(principal, info.FullMethod, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor 返回流式RPC认证拦截器
+// 签名在建立流时校验，规范请求的请求体为空，后续消息不再单独签名
+func (a *Authenticator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		principal, err := a.check(ss.Context(), info.FullMethod, nil)
+		if err != nil {
+			return err
+		}
+		if principal == nil {
+			return handler(srv, ss)
 		}
 
-		accessKeyID := getFirstValue(md, "access-key-id")
-		signature := getFirstValue(md, "signature")
-		timestamp := getFirstValue(md, "x-iam-date")
-		requestData := getFirstValue(md, "request-data")
+		err = handler(srv, &principalStream{ServerStream: ss, ctx: WithPrincipal(ss.Context(), principal)})
+		auditLog(principal, info.FullMethod, err)
+		return err
+	}
+}
 
-		if accessKeyID == "" || signature == "" || timestamp == "" || requestData == "" {
-			return nil, status.Error(codes.Unauthenticated, "missing authentication parameters")
-		}
+// check 按方法规则认证并授权，公开方法返回nil的Principal
+func (a *Authenticator) check(ctx context.Context, fullMethod string, req interface{}) (*Principal, error) {
+	rule := a.rules.Lookup(fullMethod)
+	if rule.Mode == ModePublic {
+		return nil, nil
+	}
 
-		// 验证时间戳
-		if !verifyTimestamp(timestamp) {
-			return nil, status.Error(codes.Unauthenticated, "invalid or expired timestamp")
+	principal, err := a.authenticate(ctx, fullMethod, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 校验调用者是否有权限执行该操作
+	if rule.Mode == ModeAuthorized {
+		if rule.Action == "" {
+			return nil, status.Errorf(codes.PermissionDenied, "no authorization rule for method %s", fullMethod)
+		}
+		if err := a.authorizer.Authorize(ctx, principal, rule.Action, rule.ResolveResource(req)); err != nil {
+			util.Logger.Warn("RPC denied", append(principal.LogFields(),
+				zap.String("method", fullMethod), zap.Error(err))...)
+			return nil, err
 		}
+	}
+
+	// 异步记录密钥使用情况
+	a.RecordUsage(principal, fullMethod)
+	return principal, nil
+}
+
+// authenticate 从gRPC metadata中解析并校验请求签名、会话令牌或Bearer访问令牌，返回调用者信息
+func (a *Authenticator) authenticate(ctx context.Context, fullMethod string, req interface{}) (*Principal, error) {
+	// 从metadata获取签名信息
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing metadata")
+	}
+
+	header := getFirstValue(md, signer.HeaderAuthorization)
+	if token, ok := ParseSessionToken(header); ok {
+		return a.verifySession(ctx, token, SourceIPFromContext(ctx), fullMethod)
+	}
+	if token, ok := ParseBearerToken(header); ok {
+		return a.verifyBearer(ctx, token, SourceIPFromContext(ctx), fullMethod)
+	}
+	authorization, err := signer.ParseAuthorization(header)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "missing or malformed authorization")
+	}
+
+	// 由服务端重新计算规范请求，请求体哈希基于实际收到的请求消息
+	canonical, err := signer.GRPCCanonicalRequest(fullMethod, md, authorization.SignedHeaders, req)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "failed to build canonical request: %v", err)
+	}
+
+	return a.verify(ctx, &signedRequest{
+		authorization: authorization,
+		timestamp:     getFirstValue(md, signer.HeaderDate),
+		nonce:         getFirstValue(md, signer.HeaderNonce),
+		service:       signer.ServiceName,
+		canonical:     canonical,
+		sourceIP:      SourceIPFromContext(ctx),
+		operation:     fullMethod,
+	})
+}
+
+// signedRequest 与传输方式无关的待校验签名请求
+type signedRequest struct {
+	authorization *signer.Authorization
+	timestamp     string // x-iam-date
+	nonce         string // x-iam-nonce，可为空
+	service       string // 凭证范围中要求的服务名
+	canonical     *signer.CanonicalRequest
+	sourceIP      string
+	operation     string // gRPC完整方法名或HTTP方法和路径，仅用于日志
+}
 
-		// 验证访问密钥
-		ak, err := akStore.GetByAccessKeyID(accessKeyID)
+// verify 校验时间戳、凭证范围、签名、访问密钥状态和nonce，返回调用者信息
+// 返回的错误为gRPC status错误；与访问密钥相关的失败统一返回ErrAuthenticationFailed并计入限流
+func (a *Authenticator) verify(ctx context.Context, r *signedRequest) (*Principal, error) {
+	authorization := r.authorization
+	if !authorization.HasSignedHeader(signer.HeaderDate) {
+		return nil, status.Error(codes.Unauthenticated, "x-iam-date must be signed")
+	}
+	if r.nonce != "" && !authorization.HasSignedHeader(signer.HeaderNonce) {
+		return nil, status.Error(codes.Unauthenticated, "x-iam-nonce must be signed")
+	}
+	if !validNonce(r.nonce) {
+		return nil, status.Error(codes.Unauthenticated, "malformed x-iam-nonce")
+	}
+	accessKeyID := authorization.AccessKeyID
+
+	// 验证时间戳和凭证范围
+	if err := signer.ValidateTimestamp(r.timestamp, time.Now()); err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired timestamp")
+	}
+	if err := authorization.Scope.Validate(r.timestamp, r.service); err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid credential scope")
+	}
+
+	if !a.throttle.Allow(accessKeyID, r.sourceIP) {
+		logAuthFailure(accessKeyID, r.sourceIP, r.operation, "too many failed attempts")
+		return nil, ErrTooManyFailures
+	}
+
+	// 验证访问密钥，API密钥只保存哈希，无法用于签名
+	ak, err := a.akService.GetAccessKey(ctx, accessKeyID)
+	if err != nil {
+		return nil, a.fail(r, "unknown access key")
+	}
+	if ak.CredentialType == model.CredentialTypeAPIKey {
+		return nil, a.fail(r, "api keys cannot sign requests")
+	}
+
+	// 解密密钥并验证签名，签名通过后再检查状态，避免未持有密钥的调用方得知密钥状态
+	secrets, err := a.akService.ResolveSecrets(ctx, accessKeyID)
+	if err != nil {
+		util.Logger.Error("Failed to resolve access key secret", zap.String("access_key_id", accessKeyID), zap.Error(err))
+		return nil, status.Error(codes.Internal, "failed to resolve access key secret")
+	}
+	if !signer.VerifySignature(authorization, r.timestamp, r.canonical.String(), secrets) {
+		return nil, a.fail(r, "signature mismatch")
+	}
+	if ak.Status != "active" {
+		return nil, a.fail(r, "access key is inactive")
+	}
+	if ak.IsExpired(time.Now()) {
+		return nil, a.fail(r, "access key has expired")
+	}
+	a.throttle.Reset(accessKeyID, r.sourceIP)
+
+	// 签名通过后记录nonce，重复的nonce视为重放请求
+	if r.nonce != "" {
+		fresh, err := a.nonceStore.CheckAndStore(ctx, accessKeyID+":"+r.nonce, nonceExpiry(r.timestamp))
 		if err != nil {
-			return nil, status.Error(codes.Unauthenticated, "invalid access key")
+			return nil, status.Error(codes.Internal, "failed to check request nonce")
 		}
-
-		// 验证密钥状态
-		if ak.Status != "active" {
-			return nil, status.Error(codes.PermissionDenied, "access key is inactive")
+		if !fresh {
+			return nil, status.Error(codes.AlreadyExists, "request replayed: nonce already used")
 		}
+	}
 
-		// 验证签名
-		valid, err := VerifySignatureV4(signature, requestData, timestamp, ak.SecretAccessKey)
-		if err != nil || !valid {
-			return nil, status.Error(codes.Unauthenticated, "signature verification failed")
-		}
+	return &Principal{
+		Type:        PrincipalUser,
+		UserID:      ak.UserID,
+		UserName:    ak.UserName,
+		Account:     DefaultAccount,
+		AccessKeyID: accessKeyID,
+		AuthMethod:  AuthMethodAccessKey,
+		SourceIP:    r.sourceIP,
+	}, nil
+}
+
+// fail 记录认证失败原因并计入限流，返回统一的对外错误
+func (a *Authenticator) fail(r *signedRequest, reason string) error {
+	a.throttle.RecordFailure(r.authorization.AccessKeyID, r.sourceIP)
+	logAuthFailure(r.authorization.AccessKeyID, r.sourceIP, r.operation, reason)
+	return ErrAuthenticationFailed
+}
 
-		// 将用户信息添加到上下文
-		ctx = context.WithValue(ctx, "user_id", ak.UserID)
+// logAuthFailure 记录认证失败的审计日志，失败原因只写入日志，不返回给调用方
+func logAuthFailure(accessKeyID, sourceIP, operation, reason string) {
+	util.Logger.Warn("Authentication failed",
+		zap.String("access_key_id", accessKeyID),
+		zap.String("source_ip", sourceIP),
+		zap.String("operation", operation),
+		zap.String("reason", reason),
+	)
+}
 
-		return handler(ctx, req)
+// auditLog 记录认证调用的审计日志
+func auditLog(p *Principal, fullMethod string, err error) {
+	util.Logger.Info("RPC audit", append(p.LogFields(),
+		zap.String("method", fullMethod), zap.String("code", status.Code(err).String()))...)
+}
+
+// principalStream 携带调用者信息的ServerStream
+type principalStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context 返回包含调用者信息的上下文
+func (s *principalStream) Context() context.Context {
+	return s.ctx
+}
+
+// SourceIPFromContext 获取gRPC请求的来源IP
+func SourceIPFromContext(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
 	}
+	return host
 }
 
 func getFirstValue(md metadata.MD, key string) string {
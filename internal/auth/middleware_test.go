@@ -0,0 +1,237 @@
+package auth
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/crypto"
+	"github.com/vera-byte/vgo-iam/internal/model"
+	"github.com/vera-byte/vgo-iam/internal/policy"
+	"github.com/vera-byte/vgo-iam/internal/service"
+	"github.com/vera-byte/vgo-iam/internal/store"
+	"github.com/vera-byte/vgo-iam/internal/util"
+	iamv1 "github.com/vera-byte/vgo-iam/pkg/proto"
+	"github.com/vera-byte/vgo-iam/pkg/signer"
+)
+
+const (
+	testAccessKeyID = "AKIDEXAMPLE"
+	testSecret      = "secret"
+)
+
+// singleKeyStore 只保存一个签名凭证的访问密钥存储
+type singleKeyStore struct {
+	store.AccessKeyStore
+	ak *model.AccessKey
+}
+
+func (f *singleKeyStore) GetByAccessKeyID(accessKeyID string) (*model.AccessKey, error) {
+	copied := *f.ak
+	return &copied, nil
+}
+
+func (f *singleKeyStore) GetEncryptedSecrets(accessKeyID string) (*model.AccessKeySecrets, error) {
+	return &model.AccessKeySecrets{UserID: f.ak.UserID, Version: f.ak.SecretVersion, Current: f.ak.EncryptedSecretKey}, nil
+}
+
+// singleUserStore 所有用户ID都对应alice，附加的策略由policies给出
+type singleUserStore struct {
+	store.UserStore
+	policies []*model.Policy
+}
+
+func (singleUserStore) GetByID(id int) (*model.User, error) {
+	return &model.User{ID: id, Name: "alice"}, nil
+}
+
+func (s singleUserStore) GetUserPolicies(ctx context.Context, userID int) ([]*model.Policy, error) {
+	return s.policies, nil
+}
+
+// newTestAuthenticator 创建使用alice访问密钥和内置方法规则的认证授权器，alice附加policyDocuments中的策略
+func newTestAuthenticator(t *testing.T, throttle *FailureThrottle, policyDocuments ...string) (*Authenticator, *singleKeyStore) {
+	t.Helper()
+	util.Logger = zap.NewNop()
+	local := crypto.NewLocalKeyring("test")
+	if err := local.AddKey("test", []byte("0123456789abcdef0123456789abcdef")); err != nil {
+		t.Fatalf("failed to add master key: %v", err)
+	}
+	keyring := crypto.NewKeyRotationManager(local)
+
+	ak := model.NewAccessKey(1, testAccessKeyID, testSecret, time.Hour)
+	encrypted, err := keyring.EncryptWithCurrentKey(context.Background(), []byte(testSecret), service.SecretAAD(ak.AccessKeyID, ak.UserID, ak.SecretVersion))
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	ak.EncryptedSecretKey = encrypted
+	akStore := &singleKeyStore{ak: ak}
+
+	users := singleUserStore{}
+	for i, doc := range policyDocuments {
+		users.policies = append(users.policies, &model.Policy{ID: i + 1, PolicyDocument: doc})
+	}
+	rules, err := NewMethodRules(config.AuthConfig{})
+	if err != nil {
+		t.Fatalf("NewMethodRules failed: %v", err)
+	}
+	authorizer := NewAuthorizer(service.NewUserService(users, nil), policy.NewPolicyEngine(users))
+	a := NewAuthenticator(service.NewAccessKeyService(akStore, users, keyring, config.AccessKeyConfig{}),
+		nil, nil, NewMemoryNonceStore(), authorizer, rules, throttle)
+	return a, akStore
+}
+
+// signedContext 返回携带签名的incoming context，模拟服务端收到的请求
+func signedContext(t *testing.T, method string, req interface{}, secret string, extraHeaders map[string]string) context.Context {
+	t.Helper()
+	ctx, err := signer.SignGRPCRequest(context.Background(), method, req, testAccessKeyID, secret, time.Now(), extraHeaders)
+	if err != nil {
+		t.Fatalf("SignGRPCRequest failed: %v", err)
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+// invoke 通过一元拦截器调用方法，返回处理函数是否执行及其收到的调用者
+func invoke(a *Authenticator, ctx context.Context, method string, req interface{}) (ran bool, principal *Principal, err error) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		ran = true
+		principal, _ = PrincipalFromContext(ctx)
+		return nil, nil
+	}
+	_, err = a.UnaryServerInterceptor()(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	return ran, principal, err
+}
+
+func TestAuthenticateRejectsExpiredKey(t *testing.T) {
+	a, akStore := newTestAuthenticator(t, nil)
+	method := iamv1.IAM_GetUser_FullMethodName
+	req := &iamv1.GetUserRequest{Name: "alice"}
+
+	principal, err := a.authenticate(signedContext(t, method, req, testSecret, nil), method, req)
+	if err != nil || principal.AccessKeyID != testAccessKeyID {
+		t.Fatalf("authenticate = %+v, %v", principal, err)
+	}
+
+	expired := time.Now().Add(-time.Second)
+	akStore.ak.ExpiresAt = &expired
+	if _, err := a.authenticate(signedContext(t, method, req, testSecret, nil), method, req); err != ErrAuthenticationFailed {
+		t.Errorf("expected ErrAuthenticationFailed for expired key, got %v", err)
+	}
+}
+
+func TestInterceptorAuthorization(t *testing.T) {
+	a, _ := newTestAuthenticator(t, nil,
+		`{"Version":"1","Statement":[{"Effect":"Allow","Action":["iam:GetUser"],"Resource":["iam:user:bob"]}]}`)
+	method := iamv1.IAM_GetUser_FullMethodName
+
+	// 策略允许时处理函数执行，上下文中带有调用者
+	req := &iamv1.GetUserRequest{Name: "bob"}
+	ran, principal, err := invoke(a, signedContext(t, method, req, testSecret, nil), method, req)
+	if err != nil || !ran {
+		t.Fatalf("expected allowed call to reach the handler, ran=%v err=%v", ran, err)
+	}
+	if principal == nil || principal.UserID != 1 || principal.AccessKeyID != testAccessKeyID {
+		t.Errorf("unexpected principal %+v", principal)
+	}
+
+	// 没有匹配的策略时拒绝，错误信息包含操作名
+	req = &iamv1.GetUserRequest{Name: "carol"}
+	ran, _, err = invoke(a, signedContext(t, method, req, testSecret, nil), method, req)
+	if ran || status.Code(err) != codes.PermissionDenied || !strings.Contains(err.Error(), "iam:GetUser") {
+		t.Errorf("expected PermissionDenied naming iam:GetUser, ran=%v err=%v", ran, err)
+	}
+	createReq := &iamv1.CreateUserRequest{Name: "bob"}
+	ran, _, err = invoke(a, signedContext(t, iamv1.IAM_CreateUser_FullMethodName, createReq, testSecret, nil), iamv1.IAM_CreateUser_FullMethodName, createReq)
+	if ran || status.Code(err) != codes.PermissionDenied || !strings.Contains(err.Error(), "iam:CreateUser") {
+		t.Errorf("expected PermissionDenied naming iam:CreateUser, ran=%v err=%v", ran, err)
+	}
+
+	// 公开方法不需要签名，上下文中没有调用者
+	ran, principal, err = invoke(a, context.Background(), iamv1.IAM_GetJWKS_FullMethodName, &iamv1.GetJWKSRequest{})
+	if err != nil || !ran || principal != nil {
+		t.Errorf("expected public method to run without principal, ran=%v principal=%+v err=%v", ran, principal, err)
+	}
+}
+
+func TestInterceptorNonce(t *testing.T) {
+	a, _ := newTestAuthenticator(t, nil,
+		`{"Version":"1","Statement":[{"Effect":"Allow","Action":["iam:GetUser"],"Resource":["iam:user:bob"]}]}`)
+	method := iamv1.IAM_GetUser_FullMethodName
+	req := &iamv1.GetUserRequest{Name: "bob"}
+
+	// 同一签名请求重放时返回AlreadyExists
+	ctx := signedContext(t, method, req, testSecret, map[string]string{signer.HeaderNonce: "6f1c2a0e-5b7d-4c1e-9a3f-2d8e7b6c5a41"})
+	if ran, _, err := invoke(a, ctx, method, req); err != nil || !ran {
+		t.Fatalf("expected first call to succeed, ran=%v err=%v", ran, err)
+	}
+	if ran, _, err := invoke(a, ctx, method, req); ran || status.Code(err) != codes.AlreadyExists {
+		t.Errorf("expected AlreadyExists for replayed request, ran=%v err=%v", ran, err)
+	}
+
+	// 过长或包含非法字符的nonce在记录前拒绝
+	for _, nonce := range []string{strings.Repeat("a", maxNonceLength+1), "nonce with spaces", "nonce:1"} {
+		ctx := signedContext(t, method, req, testSecret, map[string]string{signer.HeaderNonce: nonce})
+		if ran, _, err := invoke(a, ctx, method, req); ran || status.Code(err) != codes.Unauthenticated {
+			t.Errorf("nonce %q: expected Unauthenticated, ran=%v err=%v", nonce, ran, err)
+		}
+	}
+}
+
+func TestThrottlePerSourceIP(t *testing.T) {
+	a, _ := newTestAuthenticator(t, NewFailureThrottle(config.ThrottleConfig{MaxFailuresPerKey: 2, MaxFailuresPerIP: 100, Window: time.Minute}))
+	method := iamv1.IAM_GetUser_FullMethodName
+	req := &iamv1.GetUserRequest{Name: "alice"}
+	fromIP := func(ctx context.Context, ip string) context.Context {
+		return peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 40000}})
+	}
+
+	// 攻击者用错误的签名使自己的IP被限流
+	for i := 0; i < 2; i++ {
+		if _, err := a.authenticate(fromIP(signedContext(t, method, req, "wrong", nil), "203.0.113.9"), method, req); err != ErrAuthenticationFailed {
+			t.Fatalf("expected ErrAuthenticationFailed, got %v", err)
+		}
+	}
+	if _, err := a.authenticate(fromIP(signedContext(t, method, req, testSecret, nil), "203.0.113.9"), method, req); err != ErrTooManyFailures {
+		t.Errorf("expected throttled IP to get ErrTooManyFailures, got %v", err)
+	}
+
+	// 密钥持有者从其他IP仍可认证
+	if _, err := a.authenticate(fromIP(signedContext(t, method, req, testSecret, nil), "10.0.0.1"), method, req); err != nil {
+		t.Errorf("expected key holder from another IP to authenticate, got %v", err)
+	}
+}
+
+func TestAuthorizeTokenScope(t *testing.T) {
+	a, _ := newTestAuthenticator(t, nil,
+		`{"Version":"1","Statement":[{"Effect":"Allow","Action":["iam:*"],"Resource":["*"]}]}`)
+	token := func(scopes ...string) *Principal {
+		return &Principal{Type: PrincipalUser, UserID: 1, UserName: "alice", AuthMethod: AuthMethodToken, Scopes: scopes}
+	}
+
+	// 不带scope的令牌只受策略限制
+	if err := a.Authorize(context.Background(), token(), "iam:CreateUser", "iam:user:bob"); err != nil {
+		t.Errorf("expected token without scope to be allowed by policy, got %v", err)
+	}
+	// scope按策略Action的规则匹配
+	if err := a.Authorize(context.Background(), token("orders.read", "iam:GetUser"), "iam:GetUser", "iam:user:bob"); err != nil {
+		t.Errorf("expected scope iam:GetUser to allow iam:GetUser, got %v", err)
+	}
+	if err := a.Authorize(context.Background(), token("iam:*"), "iam:CreateUser", "iam:user:bob"); err != nil {
+		t.Errorf("expected scope iam:* to allow iam:CreateUser, got %v", err)
+	}
+	err := a.Authorize(context.Background(), token("orders.read", "iam:GetUser"), "iam:CreateUser", "iam:user:bob")
+	if status.Code(err) != codes.PermissionDenied || !strings.Contains(err.Error(), "iam:CreateUser") {
+		t.Errorf("expected PermissionDenied for action outside token scope, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/vera-byte/vgo-iam/internal/util"
+	"github.com/vera-byte/vgo-iam/pkg/signer"
+)
+
+// NonceStore 记录已使用的nonce，用于拒绝重放请求
+// nonce只需保留到对应时间戳超出允许的时钟偏差范围，之后的重放会被时间戳校验拒绝
+type NonceStore interface {
+	// CheckAndStore 原子地记录nonce，nonce在有效期内已存在时返回false
+	CheckAndStore(ctx context.Context, key string, expiresAt time.Time) (bool, error)
+	// DeleteExpired 清理已过期的nonce，返回清理数量
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+// maxNonceLength nonce的最大长度，UUID为36个字符；nonce与访问密钥ID拼接后存入request_nonces.nonce_key(VARCHAR(255))
+const maxNonceLength = 64
+
+// validNonce nonce长度不超过maxNonceLength，只能包含字母、数字和 - _ .
+func validNonce(nonce string) bool {
+	if len(nonce) > maxNonceLength {
+		return false
+	}
+	for _, c := range nonce {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// nonceExpiry 计算nonce需要保留到的时间
+func nonceExpiry(timestamp string) time.Time {
+	t, _ := signer.ParseTimestamp(timestamp)
+	return t.Add(signer.MaxClockSkew)
+}
+
+// MemoryNonceStore 内存nonce存储，适用于单实例部署
+type MemoryNonceStore struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time
+}
+
+// NewMemoryNonceStore 创建内存nonce存储
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{nonces: make(map[string]time.Time)}
+}
+
+// CheckAndStore 记录nonce，未过期的重复nonce返回false
+func (s *MemoryNonceStore) CheckAndStore(ctx context.Context, key string, expiresAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.nonces[key]; ok && time.Now().Before(existing) {
+		return false, nil
+	}
+	s.nonces[key] = expiresAt
+	return true, nil
+}
+
+// DeleteExpired 清理已过期的nonce
+func (s *MemoryNonceStore) DeleteExpired(now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for key, expiresAt := range s.nonces {
+		if !now.Before(expiresAt) {
+			delete(s.nonces, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// RunNonceCleanup 周期性清理过期nonce，直到ctx取消
+func RunNonceCleanup(ctx context.Context, store NonceStore, interval time.Duration) {
+	if interval <= 0 {
+		interval = signer.MaxClockSkew
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := store.DeleteExpired(time.Now()); err != nil {
+				util.Logger.Warn("Failed to delete expired nonces", zap.Error(err))
+			}
+		}
+	}
+}
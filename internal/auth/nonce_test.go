@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryNonceStore(t *testing.T) {
+	store := NewMemoryNonceStore()
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Minute)
+
+	if fresh, _ := store.CheckAndStore(ctx, "AKID:n1", expiresAt); !fresh {
+		t.Fatal("first use of nonce rejected")
+	}
+	if fresh, _ := store.CheckAndStore(ctx, "AKID:n1", expiresAt); fresh {
+		t.Fatal("replayed nonce accepted")
+	}
+	if fresh, _ := store.CheckAndStore(ctx, "OTHER:n1", expiresAt); !fresh {
+		t.Fatal("same nonce for another access key rejected")
+	}
+
+	if deleted, _ := store.DeleteExpired(expiresAt); deleted != 2 {
+		t.Errorf("expected 2 expired nonces, deleted %d", deleted)
+	}
+	if fresh, _ := store.CheckAndStore(ctx, "AKID:n1", time.Now().Add(time.Minute)); !fresh {
+		t.Error("nonce rejected after expiry")
+	}
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/vera-byte/vgo-iam/internal/model"
+	"github.com/vera-byte/vgo-iam/pkg/authz"
+)
+
+// PrincipalType 调用者类型
+type PrincipalType string
+
+const (
+	// PrincipalUser IAM用户
+	PrincipalUser PrincipalType = "user"
+	// PrincipalRole 角色
+	PrincipalRole PrincipalType = "role"
+	// PrincipalSession 临时会话
+	PrincipalSession PrincipalType = "session"
+)
+
+const (
+	// AuthMethodAccessKey 访问密钥签名认证
+	AuthMethodAccessKey = "access_key"
+	// AuthMethodSession 控制台登录会话令牌认证
+	AuthMethodSession = "session"
+	// AuthMethodToken 令牌端点签发的Bearer访问令牌认证
+	AuthMethodToken = "token"
+
+	// DefaultAccount 单账户部署时使用的账户标识
+	DefaultAccount = model.DefaultAccount
+)
+
+// Principal 经过认证的调用者
+type Principal struct {
+	Type        PrincipalType
+	UserID      int
+	UserName    string
+	Account     string
+	AccessKeyID string // 使用访问密钥认证时的密钥ID
+	AuthMethod  string
+	SourceIP    string
+	MFAPresent  bool     // 会话创建时是否通过了MFA认证
+	Scopes      []string // Bearer访问令牌的scope，为空时不限制操作
+}
+
+// ARN 返回调用者的资源ARN
+func (p *Principal) ARN() string {
+	return UserARN(p.UserName)
+}
+
+// AllowsAction 调用者的凭证是否允许执行操作
+// Bearer访问令牌带有scope时，操作必须匹配其中之一（与策略的Action相同，支持 "*" 和 "iam:*"）；其他凭证只受策略限制
+func (p *Principal) AllowsAction(action string) bool {
+	if len(p.Scopes) == 0 {
+		return true
+	}
+	return authz.MatchAction(p.Scopes, action)
+}
+
+// ConditionContext 返回策略条件使用的请求上下文
+func (p *Principal) ConditionContext() authz.Context {
+	return authz.Context{authz.ContextKeyMFAPresent: strconv.FormatBool(p.MFAPresent)}
+}
+
+// LogFields 返回用于日志和审计记录的字段
+func (p *Principal) LogFields() []zap.Field {
+	return []zap.Field{
+		zap.String("principal", p.ARN()),
+		zap.String("principal_type", string(p.Type)),
+		zap.String("account", p.Account),
+		zap.String("access_key_id", p.AccessKeyID),
+		zap.String("auth_method", p.AuthMethod),
+		zap.String("source_ip", p.SourceIP),
+		zap.Bool("mfa_present", p.MFAPresent),
+	}
+}
+
+// principalKey 上下文中保存Principal的键
+type principalKey struct{}
+
+// WithPrincipal 将调用者信息写入上下文
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext 从上下文获取调用者信息，未经认证的请求返回false
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
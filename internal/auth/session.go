@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vera-byte/vgo-iam/internal/service"
+	"github.com/vera-byte/vgo-iam/internal/util"
+)
+
+// SessionScheme 会话令牌的Authorization方案，格式: IAM-Session <token>
+const SessionScheme = "IAM-Session"
+
+// ParseSessionToken 从Authorization头中取出会话令牌，不是会话令牌时返回false
+func ParseSessionToken(header string) (string, bool) {
+	token, ok := strings.CutPrefix(header, SessionScheme+" ")
+	if !ok {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// verifySession 校验Authenticate返回的会话令牌，返回调用者信息
+// 无效或过期的令牌统一返回ErrAuthenticationFailed，并按来源IP计入限流
+func (a *Authenticator) verifySession(ctx context.Context, token, sourceIP, operation string) (*Principal, error) {
+	if !a.throttle.Allow("", sourceIP) {
+		logAuthFailure("", sourceIP, operation, "too many failed attempts")
+		return nil, ErrTooManyFailures
+	}
+
+	session, err := a.loginService.ValidateSession(ctx, token)
+	if errors.Is(err, service.ErrInvalidSession) {
+		a.throttle.RecordFailure("", sourceIP)
+		logAuthFailure("", sourceIP, operation, "invalid or expired session")
+		return nil, ErrAuthenticationFailed
+	}
+	if err != nil {
+		util.Logger.Error("Failed to validate session", zap.Error(err))
+		return nil, status.Error(codes.Internal, "failed to validate session")
+	}
+
+	return &Principal{
+		Type:       PrincipalSession,
+		UserID:     session.UserID,
+		UserName:   session.UserName,
+		Account:    DefaultAccount,
+		AuthMethod: AuthMethodSession,
+		SourceIP:   sourceIP,
+		MFAPresent: session.MFAPresent,
+	}, nil
+}
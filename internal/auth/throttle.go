@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vera-byte/vgo-iam/internal/config"
+)
+
+var (
+	// ErrAuthenticationFailed 认证失败的统一对外错误
+	// 访问密钥不存在、未激活、已过期或签名错误都返回该错误，具体原因只记录在日志中，避免调用方借此枚举访问密钥
+	ErrAuthenticationFailed = status.Error(codes.Unauthenticated, "authentication failed")
+	// ErrTooManyFailures 认证失败次数过多，窗口结束前拒绝认证
+	ErrTooManyFailures = status.Error(codes.ResourceExhausted, "too many failed authentication attempts, try again later")
+)
+
+// defaultThrottleWindow 未配置统计窗口时使用的窗口
+const defaultThrottleWindow = 5 * time.Minute
+
+// FailureThrottle 按访问密钥ID加来源IP、以及来源IP统计认证失败次数
+// 访问密钥ID的计数区分来源IP，其他来源的失败不会让密钥持有者无法认证；没有来源IP时只按访问密钥ID计数
+// 窗口从第一次失败开始计算，失败次数达到上限后窗口结束前的认证请求直接被拒绝；
+// 不存在的访问密钥ID同样计数，限流结果不会暴露访问密钥是否存在。nil表示不限流
+// 签名和令牌无法靠重试猜出，计数只保存在本副本内存中，不像service.LockoutService那样写数据库，见docs/lockout.md
+type FailureThrottle struct {
+	maxPerKey int
+	maxPerIP  int
+	window    time.Duration
+	failures  *cache.Cache
+}
+
+// NewFailureThrottle 根据配置创建认证失败限流器，两个上限都为0时返回nil
+func NewFailureThrottle(cfg config.ThrottleConfig) *FailureThrottle {
+	if cfg.MaxFailuresPerKey <= 0 && cfg.MaxFailuresPerIP <= 0 {
+		return nil
+	}
+	window := cfg.Window
+	if window <= 0 {
+		window = defaultThrottleWindow
+	}
+	return &FailureThrottle{
+		maxPerKey: cfg.MaxFailuresPerKey,
+		maxPerIP:  cfg.MaxFailuresPerIP,
+		window:    window,
+		failures:  cache.New(window, window),
+	}
+}
+
+// Allow 判断访问密钥ID和来源IP是否仍允许认证，为空的一项不检查
+func (t *FailureThrottle) Allow(accessKeyID, sourceIP string) bool {
+	if t == nil {
+		return true
+	}
+	return !t.exceeded(keyCounter(accessKeyID, sourceIP), t.maxPerKey) && !t.exceeded(ipCounter(sourceIP), t.maxPerIP)
+}
+
+// RecordFailure 记录一次认证失败，为空的一项不计数
+func (t *FailureThrottle) RecordFailure(accessKeyID, sourceIP string) {
+	if t == nil {
+		return
+	}
+	t.increment(keyCounter(accessKeyID, sourceIP))
+	t.increment(ipCounter(sourceIP))
+}
+
+// Reset 认证成功后清除访问密钥ID在该来源IP的失败计数，来源IP的计数保留到窗口结束
+func (t *FailureThrottle) Reset(accessKeyID, sourceIP string) {
+	if t == nil || accessKeyID == "" {
+		return
+	}
+	t.failures.Delete(keyCounter(accessKeyID, sourceIP))
+}
+
+func (t *FailureThrottle) exceeded(counter string, max int) bool {
+	if counter == "" || max <= 0 {
+		return false
+	}
+	count, found := t.failures.Get(counter)
+	return found && count.(int) >= max
+}
+
+func (t *FailureThrottle) increment(counter string) {
+	if counter == "" {
+		return
+	}
+	if err := t.failures.Add(counter, 1, t.window); err != nil {
+		_, _ = t.failures.IncrementInt(counter, 1)
+	}
+}
+
+func keyCounter(accessKeyID, sourceIP string) string {
+	if accessKeyID == "" {
+		return ""
+	}
+	if sourceIP == "" {
+		return "key:" + accessKeyID
+	}
+	return "key:" + accessKeyID + "@" + sourceIP
+}
+
+func ipCounter(sourceIP string) string {
+	if sourceIP == "" {
+		return ""
+	}
+	return "ip:" + sourceIP
+}
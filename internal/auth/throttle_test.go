@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vera-byte/vgo-iam/internal/config"
+)
+
+func TestFailureThrottle(t *testing.T) {
+	throttle := NewFailureThrottle(config.ThrottleConfig{MaxFailuresPerKey: 2, MaxFailuresPerIP: 3, Window: time.Minute})
+
+	throttle.RecordFailure("AKID", "10.0.0.1")
+	if !throttle.Allow("AKID", "10.0.0.1") {
+		t.Fatal("expected one failure to be allowed")
+	}
+	throttle.RecordFailure("AKID", "10.0.0.1")
+	if throttle.Allow("AKID", "10.0.0.1") {
+		t.Error("expected access key to be throttled from the failing IP")
+	}
+	// 访问密钥的计数按来源IP区分，其他来源仍可认证
+	if !throttle.Allow("AKID", "10.0.0.2") {
+		t.Error("expected access key to be allowed from another IP")
+	}
+	if !throttle.Allow("OTHER", "10.0.0.1") {
+		t.Error("expected other access key from the same IP to be allowed")
+	}
+
+	throttle.RecordFailure("OTHER", "10.0.0.1")
+	if throttle.Allow("NEW", "10.0.0.1") {
+		t.Error("expected source IP to be throttled")
+	}
+
+	// 认证成功只清除该来源IP下访问密钥的计数
+	throttle.RecordFailure("AKID", "10.0.0.2")
+	throttle.Reset("AKID", "10.0.0.2")
+	throttle.RecordFailure("AKID", "10.0.0.2")
+	if !throttle.Allow("AKID", "10.0.0.2") {
+		t.Error("expected access key to be allowed after reset")
+	}
+	if throttle.Allow("AKID", "10.0.0.1") {
+		t.Error("expected source IP to stay throttled after reset")
+	}
+
+	// 没有来源IP时只按访问密钥ID计数
+	throttle.RecordFailure("VERIFY", "")
+	throttle.RecordFailure("VERIFY", "")
+	if throttle.Allow("VERIFY", "") {
+		t.Error("expected access key without source IP to be throttled")
+	}
+}
+
+func TestFailureThrottleDisabled(t *testing.T) {
+	throttle := NewFailureThrottle(config.ThrottleConfig{})
+	if throttle != nil {
+		t.Fatal("expected nil throttle when no limit is configured")
+	}
+	throttle.RecordFailure("AKID", "10.0.0.1")
+	if !throttle.Allow("AKID", "10.0.0.1") {
+		t.Error("nil throttle should allow every request")
+	}
+}
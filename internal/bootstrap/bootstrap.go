@@ -1,12 +1,17 @@
 package bootstrap
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"os"
+	"time"
 
 	"github.com/gocraft/dbr/v2"
 	"github.com/vera-byte/vgo-iam/internal/api"
+	"github.com/vera-byte/vgo-iam/internal/auth"
 	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/crypto"
 	"github.com/vera-byte/vgo-iam/internal/policy"
 	"github.com/vera-byte/vgo-iam/internal/service"
 	"github.com/vera-byte/vgo-iam/internal/store"
@@ -44,25 +49,127 @@ func InitServices(cfg *config.AppConfig) (*api.IAMServer, *dbr.Session) {
 	userStore := store.NewUserStore(sess.Session)
 	policyStore := store.NewPolicyStore(sess.Session)
 	accessKeyStore := store.NewAccessKeyStore(sess.Session)
+	loginProfileStore := store.NewLoginProfileStore(sess.Session)
+	sessionStore := store.NewSessionStore(sess.Session)
+	mfaDeviceStore := store.NewMFADeviceStore(sess.Session)
+	authFailureStore := store.NewAuthFailureStore(sess.Session)
+	signingKeyStore := store.NewSigningKeyStore(sess.Session)
+
+	// 初始化主密钥
+	keyring, err := NewKeyring(cfg)
+	if err != nil {
+		util.Logger.Error("failed to load master keys", zap.Error(err))
+		panic(err)
+	}
 
 	// 初始化服务层
 	userService := service.NewUserService(userStore, policyStore)
 	policyService := service.NewPolicyService(policyStore)
-	accessKeyService := service.NewAccessKeyService(accessKeyStore, userStore, []byte(cfg.Security.MasterKey))
+	accessKeyService := service.NewAccessKeyService(accessKeyStore, userStore, keyring, cfg.AccessKey)
+	mfaService := service.NewMFAService(userStore, mfaDeviceStore, keyring, cfg.Login.MFA)
+	loginService := service.NewLoginService(userStore, loginProfileStore, sessionStore, mfaService, cfg.Login)
+	lockoutService := service.NewLockoutService(authFailureStore, cfg.Auth.Lockout)
+	tokenService := service.NewTokenService(accessKeyService, signingKeyStore, keyring, cfg.Token)
+	if err := tokenService.RotateSigningKeys(context.Background()); err != nil {
+		util.Logger.Error("failed to load token signing keys", zap.Error(err))
+		panic(err)
+	}
 	policyEngine := policy.NewPolicyEngine(userService)
 
+	// 首次启动时创建根管理员
+	bootstrapService := service.NewBootstrapService(store.NewBootstrapStore(sess.Session), keyring, cfg.Bootstrap)
+	initRootAdmin(cfg, bootstrapService)
+
 	// 初始化API层
 	server := api.NewIAMServer(
 		userService,
 		policyService,
 		accessKeyService,
+		loginService,
+		mfaService,
+		lockoutService,
+		tokenService,
 		policyEngine,
-		[]byte(cfg.Security.MasterKey),
+		auth.NewFailureThrottle(cfg.Auth.Throttle),
 	)
 
 	return server, sess.Session
 }
 
+// NewKeyring 根据配置创建主密钥环，并确认主密钥可以正常加解密
+func NewKeyring(cfg *config.AppConfig) (*crypto.KeyRotationManager, error) {
+	provider, err := crypto.NewKeyProvider(cfg.Security)
+	if err != nil {
+		return nil, err
+	}
+	keyring := crypto.NewKeyRotationManager(provider)
+	keyring.SetRequireBound(cfg.Security.RequireBoundSecrets)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := keyring.Check(ctx); err != nil {
+		return nil, err
+	}
+	return keyring, nil
+}
+
+// initRootAdmin 数据库为空时创建根管理员，并输出一次性的根访问密钥
+// 配置了credential_file时写入该文件(0600)，写入失败或未配置时输出到终端
+func initRootAdmin(cfg *config.AppConfig, bootstrapService *service.BootstrapService) {
+	ak, err := bootstrapService.EnsureRootAdmin(context.Background())
+	if err != nil {
+		util.Logger.Error("failed to bootstrap root administrator", zap.Error(err))
+		panic(err)
+	}
+	if ak == nil {
+		return
+	}
+	util.Logger.Info("root administrator created",
+		zap.String("user", ak.UserName),
+		zap.String("access_key_id", ak.AccessKeyID),
+	)
+
+	credential := fmt.Sprintf("[default]\niam_access_key_id = %s\niam_secret_access_key = %s\n",
+		ak.AccessKeyID, ak.SecretAccessKey)
+	if path := cfg.Bootstrap.CredentialFile; path != "" {
+		err := writeCredentialFile(path, credential)
+		if err == nil {
+			util.Logger.Info("root credential written", zap.String("path", path))
+			return
+		}
+		util.Logger.Error("failed to write root credential, printing it instead", zap.String("path", path), zap.Error(err))
+	}
+
+	// 不经过日志，避免密钥落入日志文件
+	fmt.Println("Root access key created. It will NOT be shown again:")
+	fmt.Print(credential)
+}
+
+// writeCredentialFile 以0600权限创建凭证文件，文件已存在时不覆盖
+func writeCredentialFile(path, content string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// NewNonceStore 根据配置创建请求nonce存储
+func NewNonceStore(cfg *config.AppConfig, sess *dbr.Session) auth.NonceStore {
+	switch cfg.Security.NonceStore {
+	case "postgres":
+		return store.NewNonceStore(sess)
+	case "", "memory":
+		return auth.NewMemoryNonceStore()
+	default:
+		panic(fmt.Sprintf("unknown nonce store: %s", cfg.Security.NonceStore))
+	}
+}
+
 func Start() (*config.AppConfig, net.Listener) {
 	cfg, err := util.LoadConfig("config/config.yaml")
 	if err != nil {
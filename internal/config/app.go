@@ -1,22 +1,157 @@
 package config
 
+import "time"
+
 // Config 应用配置
+// 注意: 配置通过viper加载，字段映射依赖mapstructure标签
 type AppConfig struct {
 	GRPC struct {
-		Port string `yaml:"port"`
-	} `yaml:"grpc"`
+		Port string `yaml:"port" mapstructure:"port"`
+	} `yaml:"grpc" mapstructure:"grpc"`
 	Database struct {
-		DSN string `yaml:"dsn"`
-	} `yaml:"database"`
-	Security struct {
-		MasterKey string `yaml:"master_key"`
-	} `yaml:"security"`
-	Log LogConfig `yaml:"log"`
+		DSN string `yaml:"dsn" mapstructure:"dsn"`
+	} `yaml:"database" mapstructure:"database"`
+	Security  SecurityConfig  `yaml:"security" mapstructure:"security"`
+	AccessKey AccessKeyConfig `yaml:"access_key" mapstructure:"access_key"`
+	Login     LoginConfig     `yaml:"login" mapstructure:"login"`
+	Bootstrap BootstrapConfig `yaml:"bootstrap" mapstructure:"bootstrap"`
+	Auth      AuthConfig      `yaml:"auth" mapstructure:"auth"`
+	Token     TokenConfig     `yaml:"token" mapstructure:"token"`
+	ExtAuthz  ExtAuthzConfig  `yaml:"ext_authz" mapstructure:"ext_authz"`
+	Log       LogConfig       `yaml:"log" mapstructure:"log"`
+}
+
+// SecurityConfig 安全配置
+// 主密钥不写在配置文件中，由key_provider指定的来源提供
+type SecurityConfig struct {
+	KeyProvider         string    `yaml:"key_provider" mapstructure:"key_provider"`                   // 主密钥来源: env/file/kms
+	MasterKeyEnv        string    `yaml:"master_key_env" mapstructure:"master_key_env"`               // env: 保存base64主密钥(32字节)的环境变量名
+	MasterKeyID         string    `yaml:"master_key_id" mapstructure:"master_key_id"`                 // env: 主密钥ID，写入每个密文
+	KeyringFile         string    `yaml:"keyring_file" mapstructure:"keyring_file"`                   // file: 主密钥环JSON文件(0600)，可包含轮换前的密钥
+	KMS                 KMSConfig `yaml:"kms" mapstructure:"kms"`                                     // kms: 外部KMS配置
+	RequireBoundSecrets bool      `yaml:"require_bound_secrets" mapstructure:"require_bound_secrets"` // 拒绝未绑定记录的旧密文，执行reencrypt-secrets后开启
+	NonceStore          string    `yaml:"nonce_store" mapstructure:"nonce_store"`                     // nonce存储: memory/postgres，多副本部署使用postgres
+}
+
+// KMSConfig 外部KMS配置，接口见 crypto.KMSClient
+type KMSConfig struct {
+	Endpoint string        `yaml:"endpoint" mapstructure:"endpoint"`   // KMS地址，如 https://kms.internal:8443
+	KeyID    string        `yaml:"key_id" mapstructure:"key_id"`       // 用于包装数据密钥的主密钥ID
+	TokenEnv string        `yaml:"token_env" mapstructure:"token_env"` // 保存访问令牌的环境变量名，为空时不发送令牌
+	Timeout  time.Duration `yaml:"timeout" mapstructure:"timeout"`     // 请求超时
+}
+
+// TokenConfig OAuth2令牌端点和访问令牌(JWT)配置
+type TokenConfig struct {
+	Enabled             bool          `yaml:"enabled" mapstructure:"enabled"`                             // 是否启动HTTP令牌端点，关闭时已签发的令牌仍可校验
+	Port                string        `yaml:"port" mapstructure:"port"`                                   // 令牌端点的HTTP监听端口
+	Issuer              string        `yaml:"issuer" mapstructure:"issuer"`                               // 令牌的iss声明
+	Audience            string        `yaml:"audience" mapstructure:"audience"`                           // 令牌的aud声明，为空时不签发也不校验
+	TTL                 time.Duration `yaml:"ttl" mapstructure:"ttl"`                                     // 令牌有效期
+	KeyRotationInterval time.Duration `yaml:"key_rotation_interval" mapstructure:"key_rotation_interval"` // 签名密钥轮换间隔
+	AllowedScopes       []string      `yaml:"allowed_scopes" mapstructure:"allowed_scopes"`               // 允许申请的scope，为空时不限制
+}
+
+// ExtAuthzConfig Envoy外部授权服务配置
+type ExtAuthzConfig struct {
+	Enabled     bool          `yaml:"enabled" mapstructure:"enabled"`
+	Port        string        `yaml:"port" mapstructure:"port"`                 // 独立的gRPC监听端口
+	Service     string        `yaml:"service" mapstructure:"service"`           // 客户端签名时凭证范围中的服务名
+	DefaultMode string        `yaml:"default_mode" mapstructure:"default_mode"` // 未匹配路由的认证方式: public/authenticated/authorized
+	Routes      []RouteConfig `yaml:"routes" mapstructure:"routes"`
+	// Envoy未转发完整请求体时信任已签名的x-iam-content-sha256，默认拒绝此类请求；只在上游服务自行校验请求体哈希时开启
+	TrustDeclaredPayloadHash bool `yaml:"trust_declared_payload_hash" mapstructure:"trust_declared_payload_hash"`
+}
+
+// RouteConfig HTTP方法和路径到IAM操作、资源的映射，按顺序匹配
+type RouteConfig struct {
+	Method   string `yaml:"method" mapstructure:"method"`     // HTTP方法，为空或"*"匹配所有方法
+	Path     string `yaml:"path" mapstructure:"path"`         // 路径模式，如 /orders/{id}、/static/**
+	Mode     string `yaml:"mode" mapstructure:"mode"`         // public/authenticated/authorized，默认authorized
+	Action   string `yaml:"action" mapstructure:"action"`     // IAM操作，如 orders:GetOrder
+	Resource string `yaml:"resource" mapstructure:"resource"` // 资源模板，可引用路径参数，如 orders:order:{id}
+}
+
+// AuthConfig gRPC方法认证授权配置，覆盖内置的方法规则
+type AuthConfig struct {
+	DefaultMode string             `yaml:"default_mode" mapstructure:"default_mode"` // 未配置方法的认证方式: public/authenticated/authorized
+	Methods     []MethodAuthConfig `yaml:"methods" mapstructure:"methods"`
+	Throttle    ThrottleConfig     `yaml:"throttle" mapstructure:"throttle"`
+	Lockout     LockoutConfig      `yaml:"lockout" mapstructure:"lockout"`
+}
+
+// LockoutConfig 密码登录和API密钥的暴力破解防护配置，计数保存在数据库中，多副本共享
+// 用户和API密钥每次失败后需等待渐进延迟才能再次尝试，失败次数达到上限后锁定一段时间
+type LockoutConfig struct {
+	MaxFailuresPerUser int           `yaml:"max_failures_per_user" mapstructure:"max_failures_per_user"` // 用户或API密钥在窗口内的失败上限，0表示不锁定
+	MaxFailuresPerIP   int           `yaml:"max_failures_per_ip" mapstructure:"max_failures_per_ip"`     // 来源IP在窗口内的失败上限，0表示不锁定
+	Window             time.Duration `yaml:"window" mapstructure:"window"`                               // 统计窗口，从窗口内第一次失败开始计算
+	Duration           time.Duration `yaml:"duration" mapstructure:"duration"`                           // 锁定时长
+	BaseDelay          time.Duration `yaml:"base_delay" mapstructure:"base_delay"`                       // 第一次失败后的等待时间，之后每次失败翻倍，0表示不延迟
+	MaxDelay           time.Duration `yaml:"max_delay" mapstructure:"max_delay"`                         // 渐进延迟上限
 }
+
+// ThrottleConfig 认证失败限流配置，窗口内失败次数达到上限后拒绝该访问密钥ID在该来源IP、或该来源IP的认证请求
+type ThrottleConfig struct {
+	MaxFailuresPerKey int           `yaml:"max_failures_per_key" mapstructure:"max_failures_per_key"` // 每个访问密钥ID在每个来源IP的失败上限，0表示不限制
+	MaxFailuresPerIP  int           `yaml:"max_failures_per_ip" mapstructure:"max_failures_per_ip"`   // 每个来源IP的失败上限，0表示不限制
+	Window            time.Duration `yaml:"window" mapstructure:"window"`                             // 统计窗口，从窗口内第一次失败开始计算
+}
+
+// MethodAuthConfig 单个方法的认证授权规则
+type MethodAuthConfig struct {
+	Method   string `yaml:"method" mapstructure:"method"`     // gRPC完整方法名，如 /iam.v1.IAM/GetUser
+	Mode     string `yaml:"mode" mapstructure:"mode"`         // public/authenticated/authorized
+	Action   string `yaml:"action" mapstructure:"action"`     // authorized时校验的操作，为空时使用内置操作
+	Resource string `yaml:"resource" mapstructure:"resource"` // 固定资源ARN，为空时由请求参数计算
+}
+
+// BootstrapConfig 首次启动初始化配置，仅在数据库中没有任何用户时生效
+type BootstrapConfig struct {
+	RootUser       string `yaml:"root_user" mapstructure:"root_user"`             // 根管理员用户名
+	RootEmail      string `yaml:"root_email" mapstructure:"root_email"`           // 根管理员邮箱
+	CredentialFile string `yaml:"credential_file" mapstructure:"credential_file"` // 根访问密钥写入的文件(0600)，为空时输出到终端
+}
+
+// AccessKeyConfig 访问密钥生命周期配置
+type AccessKeyConfig struct {
+	DefaultTTL            time.Duration `yaml:"default_ttl" mapstructure:"default_ttl"`                           // 默认有效期，0表示永不过期
+	MaxTTL                time.Duration `yaml:"max_ttl" mapstructure:"max_ttl"`                                   // 创建时允许指定的最长有效期，0表示不限制
+	ExpiryCheckInterval   time.Duration `yaml:"expiry_check_interval" mapstructure:"expiry_check_interval"`       // 过期检查任务执行间隔
+	ExpiryWarningDays     int           `yaml:"expiry_warning_days" mapstructure:"expiry_warning_days"`           // 过期前多少天开始告警
+	RotationGracePeriod   time.Duration `yaml:"rotation_grace_period" mapstructure:"rotation_grace_period"`       // 轮换后旧密钥仍可使用的宽限期
+	LastUsedFlushInterval time.Duration `yaml:"last_used_flush_interval" mapstructure:"last_used_flush_interval"` // 最后使用信息批量写入间隔
+	MaxKeysPerUser        int           `yaml:"max_keys_per_user" mapstructure:"max_keys_per_user"`               // 每个用户最多拥有的访问密钥数，0表示不限制
+}
+
+// LoginConfig 控制台登录配置
+type LoginConfig struct {
+	PasswordPolicy PasswordPolicyConfig `yaml:"password_policy" mapstructure:"password_policy"`
+	SessionTTL     time.Duration        `yaml:"session_ttl" mapstructure:"session_ttl"` // Authenticate返回的会话令牌有效期
+	MFA            MFAConfig            `yaml:"mfa" mapstructure:"mfa"`
+}
+
+// MFAConfig 虚拟MFA设备配置
+type MFAConfig struct {
+	Issuer    string `yaml:"issuer" mapstructure:"issuer"`         // otpauth URI中的签发者，显示在验证器应用中
+	SkewSteps int    `yaml:"skew_steps" mapstructure:"skew_steps"` // 允许的时钟偏差(30秒步长的个数)
+}
+
+// PasswordPolicyConfig 密码策略
+type PasswordPolicyConfig struct {
+	MinLength        int           `yaml:"min_length" mapstructure:"min_length"`               // 最小长度(字符数)
+	RequireUppercase bool          `yaml:"require_uppercase" mapstructure:"require_uppercase"` // 至少包含一个大写字母
+	RequireLowercase bool          `yaml:"require_lowercase" mapstructure:"require_lowercase"` // 至少包含一个小写字母
+	RequireDigits    bool          `yaml:"require_digits" mapstructure:"require_digits"`       // 至少包含一个数字
+	RequireSymbols   bool          `yaml:"require_symbols" mapstructure:"require_symbols"`     // 至少包含一个字母和数字以外的字符
+	ReuseHistory     int           `yaml:"reuse_history" mapstructure:"reuse_history"`         // 禁止重复使用最近几次的密码，0表示不限制
+	MaxAge           time.Duration `yaml:"max_age" mapstructure:"max_age"`                     // 密码有效期，过期后登录时必须修改，0表示永不过期
+}
+
 type LogConfig struct {
-	Level     string `yaml:"level"`     // 日志级别: debug/info/warn/error
-	Format    string `yaml:"format"`    // 日志格式: json/console
-	Directory string `yaml:"directory"` // 日志文件目录
-	Filename  string `yaml:"filename"`  // 日志文件名
-	ToStdout  bool   `yaml:"to_stdout"` // 是否输出到终端
+	Level     string `yaml:"level" mapstructure:"level"`         // 日志级别: debug/info/warn/error
+	Format    string `yaml:"format" mapstructure:"format"`       // 日志格式: json/console
+	Directory string `yaml:"directory" mapstructure:"directory"` // 日志文件目录
+	Filename  string `yaml:"filename" mapstructure:"filename"`   // 日志文件名
+	ToStdout  bool   `yaml:"to_stdout" mapstructure:"to_stdout"` // 是否输出到终端
 }
@@ -0,0 +1,101 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// JWTAlgEdDSA Ed25519签名的JWT算法名(RFC 8037)
+const JWTAlgEdDSA = "EdDSA"
+
+// ErrInvalidJWT JWT格式错误、算法不受支持或签名不匹配
+var ErrInvalidJWT = errors.New("invalid jwt")
+
+// jwtHeader JWT头部
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// JWK Ed25519公钥的JSON Web Key(RFC 8037)
+type JWK struct {
+	Kty string `json:"kty"` // 固定为OKP
+	Crv string `json:"crv"` // 固定为Ed25519
+	X   string `json:"x"`   // base64url编码的公钥
+	Kid string `json:"kid"`
+	Use string `json:"use"` // 固定为sig
+	Alg string `json:"alg"` // 固定为EdDSA
+}
+
+// NewJWK 返回Ed25519公钥的JWK
+func NewJWK(kid string, key ed25519.PublicKey) JWK {
+	return JWK{Kty: "OKP", Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(key), Kid: kid, Use: "sig", Alg: JWTAlgEdDSA}
+}
+
+// JWKThumbprint 返回Ed25519公钥的RFC 7638指纹(SHA-256，base64url)，用作kid
+func JWKThumbprint(key ed25519.PublicKey) string {
+	// 必需成员按字典序排列，不含空白
+	canonical := `{"crv":"Ed25519","kty":"OKP","x":"` + base64.RawURLEncoding.EncodeToString(key) + `"}`
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// SignJWT 使用Ed25519私钥签发紧凑格式的JWT，kid写入头部，claims序列化为JSON载荷
+func SignJWT(claims interface{}, kid string, key ed25519.PrivateKey) (string, error) {
+	header, err := json.Marshal(jwtHeader{Alg: JWTAlgEdDSA, Typ: "JWT", Kid: kid})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal jwt claims: %w", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature := ed25519.Sign(key, []byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// VerifyJWT 按头部的kid通过keyFunc查找公钥，校验紧凑格式JWT的签名后将载荷解析到claims
+// 只接受EdDSA算法，kid未知时返回ErrInvalidJWT；不校验exp等声明，由调用方根据业务校验
+func VerifyJWT(token string, keyFunc func(kid string) (ed25519.PublicKey, bool), claims interface{}) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ErrInvalidJWT
+	}
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return ErrInvalidJWT
+	}
+	var header jwtHeader
+	if err := json.Unmarshal(headerJSON, &header); err != nil || header.Alg != JWTAlgEdDSA || header.Kid == "" {
+		return ErrInvalidJWT
+	}
+	key, ok := keyFunc(header.Kid)
+	if !ok {
+		return ErrInvalidJWT
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return ErrInvalidJWT
+	}
+
+	if !ed25519.Verify(key, []byte(parts[0]+"."+parts[1]), signature) {
+		return ErrInvalidJWT
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ErrInvalidJWT
+	}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidJWT, err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJWT(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	keys := map[string]ed25519.PublicKey{"k1": pub}
+	keyFunc := func(kid string) (ed25519.PublicKey, bool) {
+		key, ok := keys[kid]
+		return key, ok
+	}
+
+	token, err := SignJWT(map[string]string{"sub": "alice"}, "k1", priv)
+	if err != nil {
+		t.Fatalf("SignJWT failed: %v", err)
+	}
+	var claims map[string]string
+	if err := VerifyJWT(token, keyFunc, &claims); err != nil || claims["sub"] != "alice" {
+		t.Fatalf("VerifyJWT = %v, %v", claims, err)
+	}
+
+	// kid未知或指向其他密钥时拒绝
+	other, _, _ := ed25519.GenerateKey(rand.Reader)
+	keys["k2"] = other
+	for _, kid := range []string{"k2", "k3"} {
+		token, _ := SignJWT(map[string]string{"sub": "alice"}, kid, priv)
+		if err := VerifyJWT(token, keyFunc, &claims); !errors.Is(err, ErrInvalidJWT) {
+			t.Errorf("expected ErrInvalidJWT for kid %s, got %v", kid, err)
+		}
+	}
+
+	// 修改载荷后签名不再匹配
+	parts := strings.Split(token, ".")
+	forged, _ := SignJWT(map[string]string{"sub": "root"}, "k1", priv)
+	parts[1] = strings.Split(forged, ".")[1]
+	if err := VerifyJWT(strings.Join(parts, "."), keyFunc, &claims); !errors.Is(err, ErrInvalidJWT) {
+		t.Errorf("expected ErrInvalidJWT for tampered payload, got %v", err)
+	}
+	// 拒绝alg为none的令牌
+	if err := VerifyJWT("eyJhbGciOiJub25lIiwia2lkIjoiazEifQ.e30.", keyFunc, &claims); !errors.Is(err, ErrInvalidJWT) {
+		t.Errorf("expected ErrInvalidJWT for alg none, got %v", err)
+	}
+}
+
+func TestJWKThumbprint(t *testing.T) {
+	// RFC 8037 附录A.3的测试向量
+	key, _ := hex.DecodeString("d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a")
+	if got := JWKThumbprint(key); got != "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k" {
+		t.Errorf("JWKThumbprint = %s", got)
+	}
+}
@@ -0,0 +1,208 @@
+package crypto
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/vera-byte/vgo-iam/internal/config"
+)
+
+// MasterKeySize 主密钥（密钥加密密钥）长度，使用AES-256
+const MasterKeySize = 32
+
+var (
+	// ErrInvalidMasterKey 主密钥格式或长度不合法
+	ErrInvalidMasterKey = errors.New("invalid master key")
+	// ErrUnknownKeyID 密钥提供者中没有该ID的主密钥
+	ErrUnknownKeyID = errors.New("unknown master key id")
+)
+
+// KeyProvider 密钥加密密钥(KEK)提供者
+// 每条记录使用独立的数据密钥加密，数据密钥由KeyProvider包装后与密文一起保存
+type KeyProvider interface {
+	// CurrentKeyID 返回包装新数据密钥使用的主密钥ID
+	CurrentKeyID() string
+	// KeyIDs 返回可用于解密的所有主密钥ID，当前密钥在前
+	KeyIDs() []string
+	// WrapKey 使用当前主密钥包装数据密钥，返回实际使用的主密钥ID
+	WrapKey(ctx context.Context, dataKey []byte) (keyID string, wrapped []byte, err error)
+	// UnwrapKey 使用指定主密钥解开数据密钥
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// LocalKeyring 本地主密钥环，主密钥保存在进程内存中
+// 包装格式为 nonce | AES-GCM密文，与早期直接用主密钥加密的密文格式相同
+type LocalKeyring struct {
+	currentID string
+	ids       []string
+	keys      map[string][]byte
+}
+
+// NewLocalKeyring 创建本地主密钥环，添加密钥后由Validate确认当前密钥存在
+func NewLocalKeyring(currentID string) *LocalKeyring {
+	return &LocalKeyring{
+		currentID: currentID,
+		keys:      make(map[string][]byte),
+	}
+}
+
+// AddKey 添加主密钥，主密钥必须为32字节
+func (k *LocalKeyring) AddKey(keyID string, key []byte) error {
+	if err := validateKeyID(keyID); err != nil {
+		return err
+	}
+	if _, exists := k.keys[keyID]; exists {
+		return fmt.Errorf("%w: duplicate id %q", ErrInvalidKeyID, keyID)
+	}
+	if len(key) != MasterKeySize {
+		return fmt.Errorf("%w: key %q is %d bytes, want %d (base64 of 32 random bytes)", ErrInvalidMasterKey, keyID, len(key), MasterKeySize)
+	}
+	k.keys[keyID] = key
+	if keyID == k.currentID {
+		k.ids = append([]string{keyID}, k.ids...)
+	} else {
+		k.ids = append(k.ids, keyID)
+	}
+	return nil
+}
+
+// Validate 校验当前主密钥已配置
+func (k *LocalKeyring) Validate() error {
+	if _, ok := k.keys[k.currentID]; !ok {
+		return fmt.Errorf("%w: current key %q is not in the keyring", ErrInvalidMasterKey, k.currentID)
+	}
+	return nil
+}
+
+// CurrentKeyID 返回当前主密钥ID
+func (k *LocalKeyring) CurrentKeyID() string {
+	return k.currentID
+}
+
+// KeyIDs 返回所有主密钥ID，当前密钥在前
+func (k *LocalKeyring) KeyIDs() []string {
+	return k.ids
+}
+
+// WrapKey 使用当前主密钥包装数据密钥
+func (k *LocalKeyring) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	wrapped, err := EncryptKey(dataKey, k.keys[k.currentID], nil)
+	if err != nil {
+		return "", nil, err
+	}
+	return k.currentID, wrapped, nil
+}
+
+// UnwrapKey 使用指定主密钥解开数据密钥
+func (k *LocalKeyring) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKeyID, keyID)
+	}
+	return DecryptKey(wrapped, key, nil)
+}
+
+// keyringFile 主密钥环文件格式
+type keyringFile struct {
+	CurrentKeyID string `json:"current_key_id"`
+	Keys         []struct {
+		ID  string `json:"id"`
+		Key string `json:"key"` // base64编码的32字节主密钥
+	} `json:"keys"`
+}
+
+// LoadKeyringFile 从JSON文件加载本地主密钥环，文件不能被其他用户读写
+func LoadKeyringFile(path string) (*LocalKeyring, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyring file: %w", err)
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("keyring file %s has mode %v, it must not be accessible by group or others (chmod 600)", path, info.Mode().Perm())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyring file: %w", err)
+	}
+
+	var file keyringFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse keyring file %s: %w", path, err)
+	}
+	keyring := NewLocalKeyring(file.CurrentKeyID)
+	for _, entry := range file.Keys {
+		key, err := decodeMasterKey(entry.Key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q: %v", ErrInvalidMasterKey, entry.ID, err)
+		}
+		if err := keyring.AddKey(entry.ID, key); err != nil {
+			return nil, err
+		}
+	}
+	if err := keyring.Validate(); err != nil {
+		return nil, err
+	}
+	return keyring, nil
+}
+
+// NewEnvKeyProvider 从环境变量读取base64编码的主密钥，创建只有一个密钥的主密钥环
+func NewEnvKeyProvider(envName, keyID string) (*LocalKeyring, error) {
+	encoded := os.Getenv(envName)
+	if encoded == "" {
+		return nil, fmt.Errorf("%w: environment variable %s is not set", ErrInvalidMasterKey, envName)
+	}
+	key, err := decodeMasterKey(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidMasterKey, envName, err)
+	}
+	keyring := NewLocalKeyring(keyID)
+	if err := keyring.AddKey(keyID, key); err != nil {
+		return nil, err
+	}
+	return keyring, nil
+}
+
+// decodeMasterKey 解码base64主密钥，兼容标准和URL编码、有无填充
+func decodeMasterKey(encoded string) ([]byte, error) {
+	encoded = strings.TrimSpace(encoded)
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if key, err := enc.DecodeString(encoded); err == nil {
+			return key, nil
+		}
+	}
+	return nil, errors.New("not valid base64")
+}
+
+// NewKeyProvider 根据配置创建主密钥提供者，主密钥缺失或长度不合法时返回错误
+func NewKeyProvider(cfg config.SecurityConfig) (KeyProvider, error) {
+	var (
+		provider KeyProvider
+		err      error
+	)
+	switch cfg.KeyProvider {
+	case "", "env":
+		provider, err = NewEnvKeyProvider(cfg.MasterKeyEnv, cfg.MasterKeyID)
+	case "file":
+		if cfg.KeyringFile == "" {
+			return nil, fmt.Errorf("%w: security.keyring_file is required for the file key provider", ErrInvalidMasterKey)
+		}
+		provider, err = LoadKeyringFile(cfg.KeyringFile)
+	case "kms":
+		var token string
+		if cfg.KMS.TokenEnv != "" {
+			token = os.Getenv(cfg.KMS.TokenEnv)
+		}
+		provider, err = NewKMSClient(cfg.KMS.Endpoint, cfg.KMS.KeyID, token, cfg.KMS.Timeout)
+	default:
+		return nil, fmt.Errorf("unknown key provider: %s", cfg.KeyProvider)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
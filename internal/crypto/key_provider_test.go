@@ -0,0 +1,101 @@
+package crypto_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/crypto"
+	"github.com/vera-byte/vgo-iam/internal/crypto/kmstest"
+)
+
+func TestKeyProviderRejectsInvalidKeyLength(t *testing.T) {
+	// 40个字符的字符串不是合法的AES密钥
+	t.Setenv("TEST_MASTER_KEY", "MWZjMTk4NzVkN2E4YzlmZmQwNjJiZWE2N2E3MGU1")
+	_, err := crypto.NewKeyProvider(config.SecurityConfig{KeyProvider: "env", MasterKeyEnv: "TEST_MASTER_KEY", MasterKeyID: "1"})
+	if !errors.Is(err, crypto.ErrInvalidMasterKey) {
+		t.Fatalf("expected ErrInvalidMasterKey, got %v", err)
+	}
+
+	key, _ := crypto.GenerateNewMasterKey()
+	t.Setenv("TEST_MASTER_KEY", base64.StdEncoding.EncodeToString(key))
+	provider, err := crypto.NewKeyProvider(config.SecurityConfig{KeyProvider: "env", MasterKeyEnv: "TEST_MASTER_KEY", MasterKeyID: "1"})
+	if err != nil {
+		t.Fatalf("NewKeyProvider failed: %v", err)
+	}
+	if err := crypto.NewKeyRotationManager(provider).Check(context.Background()); err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+}
+
+func TestLoadKeyringFile(t *testing.T) {
+	oldKey, _ := crypto.GenerateNewMasterKey()
+	newKey, _ := crypto.GenerateNewMasterKey()
+	content := `{"current_key_id":"2","keys":[` +
+		`{"id":"1","key":"` + base64.StdEncoding.EncodeToString(oldKey) + `"},` +
+		`{"id":"2","key":"` + base64.StdEncoding.EncodeToString(newKey) + `"}]}`
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := crypto.LoadKeyringFile(path); err == nil {
+		t.Fatal("expected a world-readable keyring file to be rejected")
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	keyring, err := crypto.LoadKeyringFile(path)
+	if err != nil {
+		t.Fatalf("LoadKeyringFile failed: %v", err)
+	}
+	if ids := keyring.KeyIDs(); keyring.CurrentKeyID() != "2" || len(ids) != 2 || ids[0] != "2" {
+		t.Errorf("unexpected keyring: current %q, ids %q", keyring.CurrentKeyID(), ids)
+	}
+}
+
+func TestKMSEnvelopeEncryption(t *testing.T) {
+	ctx := context.Background()
+	key, _ := crypto.GenerateNewMasterKey()
+	local := crypto.NewLocalKeyring("kek-1")
+	if err := local.AddKey("kek-1", key); err != nil {
+		t.Fatal(err)
+	}
+	server := kmstest.NewServer(local, "kms-token")
+	defer server.Close()
+
+	client, err := crypto.NewKMSClient(server.URL, "kek-1", "kms-token", 0)
+	if err != nil {
+		t.Fatalf("NewKMSClient failed: %v", err)
+	}
+	keyring := crypto.NewKeyRotationManager(client)
+
+	aad := []byte("record-1")
+	first, err := keyring.EncryptWithCurrentKey(ctx, []byte("secret"), aad)
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	second, _ := keyring.EncryptWithCurrentKey(ctx, []byte("secret"), aad)
+	if string(first) == string(second) {
+		t.Error("expected a fresh data key per record")
+	}
+	if id, ok := crypto.KeyIDOf(first); !ok || id != "kek-1" {
+		t.Errorf("KeyIDOf = %q, %v", id, ok)
+	}
+	plain, err := keyring.DecryptWithAnyKey(ctx, first, aad)
+	if err != nil || string(plain) != "secret" {
+		t.Fatalf("decrypt = %q, %v", plain, err)
+	}
+	if _, err := keyring.DecryptWithAnyKey(ctx, first, []byte("record-2")); err == nil {
+		t.Error("expected decryption with different additional data to fail")
+	}
+
+	unauthorized, _ := crypto.NewKMSClient(server.URL, "kek-1", "wrong", 0)
+	if _, err := crypto.NewKeyRotationManager(unauthorized).DecryptWithAnyKey(ctx, first, aad); err == nil {
+		t.Error("expected decryption to fail with an invalid kms token")
+	}
+}
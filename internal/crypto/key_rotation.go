@@ -1,108 +1,285 @@
 package crypto
 
 import (
+	"bytes"
+	"context"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
 )
 
+// 密文格式
+//
+//	绑定信封格式: 0x03 | 主密钥ID长度(1字节) | 主密钥ID | 数据密钥密文长度(2字节) | 数据密钥密文 | nonce | AES-GCM密文
+//	信封格式: 0x02 | 同上，AES-GCM未使用附加数据
+//	主密钥直接加密: 0x01 | 主密钥ID长度(1字节) | 主密钥ID | nonce | AES-GCM密文
+//	旧格式: nonce | AES-GCM密文，没有前缀
+//
+// 新密文总是使用绑定信封格式：每条记录生成独立的数据密钥，数据密钥由KeyProvider包装，
+// 内容以调用方给出的附加数据(AAD)加密，密文被挪到其他记录后无法解密。
+// 其余格式为未绑定密文，重新加密任务会将其迁移为绑定信封格式
+const (
+	ciphertextVersionDirect   = 0x01
+	ciphertextVersionEnvelope = 0x02
+	ciphertextVersionBound    = 0x03
+	maxKeyIDLength            = 255
+	dataKeySize               = 32
+)
+
+var (
+	// ErrDecryptFailed 没有可用密钥能够解密
+	ErrDecryptFailed = errors.New("failed to decrypt with any available key")
+	// ErrInvalidKeyID 密钥ID为空、过长或与已有密钥重复
+	ErrInvalidKeyID = errors.New("invalid master key id")
+	// ErrUnboundCiphertext 要求密文绑定附加数据时遇到未绑定的旧密文
+	ErrUnboundCiphertext = errors.New("ciphertext is not bound to its record, re-encrypt it first")
+)
+
 // KeyRotationManager 密钥轮换管理器
+// 使用KeyProvider当前主密钥进行信封加密，解密时按密文中的主密钥ID解开数据密钥
 type KeyRotationManager struct {
-	currentKey   []byte
-	previousKeys [][]byte
+	provider     KeyProvider
+	requireBound bool
 }
 
 // NewKeyRotationManager 创建密钥轮换管理器
-func NewKeyRotationManager(initialKey []byte) *KeyRotationManager {
-	return &KeyRotationManager{
-		currentKey:   initialKey,
-		previousKeys: [][]byte{},
-	}
+func NewKeyRotationManager(provider KeyProvider) *KeyRotationManager {
+	return &KeyRotationManager{provider: provider}
 }
 
-// RotateKey 轮换主密钥
-func (m *KeyRotationManager) RotateKey(newKey []byte) {
-	m.previousKeys = append(m.previousKeys, m.currentKey)
-	m.currentKey = newKey
-
-	// 只保留最近的两个密钥
-	if len(m.previousKeys) > 2 {
-		m.previousKeys = m.previousKeys[len(m.previousKeys)-2:]
-	}
+// SetRequireBound 设置为true后DecryptWithAnyKey拒绝未绑定附加数据的旧密文
+// 应在重新加密任务完成后开启，避免旧密文被复制到其他记录使用
+func (m *KeyRotationManager) SetRequireBound(require bool) {
+	m.requireBound = require
 }
 
-// ReEncryptKeys 使用新密钥重新加密所有密钥
-func (m *KeyRotationManager) ReEncryptKeys(encryptedKeys [][]byte) ([][]byte, error) {
-	var reencrypted [][]byte
+// CurrentKeyID 返回当前主密钥ID
+func (m *KeyRotationManager) CurrentKeyID() string {
+	return m.provider.CurrentKeyID()
+}
 
-	for _, encKey := range encryptedKeys {
-		// 尝试用之前的密钥解密
-		var decrypted []byte
-		var err error
+// Check 加密并解密一段随机数据，启动时用于确认主密钥可用
+func (m *KeyRotationManager) Check(ctx context.Context) error {
+	probe := make([]byte, 16)
+	if _, err := rand.Read(probe); err != nil {
+		return err
+	}
+	aad := []byte("vgo-iam/key-check")
+	ciphertext, err := m.EncryptWithCurrentKey(ctx, probe, aad)
+	if err != nil {
+		return fmt.Errorf("master key %q cannot encrypt: %w", m.CurrentKeyID(), err)
+	}
+	decrypted, err := m.DecryptWithAnyKey(ctx, ciphertext, aad)
+	if err != nil || !bytes.Equal(decrypted, probe) {
+		return fmt.Errorf("master key %q cannot decrypt its own ciphertext: %v", m.CurrentKeyID(), err)
+	}
+	return nil
+}
 
-		// 先尝试当前密钥
-		decrypted, err = DecryptKey(encKey, m.currentKey)
+// ReEncrypt 将密文迁移为当前主密钥的绑定信封格式，已是该格式时原样返回，changed为false
+// 绑定信封密文只需重新包装数据密钥；未绑定的旧密文解密后以additionalData重新加密，
+// 不受SetRequireBound限制，是旧密文的迁移途径
+func (m *KeyRotationManager) ReEncrypt(ctx context.Context, ciphertext, additionalData []byte) (reencrypted []byte, changed bool, err error) {
+	if env, ok := parseEnvelope(ciphertext); ok && env.bound {
+		if env.keyID == m.CurrentKeyID() {
+			return ciphertext, false, nil
+		}
+		dataKey, err := m.provider.UnwrapKey(ctx, env.keyID, env.wrappedKey)
 		if err != nil {
-			// 尝试之前的密钥
-			for _, oldKey := range m.previousKeys {
-				decrypted, err = DecryptKey(encKey, oldKey)
-				if err == nil {
-					break
-				}
-			}
-			if err != nil {
-				return nil, err
-			}
+			return nil, false, err
 		}
-
-		// 用新密钥重新加密
-		newEncKey, err := EncryptKey(decrypted, m.currentKey)
+		// 确认密文属于该记录后再重新包装
+		if _, err := DecryptKey(env.sealed, dataKey, additionalData); err != nil {
+			return nil, false, err
+		}
+		keyID, wrapped, err := m.provider.WrapKey(ctx, dataKey)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
-		reencrypted = append(reencrypted, newEncKey)
+		reencrypted, err = marshalEnvelope(ciphertextVersionBound, keyID, wrapped, env.sealed)
+		if err != nil {
+			return nil, false, err
+		}
+		return reencrypted, true, nil
 	}
 
-	return reencrypted, nil
+	decrypted, err := m.decryptUnbound(ctx, ciphertext)
+	if err != nil {
+		return nil, false, err
+	}
+	reencrypted, err = m.EncryptWithCurrentKey(ctx, decrypted, additionalData)
+	if err != nil {
+		return nil, false, err
+	}
+	return reencrypted, true, nil
 }
 
 // GenerateNewMasterKey 生成新的主密钥
 func GenerateNewMasterKey() ([]byte, error) {
-	key := make([]byte, 32) // AES-256
+	key := make([]byte, MasterKeySize) // AES-256
 	if _, err := io.ReadFull(rand.Reader, key); err != nil {
 		return nil, err
 	}
 	return key, nil
 }
 
-// EncryptWithCurrentKey 使用当前密钥加密
-func (m *KeyRotationManager) EncryptWithCurrentKey(plaintext []byte) ([]byte, error) {
-	return EncryptKey(plaintext, m.currentKey)
+// EncryptWithCurrentKey 生成数据密钥加密明文，并使用当前主密钥包装数据密钥
+// additionalData标识密文所属的记录，解密时必须提供相同的数据
+func (m *KeyRotationManager) EncryptWithCurrentKey(ctx context.Context, plaintext, additionalData []byte) ([]byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, err
+	}
+	sealed, err := EncryptKey(plaintext, dataKey, additionalData)
+	if err != nil {
+		return nil, err
+	}
+	keyID, wrapped, err := m.provider.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	return marshalEnvelope(ciphertextVersionBound, keyID, wrapped, sealed)
 }
 
 // DecryptWithAnyKey 使用可用密钥解密
-func (m *KeyRotationManager) DecryptWithAnyKey(ciphertext []byte) ([]byte, error) {
-	// 先尝试当前密钥
-	decrypted, err := DecryptKey(ciphertext, m.currentKey)
-	if err == nil {
+// 绑定信封密文按其中的主密钥ID解开数据密钥，并校验additionalData；
+// 未绑定的旧密文在未开启SetRequireBound时仍可解密
+func (m *KeyRotationManager) DecryptWithAnyKey(ctx context.Context, ciphertext, additionalData []byte) ([]byte, error) {
+	if env, ok := parseEnvelope(ciphertext); ok && env.bound {
+		dataKey, err := m.provider.UnwrapKey(ctx, env.keyID, env.wrappedKey)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrDecryptFailed, err)
+		}
+		decrypted, err := DecryptKey(env.sealed, dataKey, additionalData)
+		if err != nil {
+			return nil, fmt.Errorf("%w: ciphertext does not belong to this record", ErrDecryptFailed)
+		}
 		return decrypted, nil
 	}
 
-	// 尝试之前的密钥
-	for _, key := range m.previousKeys {
-		decrypted, err = DecryptKey(ciphertext, key)
+	if m.requireBound {
+		return nil, ErrUnboundCiphertext
+	}
+	return m.decryptUnbound(ctx, ciphertext)
+}
+
+// decryptUnbound 解密未绑定附加数据的旧密文
+func (m *KeyRotationManager) decryptUnbound(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	var envelopeErr error
+	if env, ok := parseEnvelope(ciphertext); ok {
+		dataKey, err := m.provider.UnwrapKey(ctx, env.keyID, env.wrappedKey)
 		if err == nil {
+			var decrypted []byte
+			if decrypted, err = DecryptKey(env.sealed, dataKey, nil); err == nil {
+				return decrypted, nil
+			}
+		}
+		envelopeErr = err
+	}
+
+	// 主密钥直接加密的密文，使用本地主密钥环解密
+	if keyID, sealed, ok := splitDirect(ciphertext); ok {
+		if decrypted, err := m.provider.UnwrapKey(ctx, keyID, sealed); err == nil {
 			return decrypted, nil
 		}
 	}
 
-	return nil, errors.New("failed to decrypt with any available key")
+	// 旧格式密文：nonce随机，可能恰好形似前缀，因此前缀解密失败后仍按旧格式尝试
+	for _, keyID := range m.provider.KeyIDs() {
+		if decrypted, err := m.provider.UnwrapKey(ctx, keyID, ciphertext); err == nil {
+			return decrypted, nil
+		}
+	}
+
+	// 信封密文解密失败时返回其错误，便于区分KMS不可用等情况
+	if envelopeErr != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecryptFailed, envelopeErr)
+	}
+	return nil, ErrDecryptFailed
+}
+
+// KeyIDOf 返回密文前缀中的主密钥ID，旧格式密文返回false
+func KeyIDOf(ciphertext []byte) (string, bool) {
+	if env, ok := parseEnvelope(ciphertext); ok {
+		return env.keyID, true
+	}
+	keyID, _, ok := splitDirect(ciphertext)
+	return keyID, ok
+}
+
+// envelope 信封格式密文的各部分
+type envelope struct {
+	bound      bool // 是否使用附加数据加密
+	keyID      string
+	wrappedKey []byte
+	sealed     []byte
+}
+
+func marshalEnvelope(version byte, keyID string, wrapped, sealed []byte) ([]byte, error) {
+	if err := validateKeyID(keyID); err != nil {
+		return nil, err
+	}
+	if len(wrapped) > 0xFFFF {
+		return nil, errors.New("wrapped data key too long")
+	}
+	out := make([]byte, 0, 4+len(keyID)+len(wrapped)+len(sealed))
+	out = append(out, version, byte(len(keyID)))
+	out = append(out, keyID...)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(wrapped)))
+	out = append(out, wrapped...)
+	return append(out, sealed...), nil
+}
+
+func parseEnvelope(ciphertext []byte) (*envelope, bool) {
+	if len(ciphertext) < 2 || (ciphertext[0] != ciphertextVersionEnvelope && ciphertext[0] != ciphertextVersionBound) {
+		return nil, false
+	}
+	n := int(ciphertext[1])
+	rest := ciphertext[2:]
+	if n == 0 || len(rest) < n+2 {
+		return nil, false
+	}
+	keyID := string(rest[:n])
+	rest = rest[n:]
+	w := int(binary.BigEndian.Uint16(rest))
+	rest = rest[2:]
+	if len(rest) < w {
+		return nil, false
+	}
+	return &envelope{
+		bound:      ciphertext[0] == ciphertextVersionBound,
+		keyID:      keyID,
+		wrappedKey: rest[:w],
+		sealed:     rest[w:],
+	}, true
+}
+
+// splitDirect 拆分主密钥直接加密的密文前缀，返回主密钥ID和AES-GCM部分
+func splitDirect(ciphertext []byte) (string, []byte, bool) {
+	if len(ciphertext) < 2 || ciphertext[0] != ciphertextVersionDirect {
+		return "", nil, false
+	}
+	n := int(ciphertext[1])
+	if n == 0 || len(ciphertext) < 2+n {
+		return "", nil, false
+	}
+	return string(ciphertext[2 : 2+n]), ciphertext[2+n:], true
+}
+
+func validateKeyID(keyID string) error {
+	if keyID == "" || len(keyID) > maxKeyIDLength {
+		return ErrInvalidKeyID
+	}
+	return nil
 }
 
 // EncryptKey encrypts plaintext with the given key using AES-GCM.
-func EncryptKey(plaintext, key []byte) ([]byte, error) {
+// additionalData is authenticated but not encrypted; pass the same value to DecryptKey.
+func EncryptKey(plaintext, key, additionalData []byte) ([]byte, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
@@ -115,12 +292,13 @@ func EncryptKey(plaintext, key []byte) ([]byte, error) {
 	if _, err := rand.Read(nonce); err != nil {
 		return nil, err
 	}
-	ciphertext := aesgcm.Seal(nonce, nonce, plaintext, nil)
+	ciphertext := aesgcm.Seal(nonce, nonce, plaintext, additionalData)
 	return ciphertext, nil
 }
 
-// DecryptKey decrypts ciphertext with the given key using AES-GCM.
-func DecryptKey(ciphertext, key []byte) ([]byte, error) {
+// DecryptKey decrypts ciphertext with the given key using AES-GCM,
+// verifying the additionalData it was encrypted with.
+func DecryptKey(ciphertext, key, additionalData []byte) ([]byte, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
@@ -134,7 +312,7 @@ func DecryptKey(ciphertext, key []byte) ([]byte, error) {
 		return nil, errors.New("ciphertext too short")
 	}
 	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
-	plaintext, err := aesgcm.Open(nil, nonce, ciphertext, nil)
+	plaintext, err := aesgcm.Open(nil, nonce, ciphertext, additionalData)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,131 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// KMS HTTP接口
+//
+//	POST {endpoint}/v1/keys/{key_id}/wrap    {"plaintext": base64}  -> {"key_id": "...", "ciphertext": base64}
+//	POST {endpoint}/v1/keys/{key_id}/unwrap  {"ciphertext": base64} -> {"plaintext": base64}
+//
+// 配置了token时以 Authorization: Bearer <token> 发送；wrap返回的key_id可以带版本，
+// 保存在密文中并在unwrap时原样使用
+
+// KMSWrapRequest 包装数据密钥请求
+type KMSWrapRequest struct {
+	Plaintext []byte `json:"plaintext"`
+}
+
+// KMSWrapResponse 包装数据密钥响应
+type KMSWrapResponse struct {
+	KeyID      string `json:"key_id"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// KMSUnwrapRequest 解开数据密钥请求
+type KMSUnwrapRequest struct {
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// KMSUnwrapResponse 解开数据密钥响应
+type KMSUnwrapResponse struct {
+	Plaintext []byte `json:"plaintext"`
+}
+
+// KMSClient 通过HTTP调用外部KMS包装数据密钥，主密钥不离开KMS
+type KMSClient struct {
+	endpoint   string
+	keyID      string
+	token      string
+	httpClient *http.Client
+}
+
+// NewKMSClient 创建KMS客户端，timeout为0时使用10秒
+func NewKMSClient(endpoint, keyID, token string, timeout time.Duration) (*KMSClient, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid kms endpoint %q", endpoint)
+	}
+	if err := validateKeyID(keyID); err != nil {
+		return nil, err
+	}
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	return &KMSClient{
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		keyID:      keyID,
+		token:      token,
+		httpClient: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// CurrentKeyID 返回KMS中的主密钥ID
+func (c *KMSClient) CurrentKeyID() string {
+	return c.keyID
+}
+
+// KeyIDs 返回KMS中的主密钥ID
+func (c *KMSClient) KeyIDs() []string {
+	return []string{c.keyID}
+}
+
+// WrapKey 调用KMS包装数据密钥
+func (c *KMSClient) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	var resp KMSWrapResponse
+	if err := c.call(ctx, c.keyID, "wrap", KMSWrapRequest{Plaintext: dataKey}, &resp); err != nil {
+		return "", nil, err
+	}
+	if resp.KeyID == "" {
+		resp.KeyID = c.keyID
+	}
+	return resp.KeyID, resp.Ciphertext, nil
+}
+
+// UnwrapKey 调用KMS解开数据密钥
+func (c *KMSClient) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	var resp KMSUnwrapResponse
+	if err := c.call(ctx, keyID, "unwrap", KMSUnwrapRequest{Ciphertext: wrapped}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Plaintext, nil
+}
+
+func (c *KMSClient) call(ctx context.Context, keyID, op string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	endpoint := c.endpoint + "/v1/keys/" + url.PathEscape(keyID) + "/" + op
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("kms %s failed: %w", op, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("kms %s failed: %s: %s", op, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("kms %s returned invalid response: %w", op, err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Package kmstest 提供实现KMS HTTP接口的本地替身，供测试和本地开发使用
+package kmstest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/vera-byte/vgo-iam/internal/crypto"
+)
+
+// Handler 使用本地主密钥环实现KMS HTTP接口，token非空时校验Bearer令牌
+func Handler(keyring *crypto.LocalKeyring, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if token != "" && r.Header.Get("Authorization") != "Bearer "+token {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		// /v1/keys/{key_id}/{wrap|unwrap}
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/keys/"), "/")
+		if !strings.HasPrefix(r.URL.Path, "/v1/keys/") || len(parts) != 2 {
+			http.NotFound(w, r)
+			return
+		}
+		keyID, op := parts[0], parts[1]
+
+		switch op {
+		case "wrap":
+			var req crypto.KMSWrapRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if keyID != keyring.CurrentKeyID() {
+				http.Error(w, "key not found", http.StatusNotFound)
+				return
+			}
+			usedID, wrapped, err := keyring.WrapKey(r.Context(), req.Plaintext)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			writeJSON(w, crypto.KMSWrapResponse{KeyID: usedID, Ciphertext: wrapped})
+		case "unwrap":
+			var req crypto.KMSUnwrapRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			plaintext, err := keyring.UnwrapKey(r.Context(), keyID, req.Ciphertext)
+			if err != nil {
+				http.Error(w, "unwrap failed", http.StatusBadRequest)
+				return
+			}
+			writeJSON(w, crypto.KMSUnwrapResponse{Plaintext: plaintext})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// NewServer 启动KMS本地替身，使用完毕后调用Close
+func NewServer(keyring *crypto.LocalKeyring, token string) *httptest.Server {
+	return httptest.NewServer(Handler(keyring, token))
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
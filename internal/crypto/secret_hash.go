@@ -0,0 +1,84 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// ErrInvalidHash 哈希字符串格式不合法
+var ErrInvalidHash = errors.New("invalid secret hash")
+
+// Argon2Params Argon2id参数
+type Argon2Params struct {
+	Memory      uint32 // 内存开销(KiB)
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params 默认Argon2id参数(OWASP推荐的最低配置: 19MiB内存, 2次迭代)
+var DefaultArgon2Params = Argon2Params{
+	Memory:      19 * 1024,
+	Iterations:  2,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// HashSecret 使用Argon2id计算密钥哈希，返回PHC格式字符串:
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<hash>
+// 哈希不可逆，只能用VerifySecretHash校验
+func HashSecret(secret []byte, p Argon2Params) (string, error) {
+	salt := make([]byte, p.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	hash := argon2.IDKey(secret, salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash),
+	), nil
+}
+
+// VerifySecretHash 校验密钥与HashSecret生成的哈希是否匹配，使用常量时间比较
+func VerifySecretHash(encoded string, secret []byte) (bool, error) {
+	p, salt, hash, err := parseSecretHash(encoded)
+	if err != nil {
+		return false, err
+	}
+	computed := argon2.IDKey(secret, salt, p.Iterations, p.Memory, p.Parallelism, uint32(len(hash)))
+	return subtle.ConstantTimeCompare(computed, hash) == 1, nil
+}
+
+func parseSecretHash(encoded string) (Argon2Params, []byte, []byte, error) {
+	var p Argon2Params
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return p, nil, nil, ErrInvalidHash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, ErrInvalidHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil ||
+		p.Iterations == 0 || p.Parallelism == 0 {
+		return p, nil, nil, ErrInvalidHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, ErrInvalidHash
+	}
+	hash, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(hash) == 0 {
+		return p, nil, nil, ErrInvalidHash
+	}
+	return p, salt, hash, nil
+}
@@ -0,0 +1,40 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 附录B的SHA1测试向量，取后6位
+	secret := []byte("12345678901234567890")
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		step := TOTPStep(time.Unix(tt.unix, 0))
+		if got := TOTPCode(secret, step); got != tt.want {
+			t.Errorf("TOTPCode at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+
+	step := TOTPStep(time.Unix(1111111109, 0))
+	if matched, ok := MatchTOTPStep(secret, "081804", step-1, step+1); !ok || matched != step {
+		t.Errorf("MatchTOTPStep = %d, %v, want %d", matched, ok, step)
+	}
+	if _, ok := MatchTOTPStep(secret, "081804", step+1, step+2); ok {
+		t.Error("expected code outside window to be rejected")
+	}
+
+	uri := TOTPURI("vgo-iam", "alice", secret)
+	if !strings.HasPrefix(uri, "otpauth://totp/vgo-iam:alice?") || !strings.Contains(uri, "secret="+EncodeTOTPSecret(secret)) {
+		t.Errorf("unexpected otpauth URI %s", uri)
+	}
+}
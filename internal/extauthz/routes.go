@@ -0,0 +1,140 @@
+package extauthz
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vera-byte/vgo-iam/internal/auth"
+	"github.com/vera-byte/vgo-iam/internal/config"
+)
+
+// route 单条路由规则
+type route struct {
+	method   string   // 大写HTTP方法，"*"匹配所有方法
+	segments []string // 路径模式按"/"切分后的各段
+	mode     auth.AuthMode
+	action   string
+	resource string // 资源模板，可引用路径参数，如 orders:order:{id}
+}
+
+// Routes 按顺序匹配HTTP方法和路径，得到IAM操作和资源
+type Routes struct {
+	defaultMode auth.AuthMode
+	routes      []route
+}
+
+// Decision 路由匹配结果
+type Decision struct {
+	Mode     auth.AuthMode
+	Action   string
+	Resource string
+}
+
+// NewRoutes 根据配置创建路由规则
+// 路径模式的每段可以是字面量、"*"（任意一段）、"{name}"（任意一段并作为参数），
+// 最后一段可以是"**"（匹配剩余所有段）
+func NewRoutes(cfg config.ExtAuthzConfig) (*Routes, error) {
+	r := &Routes{defaultMode: auth.ModeAuthorized}
+	if cfg.DefaultMode != "" {
+		r.defaultMode = auth.AuthMode(cfg.DefaultMode)
+	}
+	if !validMode(r.defaultMode) {
+		return nil, fmt.Errorf("unknown auth mode %q", cfg.DefaultMode)
+	}
+
+	for _, rc := range cfg.Routes {
+		if !strings.HasPrefix(rc.Path, "/") {
+			return nil, fmt.Errorf("route path %q must start with /", rc.Path)
+		}
+		rt := route{
+			method:   strings.ToUpper(rc.Method),
+			segments: splitPath(rc.Path),
+			mode:     auth.AuthMode(rc.Mode),
+			action:   rc.Action,
+			resource: rc.Resource,
+		}
+		if rt.method == "" {
+			rt.method = "*"
+		}
+		if rt.mode == "" {
+			rt.mode = auth.ModeAuthorized
+		}
+		if !validMode(rt.mode) {
+			return nil, fmt.Errorf("route %s %s: unknown auth mode %q", rc.Method, rc.Path, rc.Mode)
+		}
+		if rt.mode == auth.ModeAuthorized && rt.action == "" {
+			return nil, fmt.Errorf("route %s %s: authorized mode requires an action", rc.Method, rc.Path)
+		}
+		if rt.resource == "" {
+			rt.resource = "*"
+		}
+		r.routes = append(r.routes, rt)
+	}
+	return r, nil
+}
+
+// Match 返回第一条匹配的路由对应的操作和资源，没有匹配时使用默认认证方式
+func (r *Routes) Match(method, path string) Decision {
+	method = strings.ToUpper(method)
+	segments := splitPath(path)
+	for _, rt := range r.routes {
+		if rt.method != "*" && rt.method != method {
+			continue
+		}
+		params, ok := matchSegments(rt.segments, segments)
+		if !ok {
+			continue
+		}
+		return Decision{Mode: rt.mode, Action: rt.action, Resource: expandResource(rt.resource, params)}
+	}
+	return Decision{Mode: r.defaultMode}
+}
+
+// matchSegments 匹配路径各段，返回路径参数
+func matchSegments(pattern, path []string) (map[string]string, bool) {
+	params := make(map[string]string)
+	for i, p := range pattern {
+		if p == "**" && i == len(pattern)-1 {
+			return params, true
+		}
+		if i >= len(path) {
+			return nil, false
+		}
+		switch {
+		case p == "*":
+		case strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}"):
+			params[p[1:len(p)-1]] = path[i]
+		case p != path[i]:
+			return nil, false
+		}
+	}
+	return params, len(pattern) == len(path)
+}
+
+// expandResource 将资源模板中的{name}替换为路径参数
+func expandResource(template string, params map[string]string) string {
+	for name, value := range params {
+		template = strings.ReplaceAll(template, "{"+name+"}", value)
+	}
+	return template
+}
+
+// splitPath 去掉查询参数后按"/"切分路径
+func splitPath(path string) []string {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func validMode(mode auth.AuthMode) bool {
+	switch mode {
+	case auth.ModePublic, auth.ModeAuthenticated, auth.ModeAuthorized:
+		return true
+	}
+	return false
+}
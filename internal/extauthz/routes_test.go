@@ -0,0 +1,52 @@
+package extauthz
+
+import (
+	"testing"
+
+	"github.com/vera-byte/vgo-iam/internal/auth"
+	"github.com/vera-byte/vgo-iam/internal/config"
+)
+
+func TestRoutesMatch(t *testing.T) {
+	routes, err := NewRoutes(config.ExtAuthzConfig{
+		Routes: []config.RouteConfig{
+			{Method: "GET", Path: "/healthz", Mode: "public"},
+			{Method: "GET", Path: "/orders/{id}", Action: "orders:GetOrder", Resource: "orders:order:{id}"},
+			{Method: "*", Path: "/orders/{id}/items/*", Action: "orders:ManageItems", Resource: "orders:order:{id}"},
+			{Path: "/static/**", Mode: "authenticated"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRoutes: %v", err)
+	}
+
+	cases := []struct {
+		method, path string
+		want         Decision
+	}{
+		{"GET", "/healthz", Decision{Mode: auth.ModePublic, Resource: "*"}},
+		{"GET", "/orders/42?expand=items", Decision{Mode: auth.ModeAuthorized, Action: "orders:GetOrder", Resource: "orders:order:42"}},
+		{"delete", "/orders/42/items/7", Decision{Mode: auth.ModeAuthorized, Action: "orders:ManageItems", Resource: "orders:order:42"}},
+		{"GET", "/static/css/site.css", Decision{Mode: auth.ModeAuthenticated, Resource: "*"}},
+		{"POST", "/orders/42", Decision{Mode: auth.ModeAuthorized}},
+		{"GET", "/orders", Decision{Mode: auth.ModeAuthorized}},
+	}
+	for _, c := range cases {
+		if got := routes.Match(c.method, c.path); got != c.want {
+			t.Errorf("Match(%s %s) = %+v, want %+v", c.method, c.path, got, c.want)
+		}
+	}
+}
+
+func TestNewRoutesRejectsInvalidConfig(t *testing.T) {
+	invalid := []config.ExtAuthzConfig{
+		{DefaultMode: "open"},
+		{Routes: []config.RouteConfig{{Path: "orders"}}},
+		{Routes: []config.RouteConfig{{Path: "/orders", Mode: "authorized"}}},
+	}
+	for _, cfg := range invalid {
+		if _, err := NewRoutes(cfg); err == nil {
+			t.Errorf("NewRoutes(%+v) succeeded, want error", cfg)
+		}
+	}
+}
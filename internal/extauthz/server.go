@@ -0,0 +1,217 @@
+package extauthz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"go.uber.org/zap"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vera-byte/vgo-iam/internal/auth"
+	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/util"
+	"github.com/vera-byte/vgo-iam/pkg/signer"
+)
+
+// 授权通过后传给上游服务的调用者信息头，客户端自带的同名头会被移除
+const (
+	HeaderPrincipal   = "x-iam-principal"
+	HeaderUser        = "x-iam-user"
+	HeaderAccessKeyID = "x-iam-access-key-id"
+	HeaderAccount     = "x-iam-account"
+)
+
+// headerPartialBody Envoy截断请求体时设置的头
+const headerPartialBody = "x-envoy-auth-partial-body"
+
+var principalHeaders = []string{HeaderPrincipal, HeaderUser, HeaderAccessKeyID, HeaderAccount}
+
+// errBodyNotForwarded 请求有请求体但Envoy没有转发完整请求体，无法校验签名覆盖的请求体
+var errBodyNotForwarded = errors.New("request body was not forwarded in full, enable with_request_body in envoy ext_authz")
+
+// Server Envoy外部授权服务(envoy.service.auth.v3.Authorization)
+// 校验HTTP请求的签名，按路由规则授权，通过时将调用者信息放入请求头
+type Server struct {
+	authv3.UnimplementedAuthorizationServer
+	authenticator *auth.Authenticator
+	routes        *Routes
+	service       string
+	trustDeclared bool // 未转发完整请求体时信任x-iam-content-sha256
+}
+
+// NewServer 创建外部授权服务，cfg.Service为客户端签名时凭证范围中的服务名
+func NewServer(authenticator *auth.Authenticator, routes *Routes, cfg config.ExtAuthzConfig) *Server {
+	return &Server{
+		authenticator: authenticator,
+		routes:        routes,
+		service:       cfg.Service,
+		trustDeclared: cfg.TrustDeclaredPayloadHash,
+	}
+}
+
+// Check 校验一次HTTP请求，拒绝时返回401/403
+func (s *Server) Check(ctx context.Context, req *authv3.CheckRequest) (*authv3.CheckResponse, error) {
+	attrs := req.GetAttributes()
+	httpReq := attrs.GetRequest().GetHttp()
+	operation := httpReq.GetMethod() + " " + pathOf(httpReq.GetPath())
+
+	decision := s.routes.Match(httpReq.GetMethod(), httpReq.GetPath())
+	if decision.Mode == auth.ModePublic {
+		return okResponse(nil), nil
+	}
+
+	r, err := toHTTPRequest(httpReq)
+	if err != nil {
+		return deniedResponse(status.Error(codes.InvalidArgument, "malformed request path")), nil
+	}
+	payloadHash, err := payloadHash(httpReq, s.trustDeclared)
+	if err != nil {
+		return deniedResponse(status.Error(codes.Unauthenticated, err.Error())), nil
+	}
+
+	sourceIP := attrs.GetSource().GetAddress().GetSocketAddress().GetAddress()
+	principal, err := s.authenticator.AuthenticateHTTP(r, s.service, payloadHash, sourceIP)
+	if err != nil {
+		return deniedResponse(err), nil
+	}
+
+	if decision.Mode == auth.ModeAuthorized {
+		if decision.Action == "" {
+			err = status.Errorf(codes.PermissionDenied, "no authorization rule for %s", operation)
+		} else {
+			err = s.authenticator.Authorize(ctx, principal, decision.Action, decision.Resource)
+		}
+		if err != nil {
+			util.Logger.Warn("HTTP request denied", append(principal.LogFields(),
+				zap.String("operation", operation), zap.Error(err))...)
+			return deniedResponse(err), nil
+		}
+	}
+
+	s.authenticator.RecordUsage(principal, operation)
+	return okResponse(principal), nil
+}
+
+// toHTTPRequest 由Envoy的请求属性构造用于计算规范请求的http.Request
+func toHTTPRequest(attrs *authv3.AttributeContext_HttpRequest) (*http.Request, error) {
+	u, err := url.ParseRequestURI(attrs.GetPath())
+	if err != nil {
+		return nil, err
+	}
+	header := make(http.Header, len(attrs.GetHeaders()))
+	for name, value := range attrs.GetHeaders() {
+		// 跳过HTTP/2伪头(:authority、:path等)
+		if strings.HasPrefix(name, ":") {
+			continue
+		}
+		header.Set(name, value)
+	}
+	return &http.Request{
+		Method: attrs.GetMethod(),
+		URL:    u,
+		Host:   attrs.GetHost(),
+		Header: header,
+	}, nil
+}
+
+// payloadHash 计算参与签名的请求体哈希
+// Envoy转发了完整请求体时使用实际请求体的哈希（与x-iam-content-sha256不一致时拒绝）；
+// 请求有请求体但未转发或被截断时拒绝，否则截获的请求可以换上任意请求体重放；
+// trustDeclared为true时改为使用已签名的x-iam-content-sha256，由上游服务校验请求体
+func payloadHash(attrs *authv3.AttributeContext_HttpRequest, trustDeclared bool) (string, error) {
+	headers := attrs.GetHeaders()
+	declared := headers[signer.HeaderContentSHA256]
+
+	body := attrs.GetRawBody()
+	if len(body) == 0 && attrs.GetBody() != "" {
+		body = []byte(attrs.GetBody())
+	}
+	partial := headers[headerPartialBody] == "true"
+	if len(body) > 0 && !partial {
+		return verifyDeclared(signer.HashPayload(body), declared)
+	}
+	if partial || hasBody(attrs) {
+		if !trustDeclared || declared == "" {
+			return "", errBodyNotForwarded
+		}
+		return declared, nil
+	}
+	return verifyDeclared(signer.HashPayload(nil), declared)
+}
+
+// verifyDeclared 客户端声明了x-iam-content-sha256时必须与实际请求体的哈希一致
+func verifyDeclared(hash, declared string) (string, error) {
+	if declared != "" && declared != hash {
+		return "", signer.ErrPayloadHashMismatch
+	}
+	return hash, nil
+}
+
+// hasBody 根据请求大小、Content-Length和Transfer-Encoding判断请求是否带有请求体
+func hasBody(attrs *authv3.AttributeContext_HttpRequest) bool {
+	if attrs.GetSize() > 0 {
+		return true
+	}
+	headers := attrs.GetHeaders()
+	if n, err := strconv.ParseInt(headers["content-length"], 10, 64); err == nil && n > 0 {
+		return true
+	}
+	return headers["transfer-encoding"] != ""
+}
+
+// okResponse 允许请求，认证的调用者信息通过请求头传给上游
+func okResponse(p *auth.Principal) *authv3.CheckResponse {
+	ok := &authv3.OkHttpResponse{HeadersToRemove: principalHeaders}
+	if p != nil {
+		ok.Headers = []*corev3.HeaderValueOption{
+			header(HeaderPrincipal, p.ARN()),
+			header(HeaderUser, p.UserName),
+			header(HeaderAccessKeyID, p.AccessKeyID),
+			header(HeaderAccount, p.Account),
+		}
+	}
+	return &authv3.CheckResponse{
+		Status:       &rpcstatus.Status{Code: int32(codes.OK)},
+		HttpResponse: &authv3.CheckResponse_OkResponse{OkResponse: ok},
+	}
+}
+
+// deniedResponse 拒绝请求，err为gRPC status错误
+func deniedResponse(err error) *authv3.CheckResponse {
+	st := status.Convert(err)
+	code := codes.PermissionDenied
+	if st.Code() == codes.Unauthenticated {
+		code = codes.Unauthenticated
+	}
+	return &authv3.CheckResponse{
+		Status: &rpcstatus.Status{Code: int32(code), Message: st.Message()},
+		HttpResponse: &authv3.CheckResponse_DeniedResponse{DeniedResponse: &authv3.DeniedHttpResponse{
+			Status: &typev3.HttpStatus{Code: typev3.StatusCode(auth.HTTPStatusFromCode(st.Code()))},
+			Body:   st.Message(),
+		}},
+	}
+}
+
+func header(key, value string) *corev3.HeaderValueOption {
+	return &corev3.HeaderValueOption{
+		Header:       &corev3.HeaderValue{Key: key, Value: value},
+		AppendAction: corev3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+	}
+}
+
+// pathOf 去掉查询参数
+func pathOf(path string) string {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
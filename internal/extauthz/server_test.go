@@ -0,0 +1,236 @@
+package extauthz
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+
+	"github.com/vera-byte/vgo-iam/internal/auth"
+	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/crypto"
+	"github.com/vera-byte/vgo-iam/internal/model"
+	"github.com/vera-byte/vgo-iam/internal/policy"
+	"github.com/vera-byte/vgo-iam/internal/service"
+	"github.com/vera-byte/vgo-iam/internal/store"
+	"github.com/vera-byte/vgo-iam/internal/util"
+	"github.com/vera-byte/vgo-iam/pkg/signer"
+)
+
+const (
+	testAccessKeyID = "AKIDEXAMPLE"
+	testSecret      = "secret"
+	testService     = "http"
+)
+
+// singleKeyStore 只保存一个签名凭证的访问密钥存储
+type singleKeyStore struct {
+	store.AccessKeyStore
+	ak *model.AccessKey
+}
+
+func (f *singleKeyStore) GetByAccessKeyID(accessKeyID string) (*model.AccessKey, error) {
+	copied := *f.ak
+	return &copied, nil
+}
+
+func (f *singleKeyStore) GetEncryptedSecrets(accessKeyID string) (*model.AccessKeySecrets, error) {
+	return &model.AccessKeySecrets{UserID: f.ak.UserID, Version: f.ak.SecretVersion, Current: f.ak.EncryptedSecretKey}, nil
+}
+
+// aliceUserStore 所有用户ID都对应alice，alice可以读取订单42
+type aliceUserStore struct {
+	store.UserStore
+}
+
+func (aliceUserStore) GetByID(id int) (*model.User, error) {
+	return &model.User{ID: id, Name: "alice"}, nil
+}
+
+func (aliceUserStore) GetUserPolicies(ctx context.Context, userID int) ([]*model.Policy, error) {
+	return []*model.Policy{{ID: 1, PolicyDocument: `{"Version":"1","Statement":[{"Effect":"Allow","Action":["orders:GetOrder","orders:CreateOrder"],"Resource":["orders:order:42"]}]}`}}, nil
+}
+
+// newTestServer 创建使用alice访问密钥和测试路由的外部授权服务
+func newTestServer(t *testing.T, trustDeclared bool) *Server {
+	t.Helper()
+	util.Logger = zap.NewNop()
+	local := crypto.NewLocalKeyring("test")
+	if err := local.AddKey("test", []byte("0123456789abcdef0123456789abcdef")); err != nil {
+		t.Fatalf("failed to add master key: %v", err)
+	}
+	keyring := crypto.NewKeyRotationManager(local)
+
+	ak := model.NewAccessKey(1, testAccessKeyID, testSecret, time.Hour)
+	encrypted, err := keyring.EncryptWithCurrentKey(context.Background(), []byte(testSecret), service.SecretAAD(ak.AccessKeyID, ak.UserID, ak.SecretVersion))
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	ak.EncryptedSecretKey = encrypted
+
+	users := aliceUserStore{}
+	rules, err := auth.NewMethodRules(config.AuthConfig{})
+	if err != nil {
+		t.Fatalf("NewMethodRules failed: %v", err)
+	}
+	authorizer := auth.NewAuthorizer(service.NewUserService(users, nil), policy.NewPolicyEngine(users))
+	authenticator := auth.NewAuthenticator(service.NewAccessKeyService(&singleKeyStore{ak: ak}, users, keyring, config.AccessKeyConfig{}),
+		nil, nil, auth.NewMemoryNonceStore(), authorizer, rules, nil)
+
+	cfg := config.ExtAuthzConfig{
+		Service:                  testService,
+		TrustDeclaredPayloadHash: trustDeclared,
+		Routes: []config.RouteConfig{
+			{Method: "GET", Path: "/healthz", Mode: "public"},
+			{Path: "/orders/{id}", Action: "orders:GetOrder", Resource: "orders:order:{id}"},
+			{Method: "POST", Path: "/orders", Action: "orders:CreateOrder", Resource: "orders:order:42"},
+		},
+	}
+	routes, err := NewRoutes(cfg)
+	if err != nil {
+		t.Fatalf("NewRoutes: %v", err)
+	}
+	return NewServer(authenticator, routes, cfg)
+}
+
+// checkRequest 签名HTTP请求并转换为Envoy转发的CheckRequest，body为空时不转发请求体
+func checkRequest(t *testing.T, method, path, body, secret string, extraHeaders map[string]string) *authv3.CheckRequest {
+	t.Helper()
+	r, err := http.NewRequest(method, "http://orders.example.com"+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	if err := signer.SignHTTPRequest(r, testAccessKeyID, secret, testService, time.Now()); err != nil {
+		t.Fatalf("SignHTTPRequest: %v", err)
+	}
+	headers := map[string]string{":authority": r.Host, ":path": path, ":method": method}
+	for name := range r.Header {
+		headers[strings.ToLower(name)] = r.Header.Get(name)
+	}
+	if body != "" {
+		headers["content-length"] = strconv.Itoa(len(body))
+	}
+	for name, value := range extraHeaders {
+		headers[name] = value
+	}
+	return &authv3.CheckRequest{Attributes: &authv3.AttributeContext{
+		Source: &authv3.AttributeContext_Peer{Address: &corev3.Address{Address: &corev3.Address_SocketAddress{
+			SocketAddress: &corev3.SocketAddress{Address: "10.0.0.1"},
+		}}},
+		Request: &authv3.AttributeContext_Request{Http: &authv3.AttributeContext_HttpRequest{
+			Method:  method,
+			Path:    path,
+			Host:    r.Host,
+			Headers: headers,
+			Size:    int64(len(body)),
+		}},
+	}}
+}
+
+// withBody 模拟Envoy开启with_request_body转发完整请求体
+func withBody(req *authv3.CheckRequest, body string) *authv3.CheckRequest {
+	req.Attributes.Request.Http.RawBody = []byte(body)
+	return req
+}
+
+// check 执行Check，返回gRPC状态码、允许时上游请求头、拒绝时的HTTP状态码
+func check(t *testing.T, s *Server, req *authv3.CheckRequest) (codes.Code, *authv3.OkHttpResponse, int) {
+	t.Helper()
+	resp, err := s.Check(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	httpStatus := 0
+	if denied := resp.GetDeniedResponse(); denied != nil {
+		httpStatus = int(denied.GetStatus().GetCode())
+	}
+	return codes.Code(resp.GetStatus().GetCode()), resp.GetOkResponse(), httpStatus
+}
+
+// upstreamHeaders 返回写入上游请求的头
+func upstreamHeaders(ok *authv3.OkHttpResponse) map[string]string {
+	headers := make(map[string]string)
+	for _, h := range ok.GetHeaders() {
+		headers[h.GetHeader().GetKey()] = h.GetHeader().GetValue()
+	}
+	return headers
+}
+
+func TestCheck(t *testing.T) {
+	s := newTestServer(t, false)
+
+	// 允许时写入调用者信息，并移除客户端自带的x-iam-principal
+	code, ok, _ := check(t, s, checkRequest(t, "GET", "/orders/42?expand=items", "", testSecret,
+		map[string]string{HeaderPrincipal: "iam:user:root"}))
+	if code != codes.OK || ok == nil {
+		t.Fatalf("expected OK, got %s", code)
+	}
+	headers := upstreamHeaders(ok)
+	if headers[HeaderPrincipal] != "iam:user:alice" || headers[HeaderUser] != "alice" || headers[HeaderAccessKeyID] != testAccessKeyID {
+		t.Errorf("unexpected principal headers %v", headers)
+	}
+	removed := strings.Join(ok.GetHeadersToRemove(), ",")
+	for _, name := range principalHeaders {
+		if !strings.Contains(removed, name) {
+			t.Errorf("expected %s in headers to remove, got %s", name, removed)
+		}
+	}
+
+	// 签名错误返回401
+	if code, _, httpStatus := check(t, s, checkRequest(t, "GET", "/orders/42", "", "wrong", nil)); code != codes.Unauthenticated || httpStatus != http.StatusUnauthorized {
+		t.Errorf("bad signature: code=%s status=%d, want 401", code, httpStatus)
+	}
+
+	// 策略不允许的路由返回403
+	if code, _, httpStatus := check(t, s, checkRequest(t, "GET", "/orders/7", "", testSecret, nil)); code != codes.PermissionDenied || httpStatus != http.StatusForbidden {
+		t.Errorf("unauthorized route: code=%s status=%d, want 403", code, httpStatus)
+	}
+	// 未匹配路由的默认方式为authorized，没有操作时拒绝
+	if code, _, httpStatus := check(t, s, checkRequest(t, "GET", "/admin", "", testSecret, nil)); code != codes.PermissionDenied || httpStatus != http.StatusForbidden {
+		t.Errorf("unmatched route: code=%s status=%d, want 403", code, httpStatus)
+	}
+
+	// 公开路由不需要签名，不写入调用者信息
+	public := &authv3.CheckRequest{Attributes: &authv3.AttributeContext{Request: &authv3.AttributeContext_Request{
+		Http: &authv3.AttributeContext_HttpRequest{Method: "GET", Path: "/healthz", Headers: map[string]string{HeaderPrincipal: "iam:user:root"}},
+	}}}
+	code, ok, _ = check(t, s, public)
+	if code != codes.OK || ok == nil || len(ok.GetHeaders()) != 0 || len(ok.GetHeadersToRemove()) != len(principalHeaders) {
+		t.Errorf("public route: code=%s response=%+v", code, ok)
+	}
+}
+
+func TestCheckRequestBody(t *testing.T) {
+	s := newTestServer(t, false)
+	body := `{"item":"book"}`
+
+	// 转发了完整请求体时校验实际请求体的哈希
+	if code, _, _ := check(t, s, withBody(checkRequest(t, "POST", "/orders", body, testSecret, nil), body)); code != codes.OK {
+		t.Errorf("full body: code=%s, want OK", code)
+	}
+	if code, _, httpStatus := check(t, s, withBody(checkRequest(t, "POST", "/orders", body, testSecret, nil), `{"item":"car"}`)); httpStatus != http.StatusUnauthorized {
+		t.Errorf("tampered body: code=%s status=%d, want 401", code, httpStatus)
+	}
+
+	// 有请求体但未转发或被截断时拒绝
+	if code, _, httpStatus := check(t, s, checkRequest(t, "POST", "/orders", body, testSecret, nil)); httpStatus != http.StatusUnauthorized {
+		t.Errorf("body not forwarded: code=%s status=%d, want 401", code, httpStatus)
+	}
+	partial := withBody(checkRequest(t, "POST", "/orders", body, testSecret, map[string]string{headerPartialBody: "true"}), body[:4])
+	if code, _, httpStatus := check(t, s, partial); httpStatus != http.StatusUnauthorized {
+		t.Errorf("partial body: code=%s status=%d, want 401", code, httpStatus)
+	}
+
+	// 开启trust_declared_payload_hash时使用已签名的x-iam-content-sha256
+	trusting := newTestServer(t, true)
+	if code, _, _ := check(t, trusting, checkRequest(t, "POST", "/orders", body, testSecret, nil)); code != codes.OK {
+		t.Errorf("trusted declared hash: code=%s, want OK", code)
+	}
+}
@@ -4,31 +4,95 @@ import (
 	"time"
 )
 
+// 访问密钥凭证类型
+const (
+	// CredentialTypeSigning 签名凭证，密钥加密保存，用于校验请求签名
+	CredentialTypeSigning = "signing"
+	// CredentialTypeAPIKey API密钥，只保存密钥的Argon2id哈希，以令牌形式直接出示
+	CredentialTypeAPIKey = "api_key"
+)
+
 // AccessKey 访问密钥模型
 // 修改AccessKey结构体
 type AccessKey struct {
-	ID                 int       `json:"id"`
-	UserID             int       `json:"user_id"`                   // 关联用户ID
-	AccessKeyID        string    `json:"access_key_id"`             // 访问密钥ID
-	SecretAccessKey    string    `json:"secret_access_key"`         // 密钥（仅创建时返回）
-	EncryptedSecretKey []byte    `json:"encrypted_secret_key"`      // 加密后的密钥
-	Status             string    `json:"status"`                    // 状态: active/inactive
-	CreatedAt          time.Time `json:"created_at"`                // 创建时间
-	UpdatedAt          time.Time `json:"updated_at"`                // 更新时间
-	UserName           string    `json:"user_name,omitempty"`       // 用户名（非数据库字段，仅用于返回）
-	ExpiresAt          time.Time `json:"expires_at,omitempty"`      // 添加过期时间
-	LastRotatedAt      time.Time `json:"last_rotated_at,omitempty"` // 添加最后轮换时间
+	ID                      int        `json:"id"`
+	UserID                  int        `json:"user_id"`                              // 关联用户ID
+	AccessKeyID             string     `json:"access_key_id"`                        // 访问密钥ID
+	SecretAccessKey         string     `json:"secret_access_key"`                    // 密钥（仅创建时返回）
+	EncryptedSecretKey      []byte     `json:"-"`                                    // 加密后的密钥（不对外返回），API密钥为空
+	SecretHash              string     `json:"-"`                                    // 密钥哈希（不对外返回），仅API密钥使用
+	CredentialType          string     `json:"credential_type"`                      // 凭证类型: signing/api_key
+	SecretVersion           int        `json:"secret_version"`                       // 密钥版本，每次轮换加1
+	Status                  string     `json:"status"`                               // 状态: active/inactive
+	CreatedAt               time.Time  `json:"created_at"`                           // 创建时间
+	UpdatedAt               time.Time  `json:"updated_at"`                           // 更新时间
+	UserName                string     `json:"user_name,omitempty"`                  // 用户名（非数据库字段，仅用于返回）
+	ExpiresAt               *time.Time `json:"expires_at,omitempty"`                 // 过期时间，nil表示永不过期
+	LastRotatedAt           *time.Time `json:"last_rotated_at,omitempty"`            // 最后轮换时间
+	PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at,omitempty"` // 轮换后旧密钥的失效时间
+	LastUsedAt              *time.Time `json:"last_used_at,omitempty"`               // 最后使用时间
+	LastUsedMethod          string     `json:"last_used_method,omitempty"`           // 最后使用的RPC方法
+	LastUsedIP              string     `json:"last_used_ip,omitempty"`               // 最后使用的来源IP
+}
+
+// AccessKeyUsage 访问密钥的一次使用记录
+type AccessKeyUsage struct {
+	AccessKeyID string
+	UsedAt      time.Time
+	Method      string
+	SourceIP    string
 }
 
-// 修改NewAccessKey方法
-func NewAccessKey(userID int, accessKeyID, secretKey string) *AccessKey {
-	return &AccessKey{
+// AccessKeySecrets 访问密钥当前可用于验签的密文
+type AccessKeySecrets struct {
+	UserID            int        // 所属用户ID，与版本一起用于校验密文归属
+	Version           int        // 当前密钥版本，旧密钥版本为Version-1
+	Current           []byte     // 当前密钥密文
+	Previous          []byte     // 轮换前的旧密钥密文，宽限期外为空
+	PreviousExpiresAt *time.Time // 旧密钥宽限期结束时间
+}
+
+// AccessKeySecretHashes API密钥校验所需的状态和密钥哈希
+type AccessKeySecretHashes struct {
+	UserID            int        // 所属用户ID
+	Status            string     // 状态: active/inactive
+	ExpiresAt         *time.Time // 过期时间，nil表示永不过期
+	Current           string     // 当前密钥哈希
+	Previous          string     // 轮换前的旧密钥哈希，宽限期外为空
+	PreviousExpiresAt *time.Time // 旧密钥宽限期结束时间
+}
+
+// EncryptedSecretRecord 访问密钥的密文记录，供主密钥轮换后重新加密使用
+type EncryptedSecretRecord struct {
+	ID          int    // access_keys表主键，重新加密任务按其顺序分批处理
+	AccessKeyID string // 访问密钥ID
+	UserID      int    // 所属用户ID
+	Version     int    // 当前密钥版本，旧密钥版本为Version-1
+	Current     []byte // 当前密钥密文
+	Previous    []byte // 轮换前的旧密钥密文，没有时为空
+}
+
+// NewAccessKey 创建访问密钥，ttl为0表示永不过期
+func NewAccessKey(userID int, accessKeyID, secretKey string, ttl time.Duration) *AccessKey {
+	now := time.Now()
+	ak := &AccessKey{
 		UserID:          userID,
 		AccessKeyID:     accessKeyID,
 		SecretAccessKey: secretKey,
+		SecretVersion:   1,
+		CredentialType:  CredentialTypeSigning,
 		Status:          "active",
-		CreatedAt:       time.Now(),
-		UpdatedAt:       time.Now(),
-		ExpiresAt:       time.Now().AddDate(0, 3, 0), // 默认3个月过期
+		CreatedAt:       now,
+		UpdatedAt:       now,
 	}
+	if ttl > 0 {
+		expiresAt := now.Add(ttl)
+		ak.ExpiresAt = &expiresAt
+	}
+	return ak
+}
+
+// IsExpired 判断访问密钥在指定时间是否已过期
+func (ak *AccessKey) IsExpired(now time.Time) bool {
+	return ak.ExpiresAt != nil && !now.Before(*ak.ExpiresAt)
 }
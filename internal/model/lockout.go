@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+)
+
+// AuthFailure 认证对象(用户、来源IP或访问密钥)在统计窗口内的失败记录
+type AuthFailure struct {
+	Subject         string     `json:"subject"`                // user:<name>、ip:<address> 或 key:<access_key_id>
+	Failures        int        `json:"failures"`               // 窗口内的失败次数
+	WindowStartedAt time.Time  `json:"window_started_at"`      // 窗口开始时间，即窗口内第一次失败的时间
+	LastFailureAt   time.Time  `json:"last_failure_at"`        // 最后一次失败时间，渐进延迟从此开始计算
+	LockedUntil     *time.Time `json:"locked_until,omitempty"` // 锁定结束时间，nil表示未锁定
+}
+
+// IsLocked 判断在now时是否处于锁定状态
+func (f *AuthFailure) IsLocked(now time.Time) bool {
+	return f.LockedUntil != nil && now.Before(*f.LockedUntil)
+}
+
+// LockoutEvent 认证对象被锁定时发出的事件
+type LockoutEvent struct {
+	Subject     string    `json:"subject"`
+	Failures    int       `json:"failures"`
+	SourceIP    string    `json:"source_ip,omitempty"` // 触发锁定的请求来源IP
+	LockedUntil time.Time `json:"locked_until"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+)
+
+// LoginProfile 用户的控制台登录配置
+type LoginProfile struct {
+	UserID                int       `json:"user_id"`
+	UserName              string    `json:"user_name"`
+	PasswordHash          string    `json:"-"`                       // Argon2id密码哈希（不导出）
+	PasswordChangedAt     time.Time `json:"password_changed_at"`     // 最后修改密码时间，用于计算密码有效期
+	PasswordResetRequired bool      `json:"password_reset_required"` // 下次登录时必须修改密码
+	CreatedAt             time.Time `json:"created_at"`              // 登录配置创建时间
+}
+
+// PasswordExpiresAt 返回密码过期时间，maxAge为0时返回nil
+func (p *LoginProfile) PasswordExpiresAt(maxAge time.Duration) *time.Time {
+	if maxAge <= 0 {
+		return nil
+	}
+	expiresAt := p.PasswordChangedAt.Add(maxAge)
+	return &expiresAt
+}
+
+// LoginSession 登录会话，数据库中只保存令牌的摘要
+type LoginSession struct {
+	Token      string    `json:"-"` // 会话令牌，仅创建时返回
+	UserID     int       `json:"user_id"`
+	UserName   string    `json:"user_name"`
+	SourceIP   string    `json:"source_ip,omitempty"`
+	MFAPresent bool      `json:"mfa_present"` // 创建会话时是否通过了MFA认证
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
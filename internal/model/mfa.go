@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+// MFA设备状态
+const (
+	// MFADeviceStatusPending 已创建，等待用户用两个连续验证码启用
+	MFADeviceStatusPending = "pending"
+	// MFADeviceStatusActive 已启用，登录时必须提供验证码
+	MFADeviceStatusActive = "active"
+)
+
+// MFADevice 虚拟MFA设备(TOTP)
+type MFADevice struct {
+	ID              int        `json:"id"`
+	UserID          int        `json:"user_id"`
+	UserName        string     `json:"user_name,omitempty"`   // 用户名（非数据库字段，仅用于返回）
+	SerialNumber    string     `json:"serial_number"`         // 设备序列号，格式 iam:mfa:<user_name>/<device_name>
+	EncryptedSecret []byte     `json:"-"`                     // 主密钥加密的TOTP密钥（不对外返回）
+	Secret          string     `json:"secret,omitempty"`      // Base32编码的TOTP密钥（仅创建时返回）
+	OTPAuthURI      string     `json:"otpauth_uri,omitempty"` // 供验证器应用扫描的otpauth URI（仅创建时返回）
+	Status          string     `json:"status"`                // 状态: pending/active
+	LastUsedStep    int64      `json:"-"`                     // 最后一次接受的TOTP时间步
+	CreatedAt       time.Time  `json:"created_at"`            // 创建时间
+	EnabledAt       *time.Time `json:"enabled_at,omitempty"`  // 启用时间
+}
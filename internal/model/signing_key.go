@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+// 令牌签名密钥状态
+const (
+	// SigningKeyStatusNext 已发布到JWKS，下次轮换时成为active，使依赖方提前缓存公钥
+	SigningKeyStatusNext = "next"
+	// SigningKeyStatusActive 当前用于签发令牌
+	SigningKeyStatusActive = "active"
+	// SigningKeyStatusRetired 已轮换，只用于校验它签发的令牌，令牌全部过期后删除
+	SigningKeyStatusRetired = "retired"
+)
+
+// SigningKey 访问令牌的签名密钥
+type SigningKey struct {
+	ID                  int        `json:"id"`
+	KeyID               string     `json:"kid" db:"kid"`           // JWT头部的kid，公钥的RFC 7638指纹
+	Algorithm           string     `json:"alg"`                    // 签名算法，目前只有EdDSA
+	PublicKey           []byte     `json:"-"`                      // Ed25519公钥
+	EncryptedPrivateKey []byte     `json:"-"`                      // 主密钥加密的Ed25519私钥种子（不对外返回）
+	Status              string     `json:"status"`                 // 状态: next/active/retired
+	CreatedAt           time.Time  `json:"created_at"`             // 创建时间
+	ActivatedAt         *time.Time `json:"activated_at,omitempty"` // 成为active的时间
+	RetiredAt           *time.Time `json:"retired_at,omitempty"`   // 被轮换的时间
+}
@@ -4,13 +4,15 @@ import (
 	"time"
 )
 
+// DefaultAccount 单账户部署时使用的账户标识
+const DefaultAccount = "default"
+
 // User 用户模型
 type User struct {
 	ID          int       `json:"id"`
 	Name        string    `json:"name"`         // 用户名（唯一）
 	DisplayName string    `json:"display_name"` // 显示名称
 	Email       string    `json:"email"`        // 邮箱（唯一）
-	Password    string    `json:"-"`            // 密码（不导出）
 	CreatedAt   time.Time `json:"created_at"`   // 创建时间
 	UpdatedAt   time.Time `json:"updated_at"`   // 更新时间
 }
@@ -29,12 +31,11 @@ type Statement struct {
 }
 
 // NewUser 创建新用户
-func NewUser(name, displayName, email, password string) *User {
+func NewUser(name, displayName, email string) *User {
 	return &User{
 		Name:        name,
 		DisplayName: displayName,
 		Email:       email,
-		Password:    password,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
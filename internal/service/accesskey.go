@@ -6,30 +6,74 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/patrickmn/go-cache"
+	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/crypto"
 	"github.com/vera-byte/vgo-iam/internal/model"
 	"github.com/vera-byte/vgo-iam/internal/store"
 	"github.com/vera-byte/vgo-iam/internal/util"
 	"go.uber.org/zap"
 )
 
+// secretCacheTTL 解密后的密钥在内存中的缓存时间
+const secretCacheTTL = time.Minute
+
+var (
+	// ErrInvalidKeyTTL 请求的访问密钥有效期不合法
+	ErrInvalidKeyTTL = errors.New("access key ttl exceeds the maximum lifetime")
+	// ErrInvalidGracePeriod 轮换宽限期不合法
+	ErrInvalidGracePeriod = errors.New("rotation grace period must not be negative")
+	// ErrAccessKeyInactive 访问密钥未激活
+	ErrAccessKeyInactive = errors.New("access key is inactive")
+	// ErrRotationInProgress 上次轮换的旧密钥仍在宽限期内，再次轮换会使其提前失效
+	ErrRotationInProgress = errors.New("previous secret is still within its rotation grace period")
+	// ErrAccessKeyActive 访问密钥仍处于激活状态
+	ErrAccessKeyActive = store.ErrAccessKeyActive
+	// ErrInvalidCredentialType 凭证类型不合法
+	ErrInvalidCredentialType = errors.New("credential type must be either 'signing' or 'api_key'")
+)
+
 // AccessKeyService 访问密钥服务
 type AccessKeyService struct {
 	accessKeyStore store.AccessKeyStore
 	userStore      store.UserStore
-	masterKey      []byte
+	keyring        *crypto.KeyRotationManager
+	cfg            config.AccessKeyConfig
+	secretCache    *cache.Cache // 解密后的密钥缓存，避免每次请求都查库解密
+	apiKeyCache    *cache.Cache // 已校验通过的API密钥摘要缓存，避免每次请求都计算Argon2id
+	usage          *usageRecorder
 }
 
-// NewAccessKeyService 创建访问密钥服务实例
-func NewAccessKeyService(accessKeyStore store.AccessKeyStore, userStore store.UserStore, masterKey []byte) *AccessKeyService {
+// NewAccessKeyService 创建访问密钥服务实例，keyring用于加解密访问密钥
+func NewAccessKeyService(accessKeyStore store.AccessKeyStore, userStore store.UserStore, keyring *crypto.KeyRotationManager, cfg config.AccessKeyConfig) *AccessKeyService {
 	return &AccessKeyService{
 		accessKeyStore: accessKeyStore,
 		userStore:      userStore,
-		masterKey:      masterKey,
+		keyring:        keyring,
+		cfg:            cfg,
+		secretCache:    cache.New(secretCacheTTL, 2*secretCacheTTL),
+		apiKeyCache:    cache.New(secretCacheTTL, 2*secretCacheTTL),
+		usage:          newUsageRecorder(accessKeyStore),
 	}
 }
 
 // CreateAccessKey 创建访问密钥
-func (s *AccessKeyService) CreateAccessKey(ctx context.Context, userName string) (*model.AccessKey, error) {
+// ttl为0时使用配置的默认有效期，超过配置的最长有效期时返回ErrInvalidKeyTTL
+// credentialType为空时创建签名凭证；api_key只保存密钥哈希，明文密钥仅本次返回
+func (s *AccessKeyService) CreateAccessKey(ctx context.Context, userName string, ttl time.Duration, credentialType string) (*model.AccessKey, error) {
+	if credentialType == "" {
+		credentialType = model.CredentialTypeSigning
+	}
+	if credentialType != model.CredentialTypeSigning && credentialType != model.CredentialTypeAPIKey {
+		return nil, ErrInvalidCredentialType
+	}
+	if ttl == 0 {
+		ttl = s.cfg.DefaultTTL
+	}
+	if ttl < 0 || (s.cfg.MaxTTL > 0 && ttl > s.cfg.MaxTTL) {
+		return nil, ErrInvalidKeyTTL
+	}
+
 	// 获取用户
 	user, err := s.userStore.GetByName(userName)
 	if err != nil {
@@ -40,9 +84,20 @@ func (s *AccessKeyService) CreateAccessKey(ctx context.Context, userName string)
 	accessKeyID := util.GenerateAccessKeyID()
 	secretKey := util.GenerateSecretAccessKey()
 
-	// 创建访问密钥
-	ak := model.NewAccessKey(user.ID, accessKeyID, secretKey)
-	if err := s.accessKeyStore.Create(ak, s.masterKey); err != nil {
+	// 签名凭证加密密钥后保存，API密钥只保存哈希
+	ak := model.NewAccessKey(user.ID, accessKeyID, secretKey, ttl)
+	ak.CredentialType = credentialType
+	if credentialType == model.CredentialTypeAPIKey {
+		if ak.SecretHash, err = crypto.HashSecret([]byte(secretKey), crypto.DefaultArgon2Params); err != nil {
+			return nil, fmt.Errorf("failed to hash secret access key: %w", err)
+		}
+	} else {
+		ak.EncryptedSecretKey, err = s.keyring.EncryptWithCurrentKey(ctx, []byte(secretKey), SecretAAD(accessKeyID, user.ID, ak.SecretVersion))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt secret access key: %w", err)
+		}
+	}
+	if err := s.accessKeyStore.Create(ak, s.cfg.MaxKeysPerUser); err != nil {
 		return nil, fmt.Errorf("failed to create access key: %w", err)
 	}
 
@@ -72,6 +127,8 @@ func (s *AccessKeyService) UpdateStatus(ctx context.Context, accessKeyID, status
 		return nil, fmt.Errorf("failed to update access key status: %w", err)
 	}
 
+	s.forgetSecrets(accessKeyID)
+
 	// 获取更新后的密钥信息
 	ak, err := s.accessKeyStore.GetByAccessKeyID(accessKeyID)
 	if err != nil {
@@ -91,18 +148,138 @@ func (s *AccessKeyService) UpdateAccessKeyStatus(ctx context.Context, accessKeyI
 	if err := s.accessKeyStore.UpdateStatus(accessKeyID, status); err != nil {
 		return nil, err
 	}
+	s.forgetSecrets(accessKeyID)
 
 	// 获取更新后的密钥
 	return s.accessKeyStore.GetByAccessKeyID(accessKeyID)
 }
 
+// DeleteAccessKey 删除访问密钥，未指定force时只允许删除未激活的密钥
+func (s *AccessKeyService) DeleteAccessKey(ctx context.Context, accessKeyID string, force bool) error {
+	if err := s.accessKeyStore.Delete(accessKeyID, force); err != nil {
+		return fmt.Errorf("failed to delete access key: %w", err)
+	}
+	s.forgetSecrets(accessKeyID)
+	return nil
+}
+
 // RotateAccessKey 轮换访问密钥
-func (s *AccessKeyService) RotateAccessKey(ctx context.Context, accessKeyID string) (*model.AccessKey, error) {
-	return s.accessKeyStore.RotateKey(accessKeyID, s.masterKey)
+// 生成新的随机密钥，旧密钥在宽限期内仍然有效；grace为nil时使用配置的默认宽限期，为0时旧密钥立即失效(如密钥泄露)
+// 上次轮换的旧密钥仍在宽限期内时返回ErrRotationInProgress，避免已承诺有效的旧密钥被覆盖
+func (s *AccessKeyService) RotateAccessKey(ctx context.Context, accessKeyID string, grace *time.Duration) (*model.AccessKey, error) {
+	gracePeriod := s.cfg.RotationGracePeriod
+	if grace != nil {
+		gracePeriod = *grace
+	}
+	if gracePeriod < 0 {
+		return nil, ErrInvalidGracePeriod
+	}
+
+	ak, err := s.GetAccessKey(ctx, accessKeyID)
+	if err != nil {
+		return nil, err
+	}
+	if ak.Status != "active" {
+		return nil, ErrAccessKeyInactive
+	}
+	now := time.Now()
+	if ak.PreviousSecretExpiresAt != nil && ak.PreviousSecretExpiresAt.After(now) {
+		return nil, ErrRotationInProgress
+	}
+
+	// 生成新密钥，签名凭证加密保存，API密钥只保存哈希
+	newSecret := util.GenerateSecretAccessKey()
+	graceUntil := now.Add(gracePeriod)
+	if ak.CredentialType == model.CredentialTypeAPIKey {
+		secretHash, err := crypto.HashSecret([]byte(newSecret), crypto.DefaultArgon2Params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash secret access key: %w", err)
+		}
+		if err := s.accessKeyStore.RotateSecretHash(accessKeyID, ak.SecretVersion, secretHash, graceUntil); err != nil {
+			return nil, fmt.Errorf("failed to rotate access key: %w", err)
+		}
+	} else {
+		encryptedSecret, err := s.keyring.EncryptWithCurrentKey(ctx, []byte(newSecret), SecretAAD(accessKeyID, ak.UserID, ak.SecretVersion+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt secret access key: %w", err)
+		}
+		if err := s.accessKeyStore.RotateKey(accessKeyID, ak.SecretVersion, encryptedSecret, graceUntil); err != nil {
+			return nil, fmt.Errorf("failed to rotate access key: %w", err)
+		}
+	}
+	s.forgetSecrets(accessKeyID)
+
+	rotated, err := s.GetAccessKey(ctx, accessKeyID)
+	if err != nil {
+		return nil, err
+	}
+	// 新密钥仅本次返回
+	rotated.SecretAccessKey = newSecret
+	return rotated, nil
 }
 
-// GetAccessKey 根据访问密钥ID获取访问密钥
+// ResolveSecrets 获取访问密钥当前可用于验签的明文密钥
+// 第一个为当前密钥，处于轮换宽限期时还包含旧密钥
+// 密文由存储层读取，在服务层按密文中的主密钥ID解密，结果短时间缓存在内存中
+func (s *AccessKeyService) ResolveSecrets(ctx context.Context, accessKeyID string) ([]string, error) {
+	if cached, found := s.secretCache.Get(accessKeyID); found {
+		return cached.([]string), nil
+	}
+
+	encrypted, err := s.accessKeyStore.GetEncryptedSecrets(accessKeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get encrypted secret: %w", err)
+	}
+
+	current, err := s.keyring.DecryptWithAnyKey(ctx, encrypted.Current, SecretAAD(accessKeyID, encrypted.UserID, encrypted.Version))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+	secrets := []string{string(current)}
+
+	// 缓存时间不超过旧密钥宽限期，保证旧密钥按时失效
+	ttl := secretCacheTTL
+	now := time.Now()
+	if encrypted.Previous != nil && encrypted.PreviousExpiresAt.After(now) {
+		previous, err := s.keyring.DecryptWithAnyKey(ctx, encrypted.Previous, SecretAAD(accessKeyID, encrypted.UserID, encrypted.Version-1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt previous secret: %w", err)
+		}
+		secrets = append(secrets, string(previous))
+		if remaining := encrypted.PreviousExpiresAt.Sub(now); remaining < ttl {
+			ttl = remaining
+		}
+	}
+
+	s.secretCache.Set(accessKeyID, secrets, ttl)
+	return secrets, nil
+}
+
+// forgetSecrets 清除访问密钥在内存中的密钥和API密钥缓存，状态变更、轮换和删除后调用
+func (s *AccessKeyService) forgetSecrets(accessKeyID string) {
+	s.secretCache.Delete(accessKeyID)
+	s.apiKeyCache.Delete(accessKeyID)
+}
+
+// SecretAAD 返回访问密钥密文的AES-GCM附加数据，将密文绑定到访问密钥ID、所属用户和密钥版本
+// 数据库中的密文被复制到其他访问密钥、其他用户或替换为旧版本后无法解密
+func SecretAAD(accessKeyID string, userID, version int) []byte {
+	return []byte(fmt.Sprintf("vgo-iam:access-key-secret\x00%s\x00%d\x00%d", accessKeyID, userID, version))
+}
+
+// RetirePreviousSecrets 清理轮换宽限期已结束的旧密钥
+func (s *AccessKeyService) RetirePreviousSecrets(ctx context.Context) error {
+	retired, err := s.accessKeyStore.RetireExpiredPreviousSecrets(time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to retire previous secrets: %w", err)
+	}
+	if retired > 0 {
+		util.Logger.Info("Retired rotated access key secrets", zap.Int64("count", retired))
+	}
+	return nil
+}
 
+// GetAccessKey 根据访问密钥ID获取访问密钥
 func (s *AccessKeyService) GetAccessKey(ctx context.Context, accessKeyID string) (*model.AccessKey, error) {
 	// 参数检查
 	if accessKeyID == "" {
@@ -132,24 +309,67 @@ func (s *AccessKeyService) GetStore() store.AccessKeyStore {
 	return s.accessKeyStore
 }
 
-// 添加密钥轮换检查
-func (s *AccessKeyService) CheckAndRotateExpiredKeys(ctx context.Context) error {
-	// 获取所有访问密钥
-	allKeys, err := s.accessKeyStore.ListAll()
+// CheckExpiredKeys 停用已过期的访问密钥，并对即将过期的密钥输出告警，每个密钥只告警一次
+func (s *AccessKeyService) CheckExpiredKeys(ctx context.Context) error {
+	now := time.Now()
+	warnBefore := now.AddDate(0, 0, s.cfg.ExpiryWarningDays)
+
+	keys, err := s.accessKeyStore.ListExpiringBefore(warnBefore)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to list expiring access keys: %w", err)
 	}
 
-	// 检查并轮换过期密钥（假设90天过期）
-	expiryDuration := 90 * 24 * time.Hour
-	now := time.Now()
-
-	for _, key := range allKeys {
-		if now.Sub(key.CreatedAt) > expiryDuration {
-			if _, err := s.RotateAccessKey(ctx, key.AccessKeyID); err != nil {
-				util.Logger.Warn("Failed to rotate expired key", zap.String("access_key_id", key.AccessKeyID))
+	for _, key := range keys {
+		if !key.IsExpired(now) {
+			notified, err := s.accessKeyStore.MarkExpiryNotified(key.AccessKeyID)
+			if err != nil {
+				util.Logger.Error("Failed to record access key expiry notification",
+					zap.String("access_key_id", key.AccessKeyID), zap.Error(err))
+				continue
 			}
+			if !notified {
+				continue
+			}
+			util.Logger.Warn("Access key is about to expire",
+				zap.String("access_key_id", key.AccessKeyID),
+				zap.Int("user_id", key.UserID),
+				zap.Time("expires_at", *key.ExpiresAt))
+			continue
 		}
+
+		if _, err := s.UpdateStatus(ctx, key.AccessKeyID, "inactive"); err != nil {
+			util.Logger.Error("Failed to deactivate expired access key",
+				zap.String("access_key_id", key.AccessKeyID), zap.Error(err))
+			continue
+		}
+		util.Logger.Warn("Expired access key deactivated",
+			zap.String("access_key_id", key.AccessKeyID),
+			zap.Int("user_id", key.UserID),
+			zap.Time("expires_at", *key.ExpiresAt))
 	}
 	return nil
 }
+
+// RunExpiryJob 按配置的间隔周期性执行过期检查和旧密钥清理，直到ctx取消
+func (s *AccessKeyService) RunExpiryJob(ctx context.Context) {
+	interval := s.cfg.ExpiryCheckInterval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.CheckExpiredKeys(ctx); err != nil {
+			util.Logger.Error("Access key expiry check failed", zap.Error(err))
+		}
+		if err := s.RetirePreviousSecrets(ctx); err != nil {
+			util.Logger.Error("Access key secret retirement failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/crypto"
+	"github.com/vera-byte/vgo-iam/internal/model"
+	"github.com/vera-byte/vgo-iam/internal/store"
+	"github.com/vera-byte/vgo-iam/internal/util"
+)
+
+var testMasterKey = []byte("0123456789abcdef0123456789abcdef")
+
+// fakeAccessKeyStore 仅实现测试所需方法的访问密钥存储
+type fakeAccessKeyStore struct {
+	store.AccessKeyStore
+	secrets map[string]*model.AccessKeySecrets
+	reads   int
+}
+
+func (f *fakeAccessKeyStore) GetEncryptedSecrets(accessKeyID string) (*model.AccessKeySecrets, error) {
+	f.reads++
+	return f.secrets[accessKeyID], nil
+}
+
+// memAccessKeyStore 内存中的访问密钥存储，只实现访问密钥元数据相关的方法
+type memAccessKeyStore struct {
+	store.AccessKeyStore
+	mu       sync.Mutex
+	keys     map[string]*model.AccessKey
+	notified map[string]bool
+}
+
+func newMemAccessKeyStore(keys ...*model.AccessKey) *memAccessKeyStore {
+	f := &memAccessKeyStore{keys: make(map[string]*model.AccessKey), notified: make(map[string]bool)}
+	for _, ak := range keys {
+		f.keys[ak.AccessKeyID] = ak
+	}
+	return f
+}
+
+func (f *memAccessKeyStore) GetByAccessKeyID(accessKeyID string) (*model.AccessKey, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ak, ok := f.keys[accessKeyID]
+	if !ok {
+		return nil, dbr.ErrNotFound
+	}
+	copied := *ak
+	return &copied, nil
+}
+
+func (f *memAccessKeyStore) UpdateStatus(accessKeyID, status string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if ak, ok := f.keys[accessKeyID]; ok {
+		ak.Status = status
+	}
+	return nil
+}
+
+func (f *memAccessKeyStore) ListExpiringBefore(t time.Time) ([]*model.AccessKey, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var keys []*model.AccessKey
+	for _, ak := range f.keys {
+		if ak.Status == "active" && ak.ExpiresAt != nil && !ak.ExpiresAt.After(t) {
+			copied := *ak
+			keys = append(keys, &copied)
+		}
+	}
+	return keys, nil
+}
+
+func (f *memAccessKeyStore) MarkExpiryNotified(accessKeyID string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.notified[accessKeyID] {
+		return false, nil
+	}
+	f.notified[accessKeyID] = true
+	return true, nil
+}
+
+// newLocalKeyring 创建包含testMasterKey(ID为test)及其他主密钥的本地主密钥环
+func newLocalKeyring(t *testing.T, previous map[string][]byte) *crypto.LocalKeyring {
+	t.Helper()
+	local := crypto.NewLocalKeyring("test")
+	if err := local.AddKey("test", testMasterKey); err != nil {
+		t.Fatalf("failed to add master key: %v", err)
+	}
+	for id, key := range previous {
+		if err := local.AddKey(id, key); err != nil {
+			t.Fatalf("failed to add master key: %v", err)
+		}
+	}
+	return local
+}
+
+func newTestKeyring(t *testing.T) *crypto.KeyRotationManager {
+	return crypto.NewKeyRotationManager(newLocalKeyring(t, nil))
+}
+
+// mustEncrypt 使用主密钥生成没有密钥ID前缀的旧格式密文
+func mustEncrypt(t *testing.T, plaintext string) []byte {
+	t.Helper()
+	encrypted, err := crypto.EncryptKey([]byte(plaintext), testMasterKey, nil)
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	return encrypted
+}
+
+func TestResolveSecrets(t *testing.T) {
+	akStore := &fakeAccessKeyStore{secrets: map[string]*model.AccessKeySecrets{
+		"AKID": {Current: mustEncrypt(t, "plain-secret")},
+	}}
+	svc := NewAccessKeyService(akStore, nil, newTestKeyring(t), config.AccessKeyConfig{})
+
+	for i := 0; i < 2; i++ {
+		secrets, err := svc.ResolveSecrets(context.Background(), "AKID")
+		if err != nil {
+			t.Fatalf("ResolveSecrets failed: %v", err)
+		}
+		if len(secrets) != 1 || secrets[0] != "plain-secret" {
+			t.Errorf("unexpected secrets: %q", secrets)
+		}
+	}
+	if akStore.reads != 1 {
+		t.Errorf("expected secret to be cached, store read %d times", akStore.reads)
+	}
+}
+
+func TestResolveSecretsDuringGracePeriod(t *testing.T) {
+	graceUntil := time.Now().Add(time.Hour)
+	expiredAt := time.Now().Add(-time.Minute)
+	akStore := &fakeAccessKeyStore{secrets: map[string]*model.AccessKeySecrets{
+		"ROTATED": {
+			Current:           mustEncrypt(t, "new-secret"),
+			Previous:          mustEncrypt(t, "old-secret"),
+			PreviousExpiresAt: &graceUntil,
+		},
+		"RETIRED": {
+			Current:           mustEncrypt(t, "new-secret"),
+			Previous:          mustEncrypt(t, "old-secret"),
+			PreviousExpiresAt: &expiredAt,
+		},
+	}}
+	svc := NewAccessKeyService(akStore, nil, newTestKeyring(t), config.AccessKeyConfig{})
+
+	secrets, err := svc.ResolveSecrets(context.Background(), "ROTATED")
+	if err != nil {
+		t.Fatalf("ResolveSecrets failed: %v", err)
+	}
+	if len(secrets) != 2 || secrets[0] != "new-secret" || secrets[1] != "old-secret" {
+		t.Errorf("expected new and old secrets during grace period, got %q", secrets)
+	}
+
+	secrets, err = svc.ResolveSecrets(context.Background(), "RETIRED")
+	if err != nil {
+		t.Fatalf("ResolveSecrets failed: %v", err)
+	}
+	if len(secrets) != 1 || secrets[0] != "new-secret" {
+		t.Errorf("expected only the new secret after grace period, got %q", secrets)
+	}
+}
+
+func TestCheckExpiredKeys(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	util.Logger = zap.New(core)
+
+	now := time.Now()
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	akStore := newMemAccessKeyStore(
+		&model.AccessKey{AccessKeyID: "EXPIRED", UserID: 1, Status: "active", ExpiresAt: at(-time.Hour)},
+		&model.AccessKey{AccessKeyID: "EXPIRING", UserID: 1, Status: "active", ExpiresAt: at(48 * time.Hour)},
+		&model.AccessKey{AccessKeyID: "LATER", UserID: 1, Status: "active", ExpiresAt: at(30 * 24 * time.Hour)},
+		&model.AccessKey{AccessKeyID: "FOREVER", UserID: 1, Status: "active"},
+	)
+	svc := NewAccessKeyService(akStore, fakeUserStore{}, nil, config.AccessKeyConfig{ExpiryWarningDays: 7})
+
+	if !akStore.keys["EXPIRED"].IsExpired(now) || akStore.keys["EXPIRING"].IsExpired(now) || akStore.keys["FOREVER"].IsExpired(now) {
+		t.Fatal("unexpected IsExpired result")
+	}
+	if !akStore.keys["EXPIRING"].IsExpired(*akStore.keys["EXPIRING"].ExpiresAt) {
+		t.Error("expected key to be expired at its expiry time")
+	}
+
+	if err := svc.CheckExpiredKeys(context.Background()); err != nil {
+		t.Fatalf("CheckExpiredKeys failed: %v", err)
+	}
+	for id, want := range map[string]string{"EXPIRED": "inactive", "EXPIRING": "active", "LATER": "active", "FOREVER": "active"} {
+		if got := akStore.keys[id].Status; got != want {
+			t.Errorf("%s: expected status %s, got %s", id, want, got)
+		}
+	}
+
+	// 只有窗口内即将过期的密钥输出告警
+	deactivated := logs.FilterMessage("Expired access key deactivated").All()
+	warned := logs.FilterMessage("Access key is about to expire").All()
+	if len(deactivated) != 1 || deactivated[0].ContextMap()["access_key_id"] != "EXPIRED" {
+		t.Errorf("expected EXPIRED to be deactivated, got %v", deactivated)
+	}
+	if len(warned) != 1 || warned[0].ContextMap()["access_key_id"] != "EXPIRING" {
+		t.Errorf("expected a warning for EXPIRING only, got %v", warned)
+	}
+
+	// 再次检查时不重复告警
+	if err := svc.CheckExpiredKeys(context.Background()); err != nil {
+		t.Fatalf("CheckExpiredKeys failed: %v", err)
+	}
+	if warned := logs.FilterMessage("Access key is about to expire").Len(); warned != 1 {
+		t.Errorf("expected a single expiry warning across checks, got %d", warned)
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vera-byte/vgo-iam/internal/model"
+	"github.com/vera-byte/vgo-iam/internal/store"
+	"github.com/vera-byte/vgo-iam/internal/util"
+	"go.uber.org/zap"
+)
+
+// usageRecorder 异步批量记录访问密钥最后使用信息
+// 认证路径只写内存，由后台任务定期批量写入数据库，同一密钥只保留最近一次使用
+type usageRecorder struct {
+	accessKeyStore store.AccessKeyStore
+	mu             sync.Mutex
+	pending        map[string]model.AccessKeyUsage
+}
+
+func newUsageRecorder(accessKeyStore store.AccessKeyStore) *usageRecorder {
+	return &usageRecorder{
+		accessKeyStore: accessKeyStore,
+		pending:        make(map[string]model.AccessKeyUsage),
+	}
+}
+
+// record 记录一次使用，不访问数据库
+func (r *usageRecorder) record(usage model.AccessKeyUsage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if prev, ok := r.pending[usage.AccessKeyID]; ok && prev.UsedAt.After(usage.UsedAt) {
+		return
+	}
+	r.pending[usage.AccessKeyID] = usage
+}
+
+// flush 将待写入的使用记录批量写入数据库，失败时放回队列等待下次写入
+func (r *usageRecorder) flush() error {
+	r.mu.Lock()
+	if len(r.pending) == 0 {
+		r.mu.Unlock()
+		return nil
+	}
+	batch := make([]model.AccessKeyUsage, 0, len(r.pending))
+	for _, usage := range r.pending {
+		batch = append(batch, usage)
+	}
+	r.pending = make(map[string]model.AccessKeyUsage)
+	r.mu.Unlock()
+
+	if err := r.accessKeyStore.UpdateLastUsed(batch); err != nil {
+		for _, usage := range batch {
+			r.record(usage)
+		}
+		return err
+	}
+	return nil
+}
+
+// RecordUsage 记录访问密钥的一次成功认证
+func (s *AccessKeyService) RecordUsage(accessKeyID, method, sourceIP string) {
+	s.usage.record(model.AccessKeyUsage{
+		AccessKeyID: accessKeyID,
+		UsedAt:      time.Now(),
+		Method:      method,
+		SourceIP:    sourceIP,
+	})
+}
+
+// RunUsageFlusher 按配置的间隔将使用记录写入数据库，ctx取消时执行最后一次写入
+func (s *AccessKeyService) RunUsageFlusher(ctx context.Context) {
+	interval := s.cfg.LastUsedFlushInterval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.usage.flush(); err != nil {
+				util.Logger.Error("Failed to flush access key usage", zap.Error(err))
+			}
+			return
+		case <-ticker.C:
+			if err := s.usage.flush(); err != nil {
+				util.Logger.Warn("Failed to flush access key usage, will retry", zap.Error(err))
+			}
+		}
+	}
+}
+
+// GetLastUsed 获取访问密钥最后使用信息，尚未写入数据库的记录优先
+func (s *AccessKeyService) GetLastUsed(ctx context.Context, accessKeyID string) (*model.AccessKey, error) {
+	ak, err := s.GetAccessKey(ctx, accessKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.usage.mu.Lock()
+	usage, ok := s.usage.pending[accessKeyID]
+	s.usage.mu.Unlock()
+	if ok {
+		ak.LastUsedAt = &usage.UsedAt
+		ak.LastUsedMethod = usage.Method
+		ak.LastUsedIP = usage.SourceIP
+	}
+	return ak, nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/model"
+	"github.com/vera-byte/vgo-iam/internal/util"
+)
+
+// usageStore 记录每次批量写入的访问密钥存储，onWrite可以模拟写入失败或写入期间的新记录
+type usageStore struct {
+	*memAccessKeyStore
+	mu      sync.Mutex
+	writes  [][]model.AccessKeyUsage
+	onWrite func() error
+}
+
+func (f *usageStore) UpdateLastUsed(usages []model.AccessKeyUsage) error {
+	if f.onWrite != nil {
+		if err := f.onWrite(); err != nil {
+			return err
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.writes = append(f.writes, usages)
+	return nil
+}
+
+func TestUsageRecorderFlush(t *testing.T) {
+	akStore := &usageStore{memAccessKeyStore: newMemAccessKeyStore()}
+	r := newUsageRecorder(akStore)
+	base := time.Now()
+
+	// 同一密钥多次使用只写入一次，以时间最新的记录为准，与记录顺序无关
+	r.record(model.AccessKeyUsage{AccessKeyID: "AK", UsedAt: base, Method: "/a", SourceIP: "10.0.0.1"})
+	r.record(model.AccessKeyUsage{AccessKeyID: "AK", UsedAt: base.Add(2 * time.Second), Method: "/c", SourceIP: "10.0.0.3"})
+	r.record(model.AccessKeyUsage{AccessKeyID: "AK", UsedAt: base.Add(time.Second), Method: "/b", SourceIP: "10.0.0.2"})
+	r.record(model.AccessKeyUsage{AccessKeyID: "BK", UsedAt: base, Method: "/a", SourceIP: "10.0.0.1"})
+
+	if err := r.flush(); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	if len(akStore.writes) != 1 || len(akStore.writes[0]) != 2 {
+		t.Fatalf("expected one batch with two keys, got %v", akStore.writes)
+	}
+	for _, usage := range akStore.writes[0] {
+		if usage.AccessKeyID == "AK" && (usage.Method != "/c" || usage.SourceIP != "10.0.0.3" || !usage.UsedAt.Equal(base.Add(2*time.Second))) {
+			t.Errorf("expected the latest usage to win, got %+v", usage)
+		}
+	}
+
+	// 没有待写入记录时不访问数据库
+	if err := r.flush(); err != nil || len(akStore.writes) != 1 {
+		t.Errorf("expected empty flush to skip the store, err=%v writes=%d", err, len(akStore.writes))
+	}
+}
+
+func TestUsageRecorderRequeue(t *testing.T) {
+	akStore := &usageStore{memAccessKeyStore: newMemAccessKeyStore()}
+	r := newUsageRecorder(akStore)
+	base := time.Now()
+	r.record(model.AccessKeyUsage{AccessKeyID: "AK", UsedAt: base, Method: "/old"})
+	r.record(model.AccessKeyUsage{AccessKeyID: "BK", UsedAt: base, Method: "/old"})
+
+	// 写入期间AK又被使用，失败后放回队列不能覆盖这条更新的记录
+	akStore.onWrite = func() error {
+		r.record(model.AccessKeyUsage{AccessKeyID: "AK", UsedAt: base.Add(time.Second), Method: "/new"})
+		return errors.New("database unavailable")
+	}
+	if err := r.flush(); err == nil {
+		t.Fatal("expected flush to fail")
+	}
+	if len(r.pending) != 2 || r.pending["AK"].Method != "/new" || r.pending["BK"].Method != "/old" {
+		t.Fatalf("unexpected pending usage after failed flush: %v", r.pending)
+	}
+
+	akStore.onWrite = nil
+	if err := r.flush(); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	if len(akStore.writes) != 1 || len(akStore.writes[0]) != 2 || len(r.pending) != 0 {
+		t.Errorf("expected requeued usage to be written on retry, got %v", akStore.writes)
+	}
+}
+
+func TestRunUsageFlusherFlushesOnCancel(t *testing.T) {
+	util.Logger = zap.NewNop()
+	akStore := &usageStore{memAccessKeyStore: newMemAccessKeyStore(
+		&model.AccessKey{AccessKeyID: "AK", UserID: 1, Status: "active"},
+	)}
+	svc := NewAccessKeyService(akStore, fakeUserStore{}, nil, config.AccessKeyConfig{LastUsedFlushInterval: time.Hour})
+	svc.RecordUsage("AK", "/iam.v1.IAM/GetUser", "10.0.0.1")
+
+	// 写入数据库前GetLastUsed返回内存中的记录
+	ak, err := svc.GetLastUsed(context.Background(), "AK")
+	if err != nil {
+		t.Fatalf("GetLastUsed failed: %v", err)
+	}
+	if ak.LastUsedAt == nil || ak.LastUsedMethod != "/iam.v1.IAM/GetUser" || ak.LastUsedIP != "10.0.0.1" {
+		t.Errorf("expected pending usage in GetLastUsed, got %+v", ak)
+	}
+	if _, err := svc.GetLastUsed(context.Background(), "MISSING"); err == nil {
+		t.Error("expected an error for unknown access key")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		svc.RunUsageFlusher(ctx)
+		close(done)
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunUsageFlusher did not stop after ctx was cancelled")
+	}
+	if len(akStore.writes) != 1 || akStore.writes[0][0].AccessKeyID != "AK" {
+		t.Errorf("expected a final flush on cancel, got %v", akStore.writes)
+	}
+}
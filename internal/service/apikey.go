@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/vera-byte/vgo-iam/internal/crypto"
+	"github.com/vera-byte/vgo-iam/internal/util"
+)
+
+// ErrInvalidAPIKey API密钥格式错误、不存在或密钥不匹配
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// APIKeyPrincipal API密钥校验通过后的调用者身份
+type APIKeyPrincipal struct {
+	AccessKeyID string
+	UserID      int
+	UserName    string
+}
+
+// apiKeyCacheEntry 已校验通过的API密钥，只缓存密钥的SHA-256摘要，不缓存明文
+type apiKeyCacheEntry struct {
+	digest    [sha256.Size]byte
+	principal APIKeyPrincipal
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     string
+)
+
+// dummySecretHash 返回用于不存在的API密钥的哈希，使其与存在的密钥耗时相同
+func dummySecretHash() string {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = crypto.HashSecret([]byte(util.GenerateSecretAccessKey()), crypto.DefaultArgon2Params)
+	})
+	return dummyHash
+}
+
+// FormatAPIKey 将访问密钥ID和密钥拼接为API密钥令牌: <access_key_id>.<secret_access_key>
+func FormatAPIKey(accessKeyID, secret string) string {
+	return accessKeyID + "." + secret
+}
+
+// VerifyAPIKey 校验API密钥令牌，格式见FormatAPIKey
+// 密钥与数据库中的Argon2id哈希以常量时间比较，处于轮换宽限期时旧密钥同样有效；
+// 访问密钥不存在、不是API密钥或密钥不匹配时返回ErrInvalidAPIKey，未激活或已过期时返回ErrAccessKeyInactive
+func (s *AccessKeyService) VerifyAPIKey(ctx context.Context, apiKey string) (*APIKeyPrincipal, error) {
+	accessKeyID, secret, ok := strings.Cut(apiKey, ".")
+	if !ok || accessKeyID == "" || secret == "" {
+		return nil, ErrInvalidAPIKey
+	}
+
+	digest := sha256.Sum256([]byte(secret))
+	if cached, found := s.apiKeyCache.Get(accessKeyID); found {
+		entry := cached.(*apiKeyCacheEntry)
+		if subtle.ConstantTimeCompare(entry.digest[:], digest[:]) == 1 {
+			principal := entry.principal
+			return &principal, nil
+		}
+	}
+
+	hashes, err := s.accessKeyStore.GetSecretHashes(accessKeyID)
+	if errors.Is(err, dbr.ErrNotFound) {
+		crypto.VerifySecretHash(dummySecretHash(), []byte(secret))
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret hash: %w", err)
+	}
+
+	now := time.Now()
+	matched, err := crypto.VerifySecretHash(hashes.Current, []byte(secret))
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify secret hash: %w", err)
+	}
+	// 缓存时间不超过旧密钥宽限期和访问密钥有效期
+	ttl := secretCacheTTL
+	if !matched && hashes.Previous != "" && hashes.PreviousExpiresAt.After(now) {
+		if matched, err = crypto.VerifySecretHash(hashes.Previous, []byte(secret)); err != nil {
+			return nil, fmt.Errorf("failed to verify previous secret hash: %w", err)
+		}
+		if remaining := hashes.PreviousExpiresAt.Sub(now); remaining < ttl {
+			ttl = remaining
+		}
+	}
+	if !matched {
+		return nil, ErrInvalidAPIKey
+	}
+	if hashes.Status != "active" || (hashes.ExpiresAt != nil && !now.Before(*hashes.ExpiresAt)) {
+		return nil, ErrAccessKeyInactive
+	}
+	if hashes.ExpiresAt != nil {
+		if remaining := hashes.ExpiresAt.Sub(now); remaining < ttl {
+			ttl = remaining
+		}
+	}
+
+	user, err := s.userStore.GetByID(hashes.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get associated user: %w", err)
+	}
+	principal := APIKeyPrincipal{AccessKeyID: accessKeyID, UserID: user.ID, UserName: user.Name}
+	s.apiKeyCache.Set(accessKeyID, &apiKeyCacheEntry{digest: digest, principal: principal}, ttl)
+	return &principal, nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/crypto"
+	"github.com/vera-byte/vgo-iam/internal/model"
+	"github.com/vera-byte/vgo-iam/internal/store"
+)
+
+// fakeHashStore 仅实现API密钥校验所需方法的访问密钥存储
+type fakeHashStore struct {
+	store.AccessKeyStore
+	hashes map[string]*model.AccessKeySecretHashes
+}
+
+func (f *fakeHashStore) GetSecretHashes(accessKeyID string) (*model.AccessKeySecretHashes, error) {
+	hashes, ok := f.hashes[accessKeyID]
+	if !ok {
+		return nil, dbr.ErrNotFound
+	}
+	return hashes, nil
+}
+
+type fakeUserStore struct {
+	store.UserStore
+}
+
+func (fakeUserStore) GetByID(id int) (*model.User, error) {
+	return &model.User{ID: id, Name: "alice"}, nil
+}
+
+func mustHash(t *testing.T, secret string) string {
+	t.Helper()
+	hash, err := crypto.HashSecret([]byte(secret), crypto.DefaultArgon2Params)
+	if err != nil {
+		t.Fatalf("hash failed: %v", err)
+	}
+	return hash
+}
+
+func TestVerifyAPIKey(t *testing.T) {
+	graceUntil := time.Now().Add(time.Hour)
+	akStore := &fakeHashStore{hashes: map[string]*model.AccessKeySecretHashes{
+		"AKID": {UserID: 7, Status: "active", Current: mustHash(t, "new-secret"), Previous: mustHash(t, "old-secret"), PreviousExpiresAt: &graceUntil},
+		"OFF":  {UserID: 7, Status: "inactive", Current: mustHash(t, "secret")},
+	}}
+	svc := NewAccessKeyService(akStore, fakeUserStore{}, nil, config.AccessKeyConfig{})
+
+	for _, apiKey := range []string{"AKID.new-secret", "AKID.old-secret", "AKID.new-secret"} {
+		principal, err := svc.VerifyAPIKey(context.Background(), apiKey)
+		if err != nil {
+			t.Fatalf("VerifyAPIKey(%q) failed: %v", apiKey, err)
+		}
+		if principal.AccessKeyID != "AKID" || principal.UserName != "alice" {
+			t.Errorf("unexpected principal: %+v", principal)
+		}
+	}
+
+	for apiKey, want := range map[string]error{
+		"AKID.wrong":    ErrInvalidAPIKey,
+		"AKID.":         ErrInvalidAPIKey,
+		"no-separator":  ErrInvalidAPIKey,
+		"MISSING.value": ErrInvalidAPIKey,
+		"OFF.secret":    ErrAccessKeyInactive,
+	} {
+		if _, err := svc.VerifyAPIKey(context.Background(), apiKey); !errors.Is(err, want) {
+			t.Errorf("VerifyAPIKey(%q) = %v, want %v", apiKey, err, want)
+		}
+	}
+}
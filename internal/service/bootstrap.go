@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/crypto"
+	"github.com/vera-byte/vgo-iam/internal/model"
+	"github.com/vera-byte/vgo-iam/internal/store"
+	"github.com/vera-byte/vgo-iam/internal/util"
+)
+
+const (
+	// AdministratorAccessPolicy 根管理员附加的策略名称
+	AdministratorAccessPolicy = "AdministratorAccess"
+	// administratorAccessDocument 允许对所有资源执行所有操作
+	administratorAccessDocument = `{"Version":"1","Statement":[{"Effect":"Allow","Action":["*"],"Resource":["*"]}]}`
+)
+
+// BootstrapService 首次启动初始化服务
+type BootstrapService struct {
+	bootstrapStore store.BootstrapStore
+	keyring        *crypto.KeyRotationManager
+	cfg            config.BootstrapConfig
+}
+
+// NewBootstrapService 创建首次启动初始化服务实例
+func NewBootstrapService(bootstrapStore store.BootstrapStore, keyring *crypto.KeyRotationManager, cfg config.BootstrapConfig) *BootstrapService {
+	return &BootstrapService{
+		bootstrapStore: bootstrapStore,
+		keyring:        keyring,
+		cfg:            cfg,
+	}
+}
+
+// EnsureRootAdmin 数据库中没有任何用户时创建根管理员及其访问密钥
+// 返回的访问密钥包含明文SecretAccessKey，只在初始化时返回一次；已初始化时返回nil
+// 根访问密钥不设置有效期，拿到后应尽快创建日常使用的管理员并停用根密钥
+func (s *BootstrapService) EnsureRootAdmin(ctx context.Context) (*model.AccessKey, error) {
+	if !util.ValidateUserName(s.cfg.RootUser) {
+		return nil, fmt.Errorf("invalid root user name: %q", s.cfg.RootUser)
+	}
+
+	user := &model.User{
+		Name:        s.cfg.RootUser,
+		DisplayName: "Root Administrator",
+		Email:       s.cfg.RootEmail,
+	}
+	policy := model.NewPolicy(AdministratorAccessPolicy, "Provides full access to all IAM actions and resources", administratorAccessDocument)
+
+	secretKey := util.GenerateSecretAccessKey()
+	ak := model.NewAccessKey(0, util.GenerateAccessKeyID(), secretKey, 0)
+	// 密文绑定用户ID，由存储层在创建用户后回调加密
+	encryptSecret := func(ak *model.AccessKey) error {
+		encrypted, err := s.keyring.EncryptWithCurrentKey(ctx, []byte(secretKey), SecretAAD(ak.AccessKeyID, ak.UserID, ak.SecretVersion))
+		if err != nil {
+			return fmt.Errorf("failed to encrypt secret access key: %w", err)
+		}
+		ak.EncryptedSecretKey = encrypted
+		return nil
+	}
+
+	created, err := s.bootstrapStore.CreateRootAdmin(user, policy, ak, encryptSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create root administrator: %w", err)
+	}
+	if !created {
+		return nil, nil
+	}
+
+	ak.UserName = user.Name
+	return ak, nil
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/model"
+	"github.com/vera-byte/vgo-iam/internal/store"
+	"github.com/vera-byte/vgo-iam/internal/util"
+)
+
+// ErrLockedOut 认证对象已被锁定或仍在渐进延迟内，稍后再试
+var ErrLockedOut = errors.New("too many failed attempts, try again later")
+
+// 未配置时使用的窗口和锁定时长
+const (
+	defaultLockoutWindow   = 15 * time.Minute
+	defaultLockoutDuration = 15 * time.Minute
+)
+
+// UserSubject 返回密码登录时用户的认证对象，不存在的用户名同样计数
+func UserSubject(userName string) string { return "user:" + userName }
+
+// AccessKeySubject 返回API密钥的认证对象
+func AccessKeySubject(accessKeyID string) string { return "key:" + accessKeyID }
+
+// ipSubject 返回来源IP的认证对象
+func ipSubject(sourceIP string) string {
+	if sourceIP == "" {
+		return ""
+	}
+	return "ip:" + sourceIP
+}
+
+// LockoutService 密码登录和API密钥的暴力破解防护
+// 用户(或API密钥)每次失败后须等待渐进延迟才能再次尝试，用户和来源IP在窗口内的失败次数达到上限后锁定一段时间；
+// 计数保存在数据库中，多副本共享。nil表示不限制
+type LockoutService struct {
+	failureStore store.AuthFailureStore
+	cfg          config.LockoutConfig
+	now          func() time.Time
+}
+
+// NewLockoutService 创建暴力破解防护服务，上限和延迟都为0时返回nil
+func NewLockoutService(failureStore store.AuthFailureStore, cfg config.LockoutConfig) *LockoutService {
+	if cfg.MaxFailuresPerUser <= 0 && cfg.MaxFailuresPerIP <= 0 && cfg.BaseDelay <= 0 {
+		return nil
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = defaultLockoutWindow
+	}
+	if cfg.Duration <= 0 {
+		cfg.Duration = defaultLockoutDuration
+	}
+	return &LockoutService{
+		failureStore: failureStore,
+		cfg:          cfg,
+		now:          time.Now,
+	}
+}
+
+// Check 在校验凭证之前调用，subject或来源IP被锁定、或subject仍在渐进延迟内时返回ErrLockedOut
+// hasFailures表示subject有未清除的失败记录，认证成功后据此决定是否调用RecordSuccess
+func (s *LockoutService) Check(ctx context.Context, subject, sourceIP string) (hasFailures bool, err error) {
+	if s == nil {
+		return false, nil
+	}
+	failures, err := s.failureStore.Get(nonEmpty(subject, ipSubject(sourceIP)))
+	if err != nil {
+		return false, fmt.Errorf("failed to get authentication failures: %w", err)
+	}
+
+	now := s.now()
+	for _, f := range failures {
+		if f.IsLocked(now) {
+			return true, ErrLockedOut
+		}
+		if f.Subject != subject {
+			continue
+		}
+		hasFailures = true
+		if f.WindowStartedAt.After(now.Add(-s.cfg.Window)) && now.Before(f.LastFailureAt.Add(s.delay(f.Failures))) {
+			return true, ErrLockedOut
+		}
+	}
+	return hasFailures, nil
+}
+
+// RecordFailure 记录subject和来源IP的一次认证失败，达到上限时锁定并发出锁定事件
+func (s *LockoutService) RecordFailure(ctx context.Context, subject, sourceIP string) error {
+	if s == nil {
+		return nil
+	}
+	now := s.now()
+	if err := s.recordFailure(subject, sourceIP, s.cfg.MaxFailuresPerUser, now); err != nil {
+		return err
+	}
+	if s.cfg.MaxFailuresPerIP > 0 {
+		return s.recordFailure(ipSubject(sourceIP), sourceIP, s.cfg.MaxFailuresPerIP, now)
+	}
+	return nil
+}
+
+// RecordSuccess 认证成功后清除subject的失败记录，来源IP的计数保留到窗口结束
+func (s *LockoutService) RecordSuccess(ctx context.Context, subject string) error {
+	if s == nil || subject == "" {
+		return nil
+	}
+	if err := s.failureStore.Clear([]string{subject}); err != nil {
+		return fmt.Errorf("failed to clear authentication failures: %w", err)
+	}
+	return nil
+}
+
+// UnlockUser 解除用户及其API密钥的锁定并清除失败记录
+func (s *LockoutService) UnlockUser(ctx context.Context, userName string, accessKeyIDs []string) error {
+	if s == nil {
+		return nil
+	}
+	subjects := []string{UserSubject(userName)}
+	for _, id := range accessKeyIDs {
+		subjects = append(subjects, AccessKeySubject(id))
+	}
+	if err := s.failureStore.Clear(subjects); err != nil {
+		return fmt.Errorf("failed to unlock user: %w", err)
+	}
+	return nil
+}
+
+// CleanupExpired 清理窗口已结束且未锁定的失败记录
+func (s *LockoutService) CleanupExpired(ctx context.Context) error {
+	now := s.now()
+	deleted, err := s.failureStore.DeleteExpired(now.Add(-s.cfg.Window), now)
+	if err != nil {
+		return fmt.Errorf("failed to delete expired authentication failures: %w", err)
+	}
+	if deleted > 0 {
+		util.Logger.Debug("Expired authentication failures deleted", zap.Int64("count", deleted))
+	}
+	return nil
+}
+
+// RunCleanup 按统计窗口周期性清理失败记录，直到ctx取消
+func (s *LockoutService) RunCleanup(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	ticker := time.NewTicker(s.cfg.Window)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.CleanupExpired(ctx); err != nil {
+				util.Logger.Error("Authentication failure cleanup failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// recordFailure 记录一次失败，失败次数达到max时锁定，max为0时只计数用于渐进延迟
+func (s *LockoutService) recordFailure(subject, sourceIP string, max int, now time.Time) error {
+	if subject == "" || (max <= 0 && s.cfg.BaseDelay <= 0) {
+		return nil
+	}
+	failure, err := s.failureStore.RecordFailure(subject, now, s.cfg.Window)
+	if err != nil {
+		return fmt.Errorf("failed to record authentication failure: %w", err)
+	}
+	if max <= 0 || failure.Failures < max {
+		return nil
+	}
+
+	until := now.Add(s.cfg.Duration)
+	locked, err := s.failureStore.Lock(subject, now, until)
+	if err != nil {
+		return fmt.Errorf("failed to lock %s: %w", subject, err)
+	}
+	if locked {
+		s.emit(model.LockoutEvent{Subject: subject, Failures: failure.Failures, SourceIP: sourceIP, LockedUntil: until})
+	}
+	return nil
+}
+
+// emit 以告警日志的形式发出锁定事件，告警规则据此匹配event=lockout
+func (s *LockoutService) emit(event model.LockoutEvent) {
+	util.Logger.Warn("Authentication lockout triggered",
+		zap.String("event", "lockout"),
+		zap.String("subject", event.Subject),
+		zap.Int("failures", event.Failures),
+		zap.String("source_ip", event.SourceIP),
+		zap.Time("locked_until", event.LockedUntil),
+	)
+}
+
+// delay 返回第failures次失败后的等待时间: BaseDelay * 2^(failures-1)，不超过MaxDelay
+func (s *LockoutService) delay(failures int) time.Duration {
+	delay := s.cfg.BaseDelay
+	if delay <= 0 || failures <= 0 {
+		return 0
+	}
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if s.cfg.MaxDelay > 0 && delay >= s.cfg.MaxDelay {
+			return s.cfg.MaxDelay
+		}
+	}
+	if s.cfg.MaxDelay > 0 && delay > s.cfg.MaxDelay {
+		return s.cfg.MaxDelay
+	}
+	return delay
+}
+
+// nonEmpty 去掉空字符串
+func nonEmpty(values ...string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
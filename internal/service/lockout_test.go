@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/model"
+	"github.com/vera-byte/vgo-iam/internal/util"
+)
+
+// fakeAuthFailureStore 内存中的认证失败计数存储
+type fakeAuthFailureStore struct {
+	failures map[string]*model.AuthFailure
+}
+
+func (f *fakeAuthFailureStore) Get(subjects []string) ([]*model.AuthFailure, error) {
+	var result []*model.AuthFailure
+	for _, subject := range subjects {
+		if failure, ok := f.failures[subject]; ok {
+			copied := *failure
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeAuthFailureStore) RecordFailure(subject string, now time.Time, window time.Duration) (*model.AuthFailure, error) {
+	failure, ok := f.failures[subject]
+	if !ok {
+		failure = &model.AuthFailure{Subject: subject, WindowStartedAt: now}
+		f.failures[subject] = failure
+	} else if !failure.WindowStartedAt.After(now.Add(-window)) {
+		failure.Failures = 0
+		failure.WindowStartedAt = now
+	}
+	failure.Failures++
+	failure.LastFailureAt = now
+	copied := *failure
+	return &copied, nil
+}
+
+func (f *fakeAuthFailureStore) Lock(subject string, now, until time.Time) (bool, error) {
+	failure := f.failures[subject]
+	if failure.IsLocked(now) {
+		return false, nil
+	}
+	failure.LockedUntil = &until
+	return true, nil
+}
+
+func (f *fakeAuthFailureStore) Clear(subjects []string) error {
+	for _, subject := range subjects {
+		delete(f.failures, subject)
+	}
+	return nil
+}
+
+func (f *fakeAuthFailureStore) DeleteExpired(before, now time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestLockout(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	util.Logger = zap.New(core)
+	failures := &fakeAuthFailureStore{failures: map[string]*model.AuthFailure{}}
+	svc := NewLockoutService(failures, config.LockoutConfig{
+		MaxFailuresPerUser: 3,
+		MaxFailuresPerIP:   10,
+		Window:             15 * time.Minute,
+		Duration:           15 * time.Minute,
+		BaseDelay:          time.Second,
+		MaxDelay:           30 * time.Second,
+	})
+	now := time.Unix(1700000000, 0)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+	subject := UserSubject("alice")
+
+	if err := svc.RecordFailure(ctx, subject, "10.0.0.1"); err != nil {
+		t.Fatalf("RecordFailure failed: %v", err)
+	}
+	// 第一次失败后须等待BaseDelay
+	if _, err := svc.Check(ctx, subject, "10.0.0.1"); !errors.Is(err, ErrLockedOut) {
+		t.Errorf("expected progressive delay, got %v", err)
+	}
+	now = now.Add(time.Second)
+	if hasFailures, err := svc.Check(ctx, subject, "10.0.0.1"); err != nil || !hasFailures {
+		t.Errorf("Check = %v, %v after delay", hasFailures, err)
+	}
+
+	// 第二次失败后延迟翻倍
+	svc.RecordFailure(ctx, subject, "10.0.0.1")
+	now = now.Add(time.Second)
+	if _, err := svc.Check(ctx, subject, "10.0.0.1"); !errors.Is(err, ErrLockedOut) {
+		t.Errorf("expected doubled delay, got %v", err)
+	}
+
+	// 达到上限后锁定并发出事件，延迟结束后仍然锁定
+	now = now.Add(time.Second)
+	svc.RecordFailure(ctx, subject, "10.0.0.1")
+	events := logs.FilterMessage("Authentication lockout triggered").All()
+	if len(events) != 1 || events[0].ContextMap()["subject"] != subject || events[0].ContextMap()["failures"] != int64(3) || events[0].ContextMap()["source_ip"] != "10.0.0.1" {
+		t.Fatalf("unexpected lockout events %+v", events)
+	}
+	now = now.Add(time.Minute)
+	if _, err := svc.Check(ctx, subject, "10.0.0.1"); !errors.Is(err, ErrLockedOut) {
+		t.Errorf("expected lockout, got %v", err)
+	}
+	// 其他用户从同一IP登录不受影响
+	if _, err := svc.Check(ctx, UserSubject("bob"), "10.0.0.1"); err != nil {
+		t.Errorf("other user must not be locked, got %v", err)
+	}
+
+	if err := svc.UnlockUser(ctx, "alice", nil); err != nil {
+		t.Fatalf("UnlockUser failed: %v", err)
+	}
+	if hasFailures, err := svc.Check(ctx, subject, "10.0.0.1"); err != nil || hasFailures {
+		t.Errorf("Check = %v, %v after unlock", hasFailures, err)
+	}
+}
+
+func TestLockoutDelay(t *testing.T) {
+	svc := NewLockoutService(nil, config.LockoutConfig{BaseDelay: time.Second, MaxDelay: 30 * time.Second})
+	for failures, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 5: 16 * time.Second, 6: 30 * time.Second, 1000: 30 * time.Second} {
+		if got := svc.delay(failures); got != want {
+			t.Errorf("delay(%d) = %v, want %v", failures, got, want)
+		}
+	}
+}
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/patrickmn/go-cache"
+	"go.uber.org/zap"
+
+	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/crypto"
+	"github.com/vera-byte/vgo-iam/internal/model"
+	"github.com/vera-byte/vgo-iam/internal/store"
+	"github.com/vera-byte/vgo-iam/internal/util"
+)
+
+// sessionCacheTTL 已校验的会话在内存中的缓存时间
+const sessionCacheTTL = 30 * time.Second
+
+// defaultSessionTTL 未配置会话有效期时使用的有效期
+const defaultSessionTTL = time.Hour
+
+var (
+	// ErrLoginProfileNotFound 用户没有登录配置
+	ErrLoginProfileNotFound = errors.New("login profile not found")
+	// ErrInvalidCredentials 用户名或密码错误，不区分用户不存在、没有登录配置和密码错误
+	ErrInvalidCredentials = errors.New("invalid user name or password")
+	// ErrPasswordChangeRequired 密码已过期或被要求重置，须在登录时提供新密码
+	ErrPasswordChangeRequired = errors.New("password must be changed before signing in")
+	// ErrPasswordReused 新密码与最近使用过的密码相同
+	ErrPasswordReused = errors.New("password was used recently")
+	// ErrInvalidSession 会话令牌不存在或已过期
+	ErrInvalidSession = errors.New("invalid or expired session")
+	// ErrMFACodeRequired 用户已启用MFA设备，登录时必须提供验证码
+	ErrMFACodeRequired = errors.New("mfa code is required")
+)
+
+// LoginService 控制台登录服务: 登录配置、密码策略和会话令牌
+type LoginService struct {
+	userStore    store.UserStore
+	profileStore store.LoginProfileStore
+	sessionStore store.SessionStore
+	mfaService   *MFAService
+	cfg          config.LoginConfig
+	sessionCache *cache.Cache // 会话令牌摘要 -> *model.LoginSession
+}
+
+// NewLoginService 创建登录服务实例，mfaService为nil时不校验MFA
+func NewLoginService(userStore store.UserStore, profileStore store.LoginProfileStore, sessionStore store.SessionStore, mfaService *MFAService, cfg config.LoginConfig) *LoginService {
+	if cfg.SessionTTL <= 0 {
+		cfg.SessionTTL = defaultSessionTTL
+	}
+	return &LoginService{
+		userStore:    userStore,
+		profileStore: profileStore,
+		sessionStore: sessionStore,
+		mfaService:   mfaService,
+		cfg:          cfg,
+		sessionCache: cache.New(sessionCacheTTL, 2*sessionCacheTTL),
+	}
+}
+
+// PasswordMaxAge 返回配置的密码有效期，0表示永不过期
+func (s *LoginService) PasswordMaxAge() time.Duration {
+	return s.cfg.PasswordPolicy.MaxAge
+}
+
+// GetLoginProfile 获取用户的登录配置
+func (s *LoginService) GetLoginProfile(ctx context.Context, userName string) (*model.LoginProfile, error) {
+	profile, err := s.profileStore.GetByUserName(userName)
+	if errors.Is(err, dbr.ErrNotFound) {
+		return nil, ErrLoginProfileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login profile: %w", err)
+	}
+	return profile, nil
+}
+
+// CreateLoginProfile 为用户创建登录配置，密码须符合密码策略
+func (s *LoginService) CreateLoginProfile(ctx context.Context, userName, password string, resetRequired bool) (*model.LoginProfile, error) {
+	user, err := s.userStore.GetByName(userName)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	hash, err := s.hashNewPassword(user.ID, password)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.profileStore.Create(user.ID, hash, resetRequired, s.cfg.PasswordPolicy.ReuseHistory); err != nil {
+		if errors.Is(err, store.ErrLoginProfileExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create login profile: %w", err)
+	}
+	return s.GetLoginProfile(ctx, userName)
+}
+
+// UpdateLoginProfile 修改用户密码和下次登录是否必须修改密码，password为空时只修改后者
+// 修改密码后用户已有的会话全部失效
+func (s *LoginService) UpdateLoginProfile(ctx context.Context, userName, password string, resetRequired bool) (*model.LoginProfile, error) {
+	profile, err := s.GetLoginProfile(ctx, userName)
+	if err != nil {
+		return nil, err
+	}
+	if password == "" {
+		if err := s.profileStore.UpdateResetRequired(profile.UserID, resetRequired); err != nil {
+			return nil, fmt.Errorf("failed to update login profile: %w", err)
+		}
+		return s.GetLoginProfile(ctx, userName)
+	}
+	if err := s.changePassword(profile.UserID, password, resetRequired); err != nil {
+		return nil, err
+	}
+	return s.GetLoginProfile(ctx, userName)
+}
+
+// DeleteLoginProfile 删除用户的登录配置并使其会话全部失效
+func (s *LoginService) DeleteLoginProfile(ctx context.Context, userName string) error {
+	profile, err := s.GetLoginProfile(ctx, userName)
+	if err != nil {
+		return err
+	}
+	if err := s.profileStore.Delete(profile.UserID); err != nil {
+		if errors.Is(err, dbr.ErrNotFound) {
+			return ErrLoginProfileNotFound
+		}
+		return fmt.Errorf("failed to delete login profile: %w", err)
+	}
+	return s.revokeSessions(profile.UserID)
+}
+
+// Authenticate 校验用户名和密码，成功后创建会话并返回会话令牌
+// 用户已启用MFA设备时必须提供mfaCode，否则返回ErrMFACodeRequired，验证码错误返回ErrInvalidMFACode；
+// 密码已过期或被要求重置时必须同时提供符合密码策略的newPassword，否则返回ErrPasswordChangeRequired；
+// 未要求修改时提供newPassword也会修改密码
+func (s *LoginService) Authenticate(ctx context.Context, userName, password, newPassword, mfaCode, sourceIP string) (*model.LoginSession, error) {
+	profile, err := s.profileStore.GetByUserName(userName)
+	if err != nil && !errors.Is(err, dbr.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get login profile: %w", err)
+	}
+	if profile == nil {
+		// 用户不存在时同样计算一次哈希，响应时间不暴露用户是否存在
+		crypto.VerifySecretHash(dummySecretHash(), []byte(password))
+		return nil, ErrInvalidCredentials
+	}
+	matched, err := crypto.VerifySecretHash(profile.PasswordHash, []byte(password))
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify password: %w", err)
+	}
+	if !matched {
+		return nil, ErrInvalidCredentials
+	}
+
+	mfaPresent, err := s.checkMFA(ctx, profile.UserID, mfaCode)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expires := profile.PasswordExpiresAt(s.cfg.PasswordPolicy.MaxAge)
+	if profile.PasswordResetRequired || (expires != nil && !now.Before(*expires)) {
+		if newPassword == "" {
+			return nil, ErrPasswordChangeRequired
+		}
+	}
+	if newPassword != "" {
+		if err := s.changePassword(profile.UserID, newPassword, false); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.createSession(profile.UserID, profile.UserName, sourceIP, mfaPresent)
+}
+
+// GetSessionToken 使用已认证的长期凭证(访问密钥)换取会话令牌
+// 提供mfaCode时校验MFA，通过后会话带有MFA标记，可满足要求iam:MultiFactorAuthPresent的策略
+func (s *LoginService) GetSessionToken(ctx context.Context, userID int, userName, mfaCode, sourceIP string) (*model.LoginSession, error) {
+	mfaPresent := false
+	if mfaCode != "" {
+		if s.mfaService == nil {
+			return nil, ErrMFANotEnabled
+		}
+		if err := s.mfaService.VerifyCode(ctx, userID, mfaCode); err != nil {
+			return nil, err
+		}
+		mfaPresent = true
+	}
+	return s.createSession(userID, userName, sourceIP, mfaPresent)
+}
+
+// ValidateSession 校验会话令牌，返回会话所属用户
+// 结果短时间缓存在内存中，修改密码或删除登录配置时清除该用户的缓存
+func (s *LoginService) ValidateSession(ctx context.Context, token string) (*model.LoginSession, error) {
+	if token == "" {
+		return nil, ErrInvalidSession
+	}
+	tokenHash := hashSessionToken(token)
+	now := time.Now()
+	if cached, found := s.sessionCache.Get(tokenHash); found {
+		session := cached.(*model.LoginSession)
+		if now.Before(session.ExpiresAt) {
+			return session, nil
+		}
+	}
+
+	session, err := s.sessionStore.Get(tokenHash, now)
+	if errors.Is(err, dbr.ErrNotFound) {
+		return nil, ErrInvalidSession
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	ttl := sessionCacheTTL
+	if remaining := session.ExpiresAt.Sub(now); remaining < ttl {
+		ttl = remaining
+	}
+	s.sessionCache.Set(tokenHash, session, ttl)
+	return session, nil
+}
+
+// CleanupExpiredSessions 清理已过期的会话
+func (s *LoginService) CleanupExpiredSessions(ctx context.Context) error {
+	deleted, err := s.sessionStore.DeleteExpired(time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+	if deleted > 0 {
+		util.Logger.Info("Expired login sessions deleted", zap.Int64("count", deleted))
+	}
+	return nil
+}
+
+// RunSessionCleanup 按会话有效期周期性清理过期会话，直到ctx取消
+func (s *LoginService) RunSessionCleanup(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.SessionTTL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.CleanupExpiredSessions(ctx); err != nil {
+				util.Logger.Error("Login session cleanup failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// checkMFA 用户已启用MFA设备时校验验证码，返回本次登录是否通过了MFA
+func (s *LoginService) checkMFA(ctx context.Context, userID int, mfaCode string) (bool, error) {
+	if s.mfaService == nil {
+		return false, nil
+	}
+	enabled, err := s.mfaService.Enabled(ctx, userID)
+	if err != nil || !enabled {
+		return false, err
+	}
+	if mfaCode == "" {
+		return false, ErrMFACodeRequired
+	}
+	if err := s.mfaService.VerifyCode(ctx, userID, mfaCode); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// createSession 创建会话并返回会话令牌
+func (s *LoginService) createSession(userID int, userName, sourceIP string, mfaPresent bool) (*model.LoginSession, error) {
+	token, tokenHash, err := newSessionToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate session token: %w", err)
+	}
+	now := time.Now()
+	session := &model.LoginSession{
+		Token:      token,
+		UserID:     userID,
+		UserName:   userName,
+		SourceIP:   sourceIP,
+		MFAPresent: mfaPresent,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(s.cfg.SessionTTL),
+	}
+	if err := s.sessionStore.Create(tokenHash, session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	return session, nil
+}
+
+// changePassword 修改密码并使用户已有的会话失效
+func (s *LoginService) changePassword(userID int, password string, resetRequired bool) error {
+	hash, err := s.hashNewPassword(userID, password)
+	if err != nil {
+		return err
+	}
+	if err := s.profileStore.UpdatePassword(userID, hash, resetRequired, s.cfg.PasswordPolicy.ReuseHistory); err != nil {
+		if errors.Is(err, dbr.ErrNotFound) {
+			return ErrLoginProfileNotFound
+		}
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	return s.revokeSessions(userID)
+}
+
+// hashNewPassword 按密码策略校验新密码，确认最近没有使用过后计算Argon2id哈希
+func (s *LoginService) hashNewPassword(userID int, password string) (string, error) {
+	policy := s.cfg.PasswordPolicy
+	if err := util.ValidatePassword(password, policy); err != nil {
+		return "", err
+	}
+	history, err := s.profileStore.ListPasswordHistory(userID, policy.ReuseHistory)
+	if err != nil {
+		return "", fmt.Errorf("failed to get password history: %w", err)
+	}
+	for _, previous := range history {
+		if matched, _ := crypto.VerifySecretHash(previous, []byte(password)); matched {
+			return "", ErrPasswordReused
+		}
+	}
+	hash, err := crypto.HashSecret([]byte(password), crypto.DefaultArgon2Params)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return hash, nil
+}
+
+// revokeSessions 删除用户的所有会话并清除缓存
+func (s *LoginService) revokeSessions(userID int) error {
+	if _, err := s.sessionStore.DeleteByUser(userID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	for key, item := range s.sessionCache.Items() {
+		if session, ok := item.Object.(*model.LoginSession); ok && session.UserID == userID {
+			s.sessionCache.Delete(key)
+		}
+	}
+	return nil
+}
+
+// newSessionToken 生成随机会话令牌，返回令牌及其摘要
+func newSessionToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashSessionToken(token), nil
+}
+
+// hashSessionToken 返回会话令牌的SHA-256摘要，数据库中只保存摘要
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
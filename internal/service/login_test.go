@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/model"
+	"github.com/vera-byte/vgo-iam/internal/store"
+	"github.com/vera-byte/vgo-iam/internal/util"
+)
+
+// fakeProfileStore 内存中的登录配置存储
+type fakeProfileStore struct {
+	store.LoginProfileStore
+	profile *model.LoginProfile
+	history []string
+}
+
+// fakeSessionStore 内存中的登录会话存储
+type fakeSessionStore struct {
+	store.SessionStore
+	sessions map[string]*model.LoginSession
+}
+
+func (f *fakeProfileStore) GetByUserName(userName string) (*model.LoginProfile, error) {
+	if f.profile == nil || f.profile.UserName != userName {
+		return nil, dbr.ErrNotFound
+	}
+	profile := *f.profile
+	return &profile, nil
+}
+
+func (f *fakeProfileStore) UpdatePassword(userID int, passwordHash string, resetRequired bool, historySize int) error {
+	f.profile.PasswordHash = passwordHash
+	f.profile.PasswordChangedAt = time.Now()
+	f.profile.PasswordResetRequired = resetRequired
+	f.history = append([]string{passwordHash}, f.history...)
+	return nil
+}
+
+func (f *fakeProfileStore) ListPasswordHistory(userID, limit int) ([]string, error) {
+	if limit > len(f.history) {
+		limit = len(f.history)
+	}
+	return f.history[:limit], nil
+}
+
+func (f *fakeSessionStore) Create(tokenHash string, session *model.LoginSession) error {
+	f.sessions[tokenHash] = session
+	return nil
+}
+
+func (f *fakeSessionStore) Get(tokenHash string, now time.Time) (*model.LoginSession, error) {
+	session, ok := f.sessions[tokenHash]
+	if !ok || !now.Before(session.ExpiresAt) {
+		return nil, dbr.ErrNotFound
+	}
+	return session, nil
+}
+
+func (f *fakeSessionStore) DeleteByUser(userID int) (int64, error) {
+	for hash, session := range f.sessions {
+		if session.UserID == userID {
+			delete(f.sessions, hash)
+		}
+	}
+	return 0, nil
+}
+
+func TestAuthenticate(t *testing.T) {
+	current := mustHash(t, "Initial-pass1")
+	profiles := &fakeProfileStore{
+		profile: &model.LoginProfile{
+			UserID:            7,
+			UserName:          "alice",
+			PasswordHash:      current,
+			PasswordChangedAt: time.Now().Add(-100 * 24 * time.Hour),
+		},
+		history: []string{current},
+	}
+	sessions := &fakeSessionStore{sessions: make(map[string]*model.LoginSession)}
+	policy := util.DefaultPasswordPolicy
+	policy.ReuseHistory = 3
+	policy.MaxAge = 90 * 24 * time.Hour
+	svc := NewLoginService(nil, profiles, sessions, nil, config.LoginConfig{PasswordPolicy: policy})
+	ctx := context.Background()
+
+	if _, err := svc.Authenticate(ctx, "alice", "wrong", "", "", ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("wrong password: got %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, "bob", "Initial-pass1", "", "", ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("unknown user: got %v", err)
+	}
+	// 密码已过期，必须在登录时修改，且不能沿用旧密码
+	if _, err := svc.Authenticate(ctx, "alice", "Initial-pass1", "", "", ""); !errors.Is(err, ErrPasswordChangeRequired) {
+		t.Errorf("expired password: got %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, "alice", "Initial-pass1", "Initial-pass1", "", ""); !errors.Is(err, ErrPasswordReused) {
+		t.Errorf("reused password: got %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, "alice", "Initial-pass1", "weak", "", ""); !errors.Is(err, util.ErrWeakPassword) {
+		t.Errorf("weak password: got %v", err)
+	}
+
+	session, err := svc.Authenticate(ctx, "alice", "Initial-pass1", "Changed-pass2", "", "10.0.0.1")
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	validated, err := svc.ValidateSession(ctx, session.Token)
+	if err != nil || validated.UserID != 7 {
+		t.Fatalf("ValidateSession = %+v, %v", validated, err)
+	}
+
+	// 修改密码后已有会话失效
+	if _, err := svc.UpdateLoginProfile(ctx, "alice", "Another-pass3", false); err != nil {
+		t.Fatalf("UpdateLoginProfile failed: %v", err)
+	}
+	if _, err := svc.ValidateSession(ctx, session.Token); !errors.Is(err, ErrInvalidSession) {
+		t.Errorf("expected session to be revoked, got %v", err)
+	}
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+
+	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/crypto"
+	"github.com/vera-byte/vgo-iam/internal/model"
+	"github.com/vera-byte/vgo-iam/internal/store"
+	"github.com/vera-byte/vgo-iam/internal/util"
+)
+
+// mfaSerialPrefix MFA设备序列号前缀，序列号格式 iam:mfa:<user_name>/<device_name>
+const mfaSerialPrefix = "iam:mfa:"
+
+var (
+	// ErrMFADeviceNotFound MFA设备不存在或不属于该用户
+	ErrMFADeviceNotFound = errors.New("mfa device not found")
+	// ErrMFADeviceActive MFA设备已启用
+	ErrMFADeviceActive = errors.New("mfa device is already enabled")
+	// ErrInvalidMFADeviceName 设备名不合法
+	ErrInvalidMFADeviceName = errors.New("mfa device name must be 3-32 letters, digits, '_' or '-'")
+	// ErrInvalidMFACode 验证码错误、已使用过或不是两个连续的验证码
+	ErrInvalidMFACode = errors.New("invalid mfa code")
+	// ErrMFANotEnabled 用户没有已启用的MFA设备
+	ErrMFANotEnabled = errors.New("user has no enabled mfa device")
+)
+
+// MFAService 虚拟MFA设备服务，TOTP密钥使用主密钥加密保存
+type MFAService struct {
+	userStore   store.UserStore
+	deviceStore store.MFADeviceStore
+	keyring     *crypto.KeyRotationManager
+	cfg         config.MFAConfig
+	now         func() time.Time
+}
+
+// NewMFAService 创建虚拟MFA设备服务实例
+func NewMFAService(userStore store.UserStore, deviceStore store.MFADeviceStore, keyring *crypto.KeyRotationManager, cfg config.MFAConfig) *MFAService {
+	if cfg.Issuer == "" {
+		cfg.Issuer = "vgo-iam"
+	}
+	if cfg.SkewSteps < 0 {
+		cfg.SkewSteps = 0
+	}
+	return &MFAService{
+		userStore:   userStore,
+		deviceStore: deviceStore,
+		keyring:     keyring,
+		cfg:         cfg,
+		now:         time.Now,
+	}
+}
+
+// MFASerialNumber 返回用户设备的序列号
+func MFASerialNumber(userName, deviceName string) string {
+	return mfaSerialPrefix + userName + "/" + deviceName
+}
+
+// MFASecretAAD 返回TOTP密钥密文绑定的附加数据，密文被复制到其他设备或用户时无法解密
+func MFASecretAAD(serialNumber string, userID int) []byte {
+	return []byte(fmt.Sprintf("vgo-iam:mfa-secret\x00%s\x00%d", serialNumber, userID))
+}
+
+// CreateVirtualMFADevice 为用户创建待启用的虚拟MFA设备
+// 返回的设备中Secret和OTPAuthURI只在创建时返回一次，需调用EnableMFADevice启用后才生效
+func (s *MFAService) CreateVirtualMFADevice(ctx context.Context, userName, deviceName string) (*model.MFADevice, error) {
+	if !util.ValidateUserName(deviceName) {
+		return nil, ErrInvalidMFADeviceName
+	}
+	user, err := s.userStore.GetByName(userName)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	secret, err := crypto.GenerateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate mfa secret: %w", err)
+	}
+	device := &model.MFADevice{
+		UserID:       user.ID,
+		UserName:     user.Name,
+		SerialNumber: MFASerialNumber(user.Name, deviceName),
+		Status:       model.MFADeviceStatusPending,
+		CreatedAt:    s.now(),
+	}
+	device.EncryptedSecret, err = s.keyring.EncryptWithCurrentKey(ctx, secret, MFASecretAAD(device.SerialNumber, user.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt mfa secret: %w", err)
+	}
+	if err := s.deviceStore.Create(device); err != nil {
+		if errors.Is(err, store.ErrMFADeviceExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create mfa device: %w", err)
+	}
+
+	device.Secret = crypto.EncodeTOTPSecret(secret)
+	device.OTPAuthURI = crypto.TOTPURI(s.cfg.Issuer, user.Name, secret)
+	return device, nil
+}
+
+// EnableMFADevice 使用两个连续的验证码启用设备，确认用户的验证器应用已正确保存密钥
+func (s *MFAService) EnableMFADevice(ctx context.Context, userName, serialNumber, code1, code2 string) (*model.MFADevice, error) {
+	device, err := s.getDevice(userName, serialNumber)
+	if err != nil {
+		return nil, err
+	}
+	if device.Status == model.MFADeviceStatusActive {
+		return nil, ErrMFADeviceActive
+	}
+	secret, err := s.decryptSecret(ctx, device)
+	if err != nil {
+		return nil, err
+	}
+
+	// code2必须是code1的下一个时间步，且在允许的时钟偏差范围内
+	current := crypto.TOTPStep(s.now())
+	skew := int64(s.cfg.SkewSteps)
+	step, ok := crypto.MatchTOTPStep(secret, code1, current-skew-1, current+skew-1)
+	if !ok || subtle.ConstantTimeCompare([]byte(crypto.TOTPCode(secret, step+1)), []byte(code2)) != 1 {
+		return nil, ErrInvalidMFACode
+	}
+	if err := s.deviceStore.Activate(device.ID, step+1); err != nil {
+		if errors.Is(err, dbr.ErrNotFound) {
+			return nil, ErrMFADeviceActive
+		}
+		return nil, fmt.Errorf("failed to enable mfa device: %w", err)
+	}
+	return s.getDevice(userName, serialNumber)
+}
+
+// DeactivateMFADevice 停用并删除设备及其密钥，重新使用需要再次创建和启用
+func (s *MFAService) DeactivateMFADevice(ctx context.Context, userName, serialNumber string) error {
+	device, err := s.getDevice(userName, serialNumber)
+	if err != nil {
+		return err
+	}
+	if err := s.deviceStore.Delete(device.ID); err != nil {
+		if errors.Is(err, dbr.ErrNotFound) {
+			return ErrMFADeviceNotFound
+		}
+		return fmt.Errorf("failed to delete mfa device: %w", err)
+	}
+	return nil
+}
+
+// ListMFADevices 列出用户的MFA设备，包括未启用的设备
+func (s *MFAService) ListMFADevices(ctx context.Context, userName string) ([]*model.MFADevice, error) {
+	user, err := s.userStore.GetByName(userName)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	devices, err := s.deviceStore.ListByUser(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mfa devices: %w", err)
+	}
+	return devices, nil
+}
+
+// Enabled 用户是否有已启用的MFA设备
+func (s *MFAService) Enabled(ctx context.Context, userID int) (bool, error) {
+	devices, err := s.activeDevices(userID)
+	if err != nil {
+		return false, err
+	}
+	return len(devices) > 0, nil
+}
+
+// VerifyCode 校验用户任一已启用设备的验证码，同一设备的验证码只能使用一次
+// 用户没有已启用的设备时返回ErrMFANotEnabled
+func (s *MFAService) VerifyCode(ctx context.Context, userID int, code string) error {
+	devices, err := s.activeDevices(userID)
+	if err != nil {
+		return err
+	}
+	if len(devices) == 0 {
+		return ErrMFANotEnabled
+	}
+
+	current := crypto.TOTPStep(s.now())
+	skew := int64(s.cfg.SkewSteps)
+	for _, device := range devices {
+		secret, err := s.decryptSecret(ctx, device)
+		if err != nil {
+			return err
+		}
+		step, ok := crypto.MatchTOTPStep(secret, code, current-skew, current+skew)
+		if !ok {
+			continue
+		}
+		consumed, err := s.deviceStore.ConsumeStep(device.ID, step)
+		if err != nil {
+			return fmt.Errorf("failed to record mfa code usage: %w", err)
+		}
+		if consumed {
+			return nil
+		}
+	}
+	return ErrInvalidMFACode
+}
+
+// ReEncryptSecrets 将全部MFA设备的密钥密文迁移到当前主密钥，已使用当前主密钥的设备被跳过
+func (s *MFAService) ReEncryptSecrets(ctx context.Context, batchSize int, report func(ReEncryptProgress)) (ReEncryptProgress, error) {
+	if batchSize <= 0 {
+		batchSize = defaultReEncryptBatchSize
+	}
+	var progress ReEncryptProgress
+	for {
+		if err := ctx.Err(); err != nil {
+			return progress, err
+		}
+
+		devices, err := s.deviceStore.ListAfter(progress.LastID, batchSize)
+		if err != nil {
+			return progress, fmt.Errorf("failed to list mfa devices: %w", err)
+		}
+		if len(devices) == 0 {
+			return progress, nil
+		}
+		progress.Total += len(devices)
+
+		for _, device := range devices {
+			reencrypted, changed, err := s.keyring.ReEncrypt(ctx, device.EncryptedSecret, MFASecretAAD(device.SerialNumber, device.UserID))
+			if err != nil {
+				return progress, fmt.Errorf("failed to re-encrypt secret of mfa device %s: %w", device.SerialNumber, err)
+			}
+			progress.Processed++
+			progress.LastID = device.ID
+			if !changed {
+				progress.Skipped++
+				continue
+			}
+			replaced, err := s.deviceStore.ReplaceEncryptedSecret(device.ID, device.EncryptedSecret, reencrypted)
+			if err != nil {
+				return progress, fmt.Errorf("failed to save re-encrypted secret of mfa device %s: %w", device.SerialNumber, err)
+			}
+			if !replaced {
+				progress.Conflicts++
+				continue
+			}
+			progress.Migrated++
+		}
+
+		if report != nil {
+			report(progress)
+		}
+	}
+}
+
+// getDevice 获取属于该用户的设备，其他用户的设备同样返回ErrMFADeviceNotFound
+func (s *MFAService) getDevice(userName, serialNumber string) (*model.MFADevice, error) {
+	device, err := s.deviceStore.GetBySerialNumber(serialNumber)
+	if errors.Is(err, dbr.ErrNotFound) {
+		return nil, ErrMFADeviceNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mfa device: %w", err)
+	}
+	if device.UserName != userName {
+		return nil, ErrMFADeviceNotFound
+	}
+	return device, nil
+}
+
+// activeDevices 获取用户已启用的设备
+func (s *MFAService) activeDevices(userID int) ([]*model.MFADevice, error) {
+	devices, err := s.deviceStore.ListByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mfa devices: %w", err)
+	}
+	active := devices[:0]
+	for _, device := range devices {
+		if device.Status == model.MFADeviceStatusActive {
+			active = append(active, device)
+		}
+	}
+	return active, nil
+}
+
+// decryptSecret 解密设备的TOTP密钥
+func (s *MFAService) decryptSecret(ctx context.Context, device *model.MFADevice) ([]byte, error) {
+	secret, err := s.keyring.DecryptWithAnyKey(ctx, device.EncryptedSecret, MFASecretAAD(device.SerialNumber, device.UserID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt mfa secret: %w", err)
+	}
+	return secret, nil
+}
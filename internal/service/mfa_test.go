@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/crypto"
+	"github.com/vera-byte/vgo-iam/internal/model"
+	"github.com/vera-byte/vgo-iam/internal/store"
+)
+
+func (fakeUserStore) GetByName(name string) (*model.User, error) {
+	if name != "alice" {
+		return nil, dbr.ErrNotFound
+	}
+	return &model.User{ID: 7, Name: name}, nil
+}
+
+// fakeMFADeviceStore 内存中的MFA设备存储
+type fakeMFADeviceStore struct {
+	store.MFADeviceStore
+	devices []*model.MFADevice
+}
+
+func (f *fakeMFADeviceStore) Create(device *model.MFADevice) error {
+	for _, d := range f.devices {
+		if d.SerialNumber == device.SerialNumber {
+			return store.ErrMFADeviceExists
+		}
+	}
+	stored := *device
+	stored.ID = len(f.devices) + 1
+	f.devices = append(f.devices, &stored)
+	return nil
+}
+
+func (f *fakeMFADeviceStore) GetBySerialNumber(serialNumber string) (*model.MFADevice, error) {
+	for _, d := range f.devices {
+		if d.SerialNumber == serialNumber {
+			device := *d
+			return &device, nil
+		}
+	}
+	return nil, dbr.ErrNotFound
+}
+
+func (f *fakeMFADeviceStore) ListByUser(userID int) ([]*model.MFADevice, error) {
+	var devices []*model.MFADevice
+	for _, d := range f.devices {
+		if d.UserID == userID {
+			device := *d
+			devices = append(devices, &device)
+		}
+	}
+	return devices, nil
+}
+
+func (f *fakeMFADeviceStore) Activate(id int, step int64) error {
+	d := f.devices[id-1]
+	d.Status = model.MFADeviceStatusActive
+	d.LastUsedStep = step
+	return nil
+}
+
+func (f *fakeMFADeviceStore) ConsumeStep(id int, step int64) (bool, error) {
+	d := f.devices[id-1]
+	if d.LastUsedStep >= step {
+		return false, nil
+	}
+	d.LastUsedStep = step
+	return true, nil
+}
+
+func TestMFADeviceLifecycle(t *testing.T) {
+	devices := &fakeMFADeviceStore{}
+	svc := NewMFAService(fakeUserStore{}, devices, newTestKeyring(t), config.MFAConfig{SkewSteps: 1})
+	now := time.Unix(1700000000, 0)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	device, err := svc.CreateVirtualMFADevice(ctx, "alice", "phone")
+	if err != nil {
+		t.Fatalf("CreateVirtualMFADevice failed: %v", err)
+	}
+	if device.SerialNumber != "iam:mfa:alice/phone" || device.Secret == "" || device.OTPAuthURI == "" {
+		t.Fatalf("unexpected device %+v", device)
+	}
+	if string(devices.devices[0].EncryptedSecret) == device.Secret {
+		t.Fatal("mfa secret stored in plaintext")
+	}
+	if _, err := svc.CreateVirtualMFADevice(ctx, "alice", "phone"); !errors.Is(err, store.ErrMFADeviceExists) {
+		t.Errorf("expected ErrMFADeviceExists, got %v", err)
+	}
+	if err := svc.VerifyCode(ctx, 7, "000000"); !errors.Is(err, ErrMFANotEnabled) {
+		t.Errorf("pending device must not be usable, got %v", err)
+	}
+
+	secret, err := crypto.NewKeyRotationManager(newLocalKeyring(t, nil)).DecryptWithAnyKey(ctx,
+		devices.devices[0].EncryptedSecret, MFASecretAAD(device.SerialNumber, 7))
+	if err != nil {
+		t.Fatalf("decrypt failed: %v", err)
+	}
+	step := crypto.TOTPStep(now)
+
+	// 两个验证码必须连续
+	if _, err := svc.EnableMFADevice(ctx, "alice", device.SerialNumber, crypto.TOTPCode(secret, step), crypto.TOTPCode(secret, step-1)); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("expected ErrInvalidMFACode for non-consecutive codes, got %v", err)
+	}
+	if _, err := svc.EnableMFADevice(ctx, "bob", device.SerialNumber, "", ""); !errors.Is(err, ErrMFADeviceNotFound) {
+		t.Errorf("expected ErrMFADeviceNotFound for another user, got %v", err)
+	}
+	enabled, err := svc.EnableMFADevice(ctx, "alice", device.SerialNumber, crypto.TOTPCode(secret, step-1), crypto.TOTPCode(secret, step))
+	if err != nil || enabled.Status != model.MFADeviceStatusActive {
+		t.Fatalf("EnableMFADevice = %+v, %v", enabled, err)
+	}
+
+	// 启用时使用过的验证码不能再次使用
+	if err := svc.VerifyCode(ctx, 7, crypto.TOTPCode(secret, step)); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("expected replayed code to be rejected, got %v", err)
+	}
+	now = now.Add(crypto.TOTPPeriod)
+	code := crypto.TOTPCode(secret, step+1)
+	if err := svc.VerifyCode(ctx, 7, code); err != nil {
+		t.Errorf("VerifyCode failed: %v", err)
+	}
+	if err := svc.VerifyCode(ctx, 7, code); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("expected second use of the same code to be rejected, got %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/vera-byte/vgo-iam/internal/model"
 	"github.com/vera-byte/vgo-iam/internal/store"
 	"github.com/vera-byte/vgo-iam/internal/util"
+	"github.com/vera-byte/vgo-iam/pkg/authz"
 )
 
 // PolicyService 策略服务
@@ -25,6 +26,9 @@ func (s *PolicyService) CreatePolicy(ctx context.Context, name, description, pol
 	if !util.ValidatePolicyDocument(policyDocument) {
 		return nil, errors.New("invalid policy document")
 	}
+	if _, err := authz.ParseDocument(policyDocument); err != nil {
+		return nil, err
+	}
 
 	// 检查策略是否已存在
 	if _, err := s.policyStore.GetByName(name); err == nil {
@@ -48,6 +52,10 @@ func (s *PolicyService) UpdatePolicy(ctx context.Context, name, description, pol
 		return nil, errors.New("policy not found")
 	}
 
+	if _, err := authz.ParseDocument(policyDocument); err != nil {
+		return nil, err
+	}
+
 	// 更新策略
 	policy.Description = description
 	policy.PolicyDocument = policyDocument
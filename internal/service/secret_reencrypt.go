@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+)
+
+// defaultReEncryptBatchSize 重新加密任务默认每批处理的访问密钥数量
+const defaultReEncryptBatchSize = 100
+
+// ReEncryptProgress 重新加密任务进度
+type ReEncryptProgress struct {
+	Total     int // 本次需要检查的访问密钥总数
+	Processed int // 已检查的数量
+	Migrated  int // 已迁移到当前主密钥的数量
+	Skipped   int // 已使用当前主密钥、无需迁移的数量
+	Conflicts int // 处理期间被轮换或删除、未迁移的数量，重新执行任务即可处理
+	LastID    int // 已处理的最大主键，作为afterID传入可从此处继续
+}
+
+// ReEncryptSecrets 将访问密钥的当前和旧密钥密文迁移到当前主密钥，并绑定到所属记录(见SecretAAD)
+// 按主键顺序从afterID之后分批处理，每批完成后调用report报告进度；
+// 已使用当前主密钥的记录会被跳过，因此任务中断后从0或上次的LastID重新执行都是安全的
+func (s *AccessKeyService) ReEncryptSecrets(ctx context.Context, afterID, batchSize int, report func(ReEncryptProgress)) (ReEncryptProgress, error) {
+	if batchSize <= 0 {
+		batchSize = defaultReEncryptBatchSize
+	}
+	progress := ReEncryptProgress{LastID: afterID}
+
+	total, err := s.accessKeyStore.CountEncryptedSecrets(afterID)
+	if err != nil {
+		return progress, fmt.Errorf("failed to count access keys: %w", err)
+	}
+	progress.Total = total
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return progress, err
+		}
+
+		records, err := s.accessKeyStore.ListEncryptedSecrets(progress.LastID, batchSize)
+		if err != nil {
+			return progress, fmt.Errorf("failed to list access key secrets: %w", err)
+		}
+		if len(records) == 0 {
+			return progress, nil
+		}
+
+		for _, record := range records {
+			current, currentChanged, err := s.keyring.ReEncrypt(ctx, record.Current, SecretAAD(record.AccessKeyID, record.UserID, record.Version))
+			if err != nil {
+				return progress, fmt.Errorf("failed to re-encrypt secret of access key %s: %w", record.AccessKeyID, err)
+			}
+			var previous []byte
+			previousChanged := false
+			if record.Previous != nil {
+				if previous, previousChanged, err = s.keyring.ReEncrypt(ctx, record.Previous, SecretAAD(record.AccessKeyID, record.UserID, record.Version-1)); err != nil {
+					return progress, fmt.Errorf("failed to re-encrypt previous secret of access key %s: %w", record.AccessKeyID, err)
+				}
+			}
+
+			progress.Processed++
+			progress.LastID = record.ID
+			if !currentChanged && !previousChanged {
+				progress.Skipped++
+				continue
+			}
+
+			replaced, err := s.accessKeyStore.ReplaceEncryptedSecrets(record, current, previous)
+			if err != nil {
+				return progress, fmt.Errorf("failed to save re-encrypted secret of access key %s: %w", record.AccessKeyID, err)
+			}
+			if !replaced {
+				progress.Conflicts++
+				continue
+			}
+			progress.Migrated++
+		}
+
+		if report != nil {
+			report(progress)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/crypto"
+	"github.com/vera-byte/vgo-iam/internal/model"
+)
+
+// fakeSecretStore 按主键保存密文记录的访问密钥存储
+type fakeSecretStore struct {
+	fakeAccessKeyStore
+	records []*model.EncryptedSecretRecord
+}
+
+func (f *fakeSecretStore) CountEncryptedSecrets(afterID int) (int, error) {
+	count := 0
+	for _, r := range f.records {
+		if r.ID > afterID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeSecretStore) ListEncryptedSecrets(afterID, limit int) ([]*model.EncryptedSecretRecord, error) {
+	var out []*model.EncryptedSecretRecord
+	for _, r := range f.records {
+		if r.ID > afterID && len(out) < limit {
+			copied := *r
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeSecretStore) ReplaceEncryptedSecrets(record *model.EncryptedSecretRecord, current, previous []byte) (bool, error) {
+	for _, r := range f.records {
+		if r.ID == record.ID {
+			r.Current, r.Previous = current, previous
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestReEncryptSecrets(t *testing.T) {
+	oldKey := []byte("fedcba9876543210fedcba9876543210")
+	oldLocal := crypto.NewLocalKeyring("old")
+	if err := oldLocal.AddKey("old", oldKey); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	envelope, err := crypto.NewKeyRotationManager(oldLocal).EncryptWithCurrentKey(ctx, []byte("secret-2"), SecretAAD("AK2", 20, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy, err := crypto.EncryptKey([]byte("secret-1"), oldKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous, err := crypto.EncryptKey([]byte("secret-0"), oldKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyring := crypto.NewKeyRotationManager(newLocalKeyring(t, map[string][]byte{"old": oldKey}))
+	current, err := keyring.EncryptWithCurrentKey(ctx, []byte("secret-3"), SecretAAD("AK3", 30, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	akStore := &fakeSecretStore{records: []*model.EncryptedSecretRecord{
+		{ID: 1, AccessKeyID: "AK1", UserID: 10, Version: 2, Current: legacy, Previous: previous},
+		{ID: 2, AccessKeyID: "AK2", UserID: 20, Version: 1, Current: envelope},
+		{ID: 3, AccessKeyID: "AK3", UserID: 30, Version: 1, Current: current},
+	}}
+	svc := NewAccessKeyService(akStore, nil, keyring, config.AccessKeyConfig{})
+
+	var reports int
+	progress, err := svc.ReEncryptSecrets(ctx, 0, 2, func(ReEncryptProgress) { reports++ })
+	if err != nil {
+		t.Fatalf("ReEncryptSecrets failed: %v", err)
+	}
+	want := ReEncryptProgress{Total: 3, Processed: 3, Migrated: 2, Skipped: 1, LastID: 3}
+	if progress != want || reports != 2 {
+		t.Errorf("progress = %+v after %d reports, want %+v after 2", progress, reports, want)
+	}
+
+	// 迁移后的密文只需当前主密钥即可解密，且绑定到所属记录
+	onlyCurrent := newTestKeyring(t)
+	onlyCurrent.SetRequireBound(true)
+	plain := map[int]string{1: "secret-1", 2: "secret-2", 3: "secret-3"}
+	for _, r := range akStore.records {
+		if id, _ := crypto.KeyIDOf(r.Current); id != "test" {
+			t.Errorf("record %d key id = %q, want test", r.ID, id)
+		}
+		got, err := onlyCurrent.DecryptWithAnyKey(ctx, r.Current, SecretAAD(r.AccessKeyID, r.UserID, r.Version))
+		if err != nil || string(got) != plain[r.ID] {
+			t.Errorf("record %d decrypted to %q (%v), want %q", r.ID, got, err, plain[r.ID])
+		}
+	}
+	if got, err := onlyCurrent.DecryptWithAnyKey(ctx, akStore.records[0].Previous, SecretAAD("AK1", 10, 1)); err != nil || string(got) != "secret-0" {
+		t.Errorf("previous secret decrypted to %q (%v)", got, err)
+	}
+	if _, err := onlyCurrent.DecryptWithAnyKey(ctx, akStore.records[0].Current, SecretAAD("AK2", 20, 1)); err == nil {
+		t.Error("expected a secret moved to another access key to fail decryption")
+	}
+	if _, err := onlyCurrent.DecryptWithAnyKey(ctx, legacy, nil); !errors.Is(err, crypto.ErrUnboundCiphertext) {
+		t.Errorf("expected legacy ciphertext to be rejected, got %v", err)
+	}
+
+	// 再次执行时全部跳过
+	progress, err = svc.ReEncryptSecrets(ctx, 0, 2, nil)
+	if err != nil || progress.Migrated != 0 || progress.Skipped != 3 {
+		t.Errorf("second run = %+v (%v), want all skipped", progress, err)
+	}
+}
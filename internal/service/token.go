@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/google/uuid"
+
+	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/crypto"
+	"github.com/vera-byte/vgo-iam/internal/model"
+	"github.com/vera-byte/vgo-iam/internal/store"
+)
+
+var (
+	// ErrInvalidClient 客户端凭证(访问密钥ID和密钥)不存在或不匹配
+	ErrInvalidClient = errors.New("invalid client credentials")
+	// ErrInvalidScope 申请的scope格式错误或不在允许范围内
+	ErrInvalidScope = errors.New("invalid scope")
+	// ErrInvalidToken 访问令牌格式错误、签名不匹配、已过期或签发它的访问密钥已失效
+	ErrInvalidToken = errors.New("invalid or expired token")
+)
+
+// 未配置时使用的令牌参数
+const (
+	defaultTokenIssuer          = "vgo-iam"
+	defaultTokenTTL             = 15 * time.Minute
+	defaultTokenKeyRotationTime = 24 * time.Hour
+	// tokenClockSkew 校验nbf时允许的时钟偏差
+	tokenClockSkew = 30 * time.Second
+)
+
+// TokenClaims 访问令牌的JWT声明
+type TokenClaims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"` // 用户名
+	Audience  string   `json:"aud,omitempty"`
+	Account   string   `json:"account"`
+	Groups    []string `json:"groups"`          // 用户所属的组，当前没有用户组，始终为空
+	Scope     string   `json:"scope,omitempty"` // 空格分隔的scope
+	ClientID  string   `json:"client_id"`       // 申请令牌的访问密钥ID
+	IssuedAt  int64    `json:"iat"`
+	NotBefore int64    `json:"nbf"`
+	ExpiresAt int64    `json:"exp"`
+	ID        string   `json:"jti"`
+}
+
+// IssuedToken 签发的访问令牌
+type IssuedToken struct {
+	AccessToken string
+	ExpiresAt   time.Time
+	Claims      *TokenClaims
+}
+
+// TokenPrincipal 访问令牌校验通过后的调用者身份
+type TokenPrincipal struct {
+	AccessKeyID string
+	UserID      int
+	UserName    string
+	Claims      *TokenClaims
+}
+
+// TokenService OAuth2 client_credentials访问令牌服务
+// 访问密钥ID和密钥作为客户端凭证，换取Ed25519签名的短期JWT；签名密钥保存在数据库中，私钥使用主密钥加密，
+// 各副本共享并按key_rotation_interval轮换，见RotateSigningKeys
+type TokenService struct {
+	accessKeyService *AccessKeyService
+	keyStore         store.SigningKeyStore
+	keyring          *crypto.KeyRotationManager
+	cfg              config.TokenConfig
+	now              func() time.Time
+
+	mu        sync.RWMutex
+	keys      map[string]*signingKey // 按kid索引的已加载密钥
+	activeKid string
+	loadedAt  time.Time
+	reloadMu  sync.Mutex
+}
+
+// NewTokenService 创建访问令牌服务，调用RotateSigningKeys后才能签发令牌
+func NewTokenService(accessKeyService *AccessKeyService, keyStore store.SigningKeyStore, keyring *crypto.KeyRotationManager, cfg config.TokenConfig) *TokenService {
+	if cfg.Issuer == "" {
+		cfg.Issuer = defaultTokenIssuer
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultTokenTTL
+	}
+	if cfg.KeyRotationInterval <= 0 {
+		cfg.KeyRotationInterval = defaultTokenKeyRotationTime
+	}
+	return &TokenService{
+		accessKeyService: accessKeyService,
+		keyStore:         keyStore,
+		keyring:          keyring,
+		cfg:              cfg,
+		now:              time.Now,
+		keys:             make(map[string]*signingKey),
+	}
+}
+
+// IssueToken 校验客户端凭证并签发访问令牌
+// 凭证不存在或不匹配时返回ErrInvalidClient，访问密钥未激活或已过期时返回ErrAccessKeyInactive
+func (s *TokenService) IssueToken(ctx context.Context, clientID, clientSecret, scope string) (*IssuedToken, error) {
+	scope, err := s.normalizeScope(scope)
+	if err != nil {
+		return nil, err
+	}
+	principal, err := s.authenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	expiresAt := now.Add(s.cfg.TTL)
+	claims := &TokenClaims{
+		Issuer:    s.cfg.Issuer,
+		Subject:   principal.UserName,
+		Audience:  s.cfg.Audience,
+		Account:   model.DefaultAccount,
+		Groups:    []string{},
+		Scope:     scope,
+		ClientID:  principal.AccessKeyID,
+		IssuedAt:  now.Unix(),
+		NotBefore: now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+		ID:        uuid.NewString(),
+	}
+
+	s.mu.RLock()
+	kid, key := s.activeKid, s.keys[s.activeKid]
+	s.mu.RUnlock()
+	if key == nil || key.private == nil {
+		return nil, errors.New("no active token signing key")
+	}
+	token, err := crypto.SignJWT(claims, kid, key.private)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign token: %w", err)
+	}
+	return &IssuedToken{AccessToken: token, ExpiresAt: time.Unix(claims.ExpiresAt, 0), Claims: claims}, nil
+}
+
+// ValidateToken 校验访问令牌的签名和声明，并确认签发它的访问密钥仍然有效
+// 任何校验失败都返回ErrInvalidToken
+func (s *TokenService) ValidateToken(ctx context.Context, token string) (*TokenPrincipal, error) {
+	var claims TokenClaims
+	if err := crypto.VerifyJWT(token, s.publicKey, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	now := s.now()
+	if claims.Issuer != s.cfg.Issuer || claims.Audience != s.cfg.Audience {
+		return nil, ErrInvalidToken
+	}
+	if now.Unix() >= claims.ExpiresAt || now.Add(tokenClockSkew).Unix() < claims.NotBefore {
+		return nil, ErrInvalidToken
+	}
+
+	// 访问密钥被停用、删除或过期后，它签发的令牌立即失效
+	ak, err := s.accessKeyService.GetAccessKey(ctx, claims.ClientID)
+	if errors.Is(err, dbr.ErrNotFound) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ak.Status != "active" || ak.IsExpired(now) || ak.UserName != claims.Subject {
+		return nil, ErrInvalidToken
+	}
+
+	return &TokenPrincipal{
+		AccessKeyID: ak.AccessKeyID,
+		UserID:      ak.UserID,
+		UserName:    ak.UserName,
+		Claims:      &claims,
+	}, nil
+}
+
+// authenticateClient 校验客户端凭证，签名凭证与解密后的密钥以常量时间比较，API密钥按哈希校验
+func (s *TokenService) authenticateClient(ctx context.Context, clientID, clientSecret string) (*APIKeyPrincipal, error) {
+	if clientID == "" || clientSecret == "" {
+		return nil, ErrInvalidClient
+	}
+	ak, err := s.accessKeyService.GetAccessKey(ctx, clientID)
+	if errors.Is(err, dbr.ErrNotFound) {
+		// 与API密钥客户端一样计算一次哈希，响应时间不暴露client_id是否存在
+		crypto.VerifySecretHash(dummySecretHash(), []byte(clientSecret))
+		return nil, ErrInvalidClient
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if ak.CredentialType == model.CredentialTypeAPIKey {
+		principal, err := s.accessKeyService.VerifyAPIKey(ctx, FormatAPIKey(clientID, clientSecret))
+		if errors.Is(err, ErrInvalidAPIKey) {
+			return nil, ErrInvalidClient
+		}
+		return principal, err
+	}
+
+	secrets, err := s.accessKeyService.ResolveSecrets(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	matched := false
+	for _, secret := range secrets {
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(clientSecret)) == 1 {
+			matched = true
+		}
+	}
+	if !matched {
+		return nil, ErrInvalidClient
+	}
+	if ak.Status != "active" || ak.IsExpired(s.now()) {
+		return nil, ErrAccessKeyInactive
+	}
+	return &APIKeyPrincipal{AccessKeyID: ak.AccessKeyID, UserID: ak.UserID, UserName: ak.UserName}, nil
+}
+
+// normalizeScope 校验空格分隔的scope并去重，配置了allowed_scopes时只能申请其中的scope
+func (s *TokenService) normalizeScope(scope string) (string, error) {
+	var scopes []string
+	seen := make(map[string]bool)
+	for _, item := range strings.Fields(scope) {
+		if !validScopeToken(item) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidScope, item)
+		}
+		if len(s.cfg.AllowedScopes) > 0 && !slices.Contains(s.cfg.AllowedScopes, item) {
+			return "", fmt.Errorf("%w: %q is not allowed", ErrInvalidScope, item)
+		}
+		if !seen[item] {
+			seen[item] = true
+			scopes = append(scopes, item)
+		}
+	}
+	return strings.Join(scopes, " "), nil
+}
+
+// validScopeToken scope只能包含RFC 6749 3.3规定的字符
+func validScopeToken(scope string) bool {
+	for i := 0; i < len(scope); i++ {
+		c := scope[i]
+		if c < 0x21 || c > 0x7e || c == '"' || c == '\\' {
+			return false
+		}
+	}
+	return scope != ""
+}
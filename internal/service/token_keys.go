@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/vera-byte/vgo-iam/internal/crypto"
+	"github.com/vera-byte/vgo-iam/internal/model"
+	"github.com/vera-byte/vgo-iam/internal/store"
+	"github.com/vera-byte/vgo-iam/internal/util"
+)
+
+const (
+	// JWKSMaxAge 依赖方缓存JWKS的最长时间，next密钥至少发布这么久才会被启用
+	JWKSMaxAge = 5 * time.Minute
+	// signingKeyCheckInterval 检查是否需要轮换并重新加载签名密钥的间隔，也是副本之间密钥状态的最大延迟
+	signingKeyCheckInterval = time.Minute
+	// signingKeyReloadInterval 遇到未知kid时两次重新加载之间的最短间隔
+	signingKeyReloadInterval = 10 * time.Second
+)
+
+// signingKey 已加载的签名密钥，只有active密钥解密私钥
+type signingKey struct {
+	public  ed25519.PublicKey
+	private ed25519.PrivateKey
+}
+
+// SigningKeyAAD 返回签名私钥密文绑定的附加数据，密文被复制到其他密钥时无法解密
+func SigningKeyAAD(kid string) []byte {
+	return []byte("vgo-iam:token-signing-key\x00" + kid)
+}
+
+// RotateSigningKeys 检查签名密钥状态并重新加载
+// 没有active密钥时启用next密钥或直接创建；active密钥使用满key_rotation_interval且next密钥已发布满JWKSMaxAge时轮换；
+// 始终保留一个next密钥，它签发的令牌全部过期后删除retired密钥。多个副本并发执行时由状态唯一索引和条件更新保证只有一个生效
+func (s *TokenService) RotateSigningKeys(ctx context.Context) error {
+	keys, err := s.keyStore.List()
+	if err != nil {
+		return fmt.Errorf("failed to list token signing keys: %w", err)
+	}
+	var active, next *model.SigningKey
+	for _, key := range keys {
+		switch key.Status {
+		case model.SigningKeyStatusActive:
+			active = key
+		case model.SigningKeyStatusNext:
+			next = key
+		}
+	}
+
+	now := s.now()
+	switch {
+	case active == nil && next != nil:
+		if _, err := s.keyStore.Rotate(0, next.ID, now); err != nil {
+			return fmt.Errorf("failed to activate token signing key: %w", err)
+		}
+	case active == nil:
+		if err := s.createSigningKey(ctx, model.SigningKeyStatusActive, now); err != nil {
+			return err
+		}
+	case next != nil && active.ActivatedAt != nil &&
+		!now.Before(active.ActivatedAt.Add(s.cfg.KeyRotationInterval)) && !now.Before(next.CreatedAt.Add(JWKSMaxAge)):
+		rotated, err := s.keyStore.Rotate(active.ID, next.ID, now)
+		if err != nil {
+			return fmt.Errorf("failed to rotate token signing key: %w", err)
+		}
+		if rotated {
+			util.Logger.Info("Token signing key rotated", zap.String("retired_kid", active.KeyID), zap.String("active_kid", next.KeyID))
+			next = nil
+		}
+	}
+	if next == nil || active == nil {
+		if err := s.createSigningKey(ctx, model.SigningKeyStatusNext, now); err != nil {
+			return err
+		}
+	}
+
+	// 旧密钥签发的令牌最晚在轮换后TTL加上副本重新加载的延迟内过期
+	deleted, err := s.keyStore.DeleteRetired(now.Add(-(s.cfg.TTL + signingKeyCheckInterval + tokenClockSkew)))
+	if err != nil {
+		return fmt.Errorf("failed to delete retired token signing keys: %w", err)
+	}
+	if deleted > 0 {
+		util.Logger.Debug("Retired token signing keys deleted", zap.Int64("count", deleted))
+	}
+	return s.reloadSigningKeys(ctx)
+}
+
+// RunKeyRotation 周期性检查签名密钥轮换并重新加载其他副本的修改，直到ctx取消
+func (s *TokenService) RunKeyRotation(ctx context.Context) {
+	ticker := time.NewTicker(signingKeyCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.RotateSigningKeys(ctx); err != nil {
+				util.Logger.Error("Token signing key rotation failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// JWKS 返回用于校验访问令牌的公钥: next、active和尚未删除的retired密钥
+func (s *TokenService) JWKS() []crypto.JWK {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]crypto.JWK, 0, len(s.keys))
+	for kid, key := range s.keys {
+		keys = append(keys, crypto.NewJWK(kid, key.public))
+	}
+	slices.SortFunc(keys, func(a, b crypto.JWK) int { return strings.Compare(a.Kid, b.Kid) })
+	return keys
+}
+
+// ReEncryptSigningKeys 将全部签名私钥的密文迁移到当前主密钥，已使用当前主密钥的密钥被跳过
+func (s *TokenService) ReEncryptSigningKeys(ctx context.Context) (ReEncryptProgress, error) {
+	var progress ReEncryptProgress
+	keys, err := s.keyStore.List()
+	if err != nil {
+		return progress, fmt.Errorf("failed to list token signing keys: %w", err)
+	}
+	progress.Total = len(keys)
+
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return progress, err
+		}
+		reencrypted, changed, err := s.keyring.ReEncrypt(ctx, key.EncryptedPrivateKey, SigningKeyAAD(key.KeyID))
+		if err != nil {
+			return progress, fmt.Errorf("failed to re-encrypt token signing key %s: %w", key.KeyID, err)
+		}
+		progress.Processed++
+		progress.LastID = key.ID
+		if !changed {
+			progress.Skipped++
+			continue
+		}
+		replaced, err := s.keyStore.ReplaceEncryptedPrivateKey(key.ID, key.EncryptedPrivateKey, reencrypted)
+		if err != nil {
+			return progress, fmt.Errorf("failed to save re-encrypted token signing key %s: %w", key.KeyID, err)
+		}
+		if !replaced {
+			progress.Conflicts++
+			continue
+		}
+		progress.Migrated++
+	}
+	return progress, nil
+}
+
+// publicKey 按kid查找校验公钥，未知kid可能是其他副本刚创建的密钥，限频重新加载后再查找
+func (s *TokenService) publicKey(kid string) (ed25519.PublicKey, bool) {
+	s.mu.RLock()
+	key, ok := s.keys[kid]
+	loadedAt := s.loadedAt
+	s.mu.RUnlock()
+	if ok {
+		return key.public, true
+	}
+	if s.now().Before(loadedAt.Add(signingKeyReloadInterval)) {
+		return nil, false
+	}
+
+	if err := s.reloadSigningKeys(context.Background()); err != nil {
+		util.Logger.Error("Failed to reload token signing keys", zap.Error(err))
+		return nil, false
+	}
+	s.mu.RLock()
+	key, ok = s.keys[kid]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return key.public, true
+}
+
+// reloadSigningKeys 从数据库加载全部签名密钥并解密active私钥，替换内存中的密钥
+func (s *TokenService) reloadSigningKeys(ctx context.Context) error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	records, err := s.keyStore.List()
+	if err != nil {
+		return fmt.Errorf("failed to list token signing keys: %w", err)
+	}
+	keys := make(map[string]*signingKey, len(records))
+	activeKid := ""
+	for _, record := range records {
+		if len(record.PublicKey) != ed25519.PublicKeySize {
+			return fmt.Errorf("token signing key %s has an invalid public key", record.KeyID)
+		}
+		key := &signingKey{public: ed25519.PublicKey(record.PublicKey)}
+		if record.Status == model.SigningKeyStatusActive {
+			seed, err := s.keyring.DecryptWithAnyKey(ctx, record.EncryptedPrivateKey, SigningKeyAAD(record.KeyID))
+			if err != nil {
+				return fmt.Errorf("failed to decrypt token signing key %s: %w", record.KeyID, err)
+			}
+			if len(seed) != ed25519.SeedSize {
+				return fmt.Errorf("token signing key %s has an invalid private key", record.KeyID)
+			}
+			key.private = ed25519.NewKeyFromSeed(seed)
+			activeKid = record.KeyID
+		}
+		keys[record.KeyID] = key
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+	s.activeKid = activeKid
+	s.loadedAt = s.now()
+	return nil
+}
+
+// createSigningKey 生成Ed25519密钥，私钥种子使用当前主密钥加密后保存，其他副本已创建同状态密钥时忽略
+func (s *TokenService) createSigningKey(ctx context.Context, status string, now time.Time) error {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return fmt.Errorf("failed to generate token signing key: %w", err)
+	}
+	kid := crypto.JWKThumbprint(public)
+	encrypted, err := s.keyring.EncryptWithCurrentKey(ctx, private.Seed(), SigningKeyAAD(kid))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt token signing key: %w", err)
+	}
+
+	key := &model.SigningKey{
+		KeyID:               kid,
+		Algorithm:           crypto.JWTAlgEdDSA,
+		PublicKey:           public,
+		EncryptedPrivateKey: encrypted,
+		Status:              status,
+		CreatedAt:           now,
+	}
+	if status == model.SigningKeyStatusActive {
+		key.ActivatedAt = &now
+	}
+	err = s.keyStore.Create(key)
+	if errors.Is(err, store.ErrSigningKeyExists) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save token signing key: %w", err)
+	}
+	util.Logger.Info("Token signing key created", zap.String("kid", kid), zap.String("status", status))
+	return nil
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"go.uber.org/zap"
+
+	"github.com/vera-byte/vgo-iam/internal/config"
+	"github.com/vera-byte/vgo-iam/internal/model"
+	"github.com/vera-byte/vgo-iam/internal/store"
+	"github.com/vera-byte/vgo-iam/internal/util"
+)
+
+// fakeSigningKeyStore 内存中的签名密钥存储，模拟状态唯一索引和条件更新
+type fakeSigningKeyStore struct {
+	keys []*model.SigningKey
+}
+
+func (f *fakeSigningKeyStore) List() ([]*model.SigningKey, error) {
+	keys := make([]*model.SigningKey, len(f.keys))
+	for i, key := range f.keys {
+		copied := *key
+		keys[i] = &copied
+	}
+	return keys, nil
+}
+
+func (f *fakeSigningKeyStore) Create(key *model.SigningKey) error {
+	for _, existing := range f.keys {
+		if existing.Status == key.Status && key.Status != model.SigningKeyStatusRetired {
+			return store.ErrSigningKeyExists
+		}
+	}
+	key.ID = len(f.keys) + 1
+	f.keys = append(f.keys, key)
+	return nil
+}
+
+func (f *fakeSigningKeyStore) Rotate(activeID, nextID int, now time.Time) (bool, error) {
+	for _, key := range f.keys {
+		if key.ID == activeID {
+			key.Status, key.RetiredAt = model.SigningKeyStatusRetired, &now
+		}
+		if key.ID == nextID {
+			key.Status, key.ActivatedAt = model.SigningKeyStatusActive, &now
+		}
+	}
+	return true, nil
+}
+
+func (f *fakeSigningKeyStore) DeleteRetired(before time.Time) (int64, error) {
+	var kept []*model.SigningKey
+	for _, key := range f.keys {
+		if key.Status != model.SigningKeyStatusRetired || !key.RetiredAt.Before(before) {
+			kept = append(kept, key)
+		}
+	}
+	deleted := int64(len(f.keys) - len(kept))
+	f.keys = kept
+	return deleted, nil
+}
+
+func (f *fakeSigningKeyStore) ReplaceEncryptedPrivateKey(id int, old, new []byte) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeHashStore) GetByAccessKeyID(accessKeyID string) (*model.AccessKey, error) {
+	hashes, ok := f.hashes[accessKeyID]
+	if !ok {
+		return nil, dbr.ErrNotFound
+	}
+	return &model.AccessKey{AccessKeyID: accessKeyID, UserID: hashes.UserID, CredentialType: model.CredentialTypeAPIKey, Status: hashes.Status}, nil
+}
+
+func TestIssueAndValidateToken(t *testing.T) {
+	akStore := &fakeHashStore{hashes: map[string]*model.AccessKeySecretHashes{
+		"AKID": {UserID: 7, Status: "active", Current: mustHash(t, "secret")},
+	}}
+	accessKeys := NewAccessKeyService(akStore, fakeUserStore{}, nil, config.AccessKeyConfig{})
+	util.Logger = zap.NewNop()
+	keyStore := &fakeSigningKeyStore{}
+	svc := NewTokenService(accessKeys, keyStore, newTestKeyring(t), config.TokenConfig{
+		TTL: 15 * time.Minute, KeyRotationInterval: 10 * time.Minute, AllowedScopes: []string{"read", "write"},
+	})
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+	if err := svc.RotateSigningKeys(ctx); err != nil {
+		t.Fatalf("RotateSigningKeys failed: %v", err)
+	}
+	if len(keyStore.keys) != 2 || len(svc.JWKS()) != 2 {
+		t.Fatalf("expected active and next keys, got %d", len(keyStore.keys))
+	}
+
+	if _, err := svc.IssueToken(ctx, "AKID", "wrong", ""); !errors.Is(err, ErrInvalidClient) {
+		t.Errorf("expected ErrInvalidClient, got %v", err)
+	}
+	if _, err := svc.IssueToken(ctx, "AKID", "secret", "admin"); !errors.Is(err, ErrInvalidScope) {
+		t.Errorf("expected ErrInvalidScope, got %v", err)
+	}
+	token, err := svc.IssueToken(ctx, "AKID", "secret", "read write read")
+	if err != nil {
+		t.Fatalf("IssueToken failed: %v", err)
+	}
+	if token.Claims.Subject != "alice" || token.Claims.Scope != "read write" || token.Claims.Account != model.DefaultAccount {
+		t.Errorf("unexpected claims %+v", token.Claims)
+	}
+
+	// 轮换后旧密钥签发的令牌在有效期内仍然有效，新令牌使用新的kid
+	firstKid := keyStore.keys[0].KeyID
+	now = now.Add(10 * time.Minute)
+	if err := svc.RotateSigningKeys(ctx); err != nil {
+		t.Fatalf("RotateSigningKeys failed: %v", err)
+	}
+	if len(keyStore.keys) != 3 || keyStore.keys[0].Status != model.SigningKeyStatusRetired || keyStore.keys[1].Status != model.SigningKeyStatusActive {
+		t.Fatalf("expected key rotation, got %+v", keyStore.keys)
+	}
+	rotated, err := svc.IssueToken(ctx, "AKID", "secret", "")
+	if err != nil {
+		t.Fatalf("IssueToken failed: %v", err)
+	}
+	if tokenKid(t, rotated.AccessToken) != keyStore.keys[1].KeyID || tokenKid(t, token.AccessToken) != keyStore.keys[0].KeyID {
+		t.Error("expected tokens to carry the kid of their signing key")
+	}
+	principal, err := svc.ValidateToken(ctx, token.AccessToken)
+	if err != nil || principal.UserID != 7 || principal.AccessKeyID != "AKID" {
+		t.Fatalf("ValidateToken = %+v, %v", principal, err)
+	}
+	if _, err := svc.ValidateToken(ctx, token.AccessToken+"x"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken for tampered token, got %v", err)
+	}
+
+	// 访问密钥停用后令牌立即失效
+	akStore.hashes["AKID"].Status = "inactive"
+	if _, err := svc.ValidateToken(ctx, token.AccessToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken for inactive key, got %v", err)
+	}
+	akStore.hashes["AKID"].Status = "active"
+
+	now = now.Add(15 * time.Minute)
+	if _, err := svc.ValidateToken(ctx, rotated.AccessToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken for expired token, got %v", err)
+	}
+
+	// 旧密钥签发的令牌全部过期后删除retired密钥
+	now = now.Add(2 * time.Minute)
+	if err := svc.RotateSigningKeys(ctx); err != nil {
+		t.Fatalf("RotateSigningKeys failed: %v", err)
+	}
+	for _, key := range svc.JWKS() {
+		if key.Kid == firstKid {
+			t.Error("expected retired key to be removed from JWKS")
+		}
+	}
+}
+
+// tokenKid 返回JWT头部的kid
+func tokenKid(t *testing.T, token string) string {
+	t.Helper()
+	header, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[0])
+	if err != nil {
+		t.Fatalf("decode header failed: %v", err)
+	}
+	var h struct {
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(header, &h); err != nil {
+		t.Fatalf("unmarshal header failed: %v", err)
+	}
+	return h.Kid
+}
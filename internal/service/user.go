@@ -64,6 +64,11 @@ func (s *UserService) GetUser(ctx context.Context, name string) (*model.User, er
 	return s.userStore.GetByName(name)
 }
 
+// GetUserByID 根据用户ID获取用户
+func (s *UserService) GetUserByID(ctx context.Context, id int) (*model.User, error) {
+	return s.userStore.GetByID(id)
+}
+
 // AttachPolicy 为用户附加策略
 func (s *UserService) AttachPolicy(ctx context.Context, userName, policyName string) error {
 	// 获取用户
@@ -1,22 +1,62 @@
 package store
 
 import (
+	"database/sql"
+	"encoding/base64"
+	"errors"
 	"time"
 
 	"github.com/gocraft/dbr/v2"
-	"github.com/vera-byte/vgo-iam/internal/crypto"
 	"github.com/vera-byte/vgo-iam/internal/model"
 )
 
+var (
+	// ErrAccessKeyQuotaExceeded 用户的访问密钥数量已达上限
+	ErrAccessKeyQuotaExceeded = errors.New("access key quota exceeded")
+	// ErrSecretVersionConflict 访问密钥已被其他请求轮换或删除，或上次轮换的旧密钥仍在宽限期内
+	ErrSecretVersionConflict = errors.New("access key was modified concurrently")
+	// ErrAccessKeyActive 访问密钥仍处于激活状态，不能非强制删除
+	ErrAccessKeyActive = errors.New("access key is active, deactivate it first or force the deletion")
+)
+
 // AccessKeyStore 访问密钥存储接口
 type AccessKeyStore interface {
-	Create(ak *model.AccessKey, masterKey []byte) error
+	Create(ak *model.AccessKey, maxPerUser int) error
+	Delete(accessKeyID string, force bool) error
 	GetByID(id int) (*model.AccessKey, error)
 	GetByAccessKeyID(accessKeyID string) (*model.AccessKey, error)
+	GetEncryptedSecrets(accessKeyID string) (*model.AccessKeySecrets, error)
+	GetSecretHashes(accessKeyID string) (*model.AccessKeySecretHashes, error)
 	ListByUser(userID int) ([]*model.AccessKey, error)
 	ListAll() ([]*model.AccessKey, error)
+	ListExpiringBefore(t time.Time) ([]*model.AccessKey, error)
+	MarkExpiryNotified(accessKeyID string) (bool, error)
 	UpdateStatus(accessKeyID, status string) error
-	RotateKey(accessKeyID string, masterKey []byte) (*model.AccessKey, error)
+	RotateKey(accessKeyID string, version int, encryptedSecret []byte, graceUntil time.Time) error
+	RotateSecretHash(accessKeyID string, version int, secretHash string, graceUntil time.Time) error
+	RetireExpiredPreviousSecrets(now time.Time) (int64, error)
+	UpdateLastUsed(usages []model.AccessKeyUsage) error
+	CountEncryptedSecrets(afterID int) (int, error)
+	ListEncryptedSecrets(afterID, limit int) ([]*model.EncryptedSecretRecord, error)
+	ReplaceEncryptedSecrets(record *model.EncryptedSecretRecord, current, previous []byte) (bool, error)
+}
+
+// accessKeyColumns 查询访问密钥元数据时使用的列，不包含密钥密文
+var accessKeyColumns = []interface{}{
+	"id",
+	"user_id",
+	"access_key_id",
+	"credential_type",
+	"secret_version",
+	"status",
+	"created_at",
+	"updated_at",
+	"expires_at",
+	"last_rotated_at",
+	"previous_secret_expires_at",
+	"last_used_at",
+	"COALESCE(last_used_method, '') AS last_used_method",
+	"COALESCE(last_used_ip, '') AS last_used_ip",
 }
 
 // accessKeyStore 访问密钥存储实现
@@ -29,33 +69,93 @@ func NewAccessKeyStore(session *dbr.Session) AccessKeyStore {
 	return &accessKeyStore{session: session}
 }
 
-func (s *accessKeyStore) Create(ak *model.AccessKey, masterKey []byte) error {
-	// 加密密钥
-	encryptedSecret, err := crypto.EncryptKey([]byte(ak.SecretAccessKey), masterKey)
+// Create 保存访问密钥，签名凭证的密钥须已由服务层加密并写入EncryptedSecretKey，API密钥须已写入SecretHash
+// maxPerUser大于0时在事务中校验用户密钥数量，锁定用户行避免并发创建超出上限
+func (s *accessKeyStore) Create(ak *model.AccessKey, maxPerUser int) error {
+	tx, err := s.session.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.RollbackUnlessCommitted()
 
-	_, err = s.session.InsertInto("access_keys").
+	if maxPerUser > 0 {
+		var userID int
+		if err := tx.Select("id").
+			From("users").
+			Where("id = ?", ak.UserID).
+			Suffix("FOR UPDATE").
+			LoadOne(&userID); err != nil {
+			return err
+		}
+
+		var count int
+		if err := tx.Select("COUNT(*)").
+			From("access_keys").
+			Where("user_id = ?", ak.UserID).
+			LoadOne(&count); err != nil {
+			return err
+		}
+		if count >= maxPerUser {
+			return ErrAccessKeyQuotaExceeded
+		}
+	}
+
+	_, err = tx.InsertInto("access_keys").
 		Columns(
 			"user_id",
 			"access_key_id",
+			"credential_type",
 			"encrypted_secret_access_key",
+			"secret_hash",
+			"secret_version",
 			"status",
+			"expires_at",
 		).
 		Values(
 			ak.UserID,
 			ak.AccessKeyID,
-			encryptedSecret,
+			ak.CredentialType,
+			nullableSecret(ak.EncryptedSecretKey),
+			nullableString(ak.SecretHash),
+			ak.SecretVersion,
 			ak.Status,
+			ak.ExpiresAt,
 		).Exec()
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
 
-	return err
+// Delete 删除访问密钥，非强制删除时状态检查在同一条语句中完成，避免检查后被并发启用
+// 未删除任何行时再查询一次区分密钥不存在(dbr.ErrNotFound)和仍处于激活状态(ErrAccessKeyActive)
+func (s *accessKeyStore) Delete(accessKeyID string, force bool) error {
+	result, err := s.session.DeleteFrom("access_keys").
+		Where("access_key_id = ? AND (status <> 'active' OR ?)", accessKeyID, force).
+		Exec()
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected > 0 {
+		return nil
+	}
+
+	var count int
+	if err := s.session.Select("COUNT(*)").
+		From("access_keys").
+		Where("access_key_id = ?", accessKeyID).
+		LoadOne(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrAccessKeyActive
+	}
+	return dbr.ErrNotFound
 }
 
 func (s *accessKeyStore) GetByID(id int) (*model.AccessKey, error) {
 	var ak model.AccessKey
-	err := s.session.Select("*").
+	err := s.session.Select(accessKeyColumns...).
 		From("access_keys").
 		Where("id = ?", id).
 		LoadOne(&ak)
@@ -65,21 +165,90 @@ func (s *accessKeyStore) GetByID(id int) (*model.AccessKey, error) {
 
 func (s *accessKeyStore) GetByAccessKeyID(accessKeyID string) (*model.AccessKey, error) {
 	var ak model.AccessKey
+	err := s.session.Select(accessKeyColumns...).
+		From("access_keys").
+		Where("access_key_id = ?", accessKeyID).
+		LoadOne(&ak)
+	return &ak, err
+}
+
+// GetEncryptedSecrets 获取访问密钥的密文，仅供服务层解密校验签名使用
+// 处于轮换宽限期内时同时返回旧密钥密文；API密钥没有密文，返回dbr.ErrNotFound
+func (s *accessKeyStore) GetEncryptedSecrets(accessKeyID string) (*model.AccessKeySecrets, error) {
+	var row struct {
+		UserID            int            `db:"user_id"`
+		Version           int            `db:"secret_version"`
+		Current           string         `db:"encrypted_secret_access_key"`
+		Previous          sql.NullString `db:"previous_encrypted_secret_access_key"`
+		PreviousExpiresAt *time.Time     `db:"previous_secret_expires_at"`
+	}
+	err := s.session.Select(
+		"user_id",
+		"secret_version",
+		"encrypted_secret_access_key",
+		"previous_encrypted_secret_access_key",
+		"previous_secret_expires_at",
+	).From("access_keys").
+		Where("access_key_id = ? AND credential_type = ?", accessKeyID, model.CredentialTypeSigning).
+		LoadOne(&row)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := &model.AccessKeySecrets{UserID: row.UserID, Version: row.Version}
+	if secrets.Current, err = decodeSecret(row.Current); err != nil {
+		return nil, err
+	}
+	if row.Previous.Valid && row.PreviousExpiresAt != nil {
+		if secrets.Previous, err = decodeSecret(row.Previous.String); err != nil {
+			return nil, err
+		}
+		secrets.PreviousExpiresAt = row.PreviousExpiresAt
+	}
+	return secrets, nil
+}
+
+// GetSecretHashes 获取API密钥的状态和密钥哈希，仅供服务层校验API密钥使用
+// 处于轮换宽限期内时同时返回旧密钥哈希；签名凭证返回dbr.ErrNotFound
+func (s *accessKeyStore) GetSecretHashes(accessKeyID string) (*model.AccessKeySecretHashes, error) {
+	var row struct {
+		UserID            int            `db:"user_id"`
+		Status            string         `db:"status"`
+		ExpiresAt         *time.Time     `db:"expires_at"`
+		Current           string         `db:"secret_hash"`
+		Previous          sql.NullString `db:"previous_secret_hash"`
+		PreviousExpiresAt *time.Time     `db:"previous_secret_expires_at"`
+	}
 	err := s.session.Select(
-		"id",
 		"user_id",
-		"access_key_id",
 		"status",
-		"created_at",
-		"updated_at",
+		"expires_at",
+		"secret_hash",
+		"previous_secret_hash",
+		"previous_secret_expires_at",
 	).From("access_keys").
-		Where("access_key_id = ?", accessKeyID).
-		LoadOne(&ak)
-	return &ak, err
+		Where("access_key_id = ? AND credential_type = ?", accessKeyID, model.CredentialTypeAPIKey).
+		LoadOne(&row)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := &model.AccessKeySecretHashes{
+		UserID:    row.UserID,
+		Status:    row.Status,
+		ExpiresAt: row.ExpiresAt,
+		Current:   row.Current,
+	}
+	if row.Previous.Valid && row.PreviousExpiresAt != nil {
+		hashes.Previous = row.Previous.String
+		hashes.PreviousExpiresAt = row.PreviousExpiresAt
+	}
+	return hashes, nil
 }
+
 func (s *accessKeyStore) ListByUser(userID int) ([]*model.AccessKey, error) {
 	var aks []*model.AccessKey
-	_, err := s.session.Select("*").
+	_, err := s.session.Select(accessKeyColumns...).
 		From("access_keys").
 		Where("user_id = ?", userID).
 		Load(&aks)
@@ -95,59 +264,226 @@ func (s *accessKeyStore) UpdateStatus(accessKeyID, status string) error {
 	return err
 }
 
-func (s *accessKeyStore) RotateKey(accessKeyID string, masterKey []byte) (*model.AccessKey, error) {
-	// 1. 获取现有密钥
-	ak, err := s.GetByAccessKeyID(accessKeyID)
+// RotateKey 轮换访问密钥：当前密文转为旧密文并保留到graceUntil，新密文成为当前密钥，版本加1
+// 仅当当前版本仍为version且没有宽限期内的旧密钥时更新，新密文须以version+1加密；
+// 并发轮换、已删除或旧密钥仍在宽限期内时返回ErrSecretVersionConflict
+func (s *accessKeyStore) RotateKey(accessKeyID string, version int, encryptedSecret []byte, graceUntil time.Time) error {
+	now := time.Now()
+	result, err := s.session.Update("access_keys").
+		Set("previous_encrypted_secret_access_key", dbr.Expr("encrypted_secret_access_key")).
+		Set("previous_secret_expires_at", graceUntil).
+		Set("encrypted_secret_access_key", encodeSecret(encryptedSecret)).
+		Set("secret_version", version+1).
+		Set("last_rotated_at", now).
+		Set("updated_at", now).
+		Where("access_key_id = ? AND secret_version = ?", accessKeyID, version).
+		Where("(previous_secret_expires_at IS NULL OR previous_secret_expires_at <= ?)", now).
+		Exec()
 	if err != nil {
-		return nil, err
+		return err
 	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrSecretVersionConflict
+	}
+	return nil
+}
 
-	// 2. 生成新密钥
-	newSecret := generateRandomSecret()
-
-	// 3. 加密新密钥
-	encryptedSecret, err := crypto.EncryptKey([]byte(newSecret), masterKey)
+// RotateSecretHash 轮换API密钥：当前哈希转为旧哈希并保留到graceUntil，新哈希成为当前密钥，版本加1
+// 条件与RotateKey相同，不满足时返回ErrSecretVersionConflict
+func (s *accessKeyStore) RotateSecretHash(accessKeyID string, version int, secretHash string, graceUntil time.Time) error {
+	now := time.Now()
+	result, err := s.session.Update("access_keys").
+		Set("previous_secret_hash", dbr.Expr("secret_hash")).
+		Set("previous_secret_expires_at", graceUntil).
+		Set("secret_hash", secretHash).
+		Set("secret_version", version+1).
+		Set("last_rotated_at", now).
+		Set("updated_at", now).
+		Where("access_key_id = ? AND credential_type = ? AND secret_version = ?", accessKeyID, model.CredentialTypeAPIKey, version).
+		Where("(previous_secret_expires_at IS NULL OR previous_secret_expires_at <= ?)", now).
+		Exec()
 	if err != nil {
-		return nil, err
+		return err
 	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrSecretVersionConflict
+	}
+	return nil
+}
 
-	// 4. 更新数据库
-	_, err = s.session.Update("access_keys").
-		Set("encrypted_secret_access_key", encryptedSecret).
-		Set("updated_at", time.Now()).
-		Where("access_key_id = ?", accessKeyID).
+// RetireExpiredPreviousSecrets 清除宽限期已结束的旧密钥密文和哈希，返回清理的数量
+func (s *accessKeyStore) RetireExpiredPreviousSecrets(now time.Time) (int64, error) {
+	result, err := s.session.Update("access_keys").
+		Set("previous_encrypted_secret_access_key", nil).
+		Set("previous_secret_hash", nil).
+		Set("previous_secret_expires_at", nil).
+		Where("previous_secret_expires_at IS NOT NULL AND previous_secret_expires_at <= ?", now).
 		Exec()
-
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-
-	// 5. 返回新密钥（仅本次返回）
-	return &model.AccessKey{
-		AccessKeyID:     ak.AccessKeyID,
-		SecretAccessKey: newSecret,
-		Status:          ak.Status,
-	}, nil
+	return result.RowsAffected()
 }
 
 // ListAll 获取所有访问密钥
 func (s *accessKeyStore) ListAll() ([]*model.AccessKey, error) {
 	var aks []*model.AccessKey
+	_, err := s.session.Select(accessKeyColumns...).
+		From("access_keys").
+		Load(&aks)
+	return aks, err
+}
+
+// ListExpiringBefore 获取在指定时间之前过期且仍处于激活状态的访问密钥
+func (s *accessKeyStore) ListExpiringBefore(t time.Time) ([]*model.AccessKey, error) {
+	var aks []*model.AccessKey
+	_, err := s.session.Select(accessKeyColumns...).
+		From("access_keys").
+		Where("status = ? AND expires_at IS NOT NULL AND expires_at <= ?", "active", t).
+		OrderBy("expires_at").
+		Load(&aks)
+	return aks, err
+}
+
+// MarkExpiryNotified 记录已发送即将过期告警，已记录过时返回false
+// 条件更新保证多个副本同时检查时只有一个副本告警
+func (s *accessKeyStore) MarkExpiryNotified(accessKeyID string) (bool, error) {
+	result, err := s.session.Update("access_keys").
+		Set("expiry_notified_at", dbr.Now).
+		Where("access_key_id = ? AND expiry_notified_at IS NULL", accessKeyID).
+		Exec()
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// UpdateLastUsed 批量更新访问密钥最后使用信息
+func (s *accessKeyStore) UpdateLastUsed(usages []model.AccessKeyUsage) error {
+	tx, err := s.session.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	for _, usage := range usages {
+		_, err := tx.Update("access_keys").
+			Set("last_used_at", usage.UsedAt).
+			Set("last_used_method", usage.Method).
+			Set("last_used_ip", usage.SourceIP).
+			Where("access_key_id = ?", usage.AccessKeyID).
+			Exec()
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// CountEncryptedSecrets 统计主键大于afterID的签名凭证数量，API密钥只保存哈希，不需要重新加密
+func (s *accessKeyStore) CountEncryptedSecrets(afterID int) (int, error) {
+	var count int
+	err := s.session.Select("COUNT(*)").
+		From("access_keys").
+		Where("id > ? AND credential_type = ?", afterID, model.CredentialTypeSigning).
+		LoadOne(&count)
+	return count, err
+}
+
+// ListEncryptedSecrets 按主键顺序获取主键大于afterID的最多limit条签名凭证密文记录
+func (s *accessKeyStore) ListEncryptedSecrets(afterID, limit int) ([]*model.EncryptedSecretRecord, error) {
+	var rows []struct {
+		ID          int            `db:"id"`
+		AccessKeyID string         `db:"access_key_id"`
+		UserID      int            `db:"user_id"`
+		Version     int            `db:"secret_version"`
+		Current     string         `db:"encrypted_secret_access_key"`
+		Previous    sql.NullString `db:"previous_encrypted_secret_access_key"`
+	}
 	_, err := s.session.Select(
 		"id",
-		"user_id",
 		"access_key_id",
-		"status",
-		"created_at",
-		"updated_at",
+		"user_id",
+		"secret_version",
+		"encrypted_secret_access_key",
+		"previous_encrypted_secret_access_key",
 	).From("access_keys").
-		Load(&aks)
-	return aks, err
+		Where("id > ? AND credential_type = ?", afterID, model.CredentialTypeSigning).
+		OrderBy("id").
+		Limit(uint64(limit)).
+		Load(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*model.EncryptedSecretRecord, 0, len(rows))
+	for _, row := range rows {
+		record := &model.EncryptedSecretRecord{
+			ID:          row.ID,
+			AccessKeyID: row.AccessKeyID,
+			UserID:      row.UserID,
+			Version:     row.Version,
+		}
+		if record.Current, err = decodeSecret(row.Current); err != nil {
+			return nil, err
+		}
+		if row.Previous.Valid {
+			if record.Previous, err = decodeSecret(row.Previous.String); err != nil {
+				return nil, err
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// ReplaceEncryptedSecrets 替换记录的密文，previous为空时保持旧密钥密文为空
+// 仅当数据库中的密文仍与record一致时更新，期间被轮换或删除时返回false
+func (s *accessKeyStore) ReplaceEncryptedSecrets(record *model.EncryptedSecretRecord, current, previous []byte) (bool, error) {
+	stmt := s.session.Update("access_keys").
+		Set("encrypted_secret_access_key", encodeSecret(current)).
+		Where("id = ? AND secret_version = ? AND encrypted_secret_access_key = ?", record.ID, record.Version, encodeSecret(record.Current))
+	if record.Previous != nil {
+		stmt = stmt.Set("previous_encrypted_secret_access_key", encodeSecret(previous)).
+			Where("previous_encrypted_secret_access_key = ?", encodeSecret(record.Previous))
+	} else {
+		stmt = stmt.Where("previous_encrypted_secret_access_key IS NULL")
+	}
+
+	result, err := stmt.Exec()
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// encodeSecret 将密文编码为base64，encrypted_secret_access_key 列为TEXT类型
+func encodeSecret(ciphertext []byte) string {
+	return base64.StdEncoding.EncodeToString(ciphertext)
+}
+
+// nullableSecret 编码密文，没有密文时写入NULL
+func nullableSecret(ciphertext []byte) interface{} {
+	if ciphertext == nil {
+		return nil
+	}
+	return encodeSecret(ciphertext)
+}
+
+// nullableString 空字符串写入NULL
+func nullableString(v string) interface{} {
+	if v == "" {
+		return nil
+	}
+	return v
 }
 
-// generateRandomSecret 生成随机密钥
-func generateRandomSecret() string {
-	// 实际实现使用安全随机数生成器
-	// 这里简化为示例
-	return "new-secret-key-" + time.Now().Format("20060102150405")
+// decodeSecret 解码数据库中保存的密文
+func decodeSecret(encoded string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(encoded)
 }
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/vera-byte/vgo-iam/internal/model"
+)
+
+// AuthFailureStore 认证失败计数存储接口
+type AuthFailureStore interface {
+	Get(subjects []string) ([]*model.AuthFailure, error)
+	RecordFailure(subject string, now time.Time, window time.Duration) (*model.AuthFailure, error)
+	Lock(subject string, now, until time.Time) (bool, error)
+	Clear(subjects []string) error
+	DeleteExpired(before, now time.Time) (int64, error)
+}
+
+// authFailureStore 认证失败计数存储实现
+type authFailureStore struct {
+	session *dbr.Session
+}
+
+// NewAuthFailureStore 创建认证失败计数存储实例
+func NewAuthFailureStore(session *dbr.Session) AuthFailureStore {
+	return &authFailureStore{session: session}
+}
+
+// Get 获取多个认证对象的失败记录，没有记录的对象不返回
+func (s *authFailureStore) Get(subjects []string) ([]*model.AuthFailure, error) {
+	var failures []*model.AuthFailure
+	if len(subjects) == 0 {
+		return failures, nil
+	}
+	_, err := s.session.Select("subject", "failures", "window_started_at", "last_failure_at", "locked_until").
+		From("auth_failures").
+		Where("subject IN ?", subjects).
+		Load(&failures)
+	return failures, err
+}
+
+// RecordFailure 原子地记录一次失败并返回最新记录，窗口已结束时从1重新计数
+func (s *authFailureStore) RecordFailure(subject string, now time.Time, window time.Duration) (*model.AuthFailure, error) {
+	var failure model.AuthFailure
+	windowStart := now.Add(-window)
+	err := s.session.InsertBySql(
+		`INSERT INTO auth_failures (subject, failures, window_started_at, last_failure_at)
+		VALUES (?, 1, ?, ?)
+		ON CONFLICT (subject) DO UPDATE SET
+			failures = CASE WHEN auth_failures.window_started_at <= ? THEN 1 ELSE auth_failures.failures + 1 END,
+			window_started_at = CASE WHEN auth_failures.window_started_at <= ? THEN EXCLUDED.window_started_at ELSE auth_failures.window_started_at END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING subject, failures, window_started_at, last_failure_at, locked_until`,
+		subject, now, now, windowStart, windowStart,
+	).Load(&failure)
+	if err != nil {
+		return nil, err
+	}
+	return &failure, nil
+}
+
+// Lock 锁定认证对象到until，now时已处于锁定状态时不修改并返回false
+func (s *authFailureStore) Lock(subject string, now, until time.Time) (bool, error) {
+	result, err := s.session.Update("auth_failures").
+		Set("locked_until", until).
+		Where("subject = ? AND (locked_until IS NULL OR locked_until <= ?)", subject, now).
+		Exec()
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// Clear 删除认证对象的失败记录和锁定状态
+func (s *authFailureStore) Clear(subjects []string) error {
+	if len(subjects) == 0 {
+		return nil
+	}
+	_, err := s.session.DeleteFrom("auth_failures").
+		Where("subject IN ?", subjects).
+		Exec()
+	return err
+}
+
+// DeleteExpired 清理before之前最后一次失败、且now时未处于锁定状态的记录
+func (s *authFailureStore) DeleteExpired(before, now time.Time) (int64, error) {
+	result, err := s.session.DeleteFrom("auth_failures").
+		Where("last_failure_at < ? AND (locked_until IS NULL OR locked_until <= ?)", before, now).
+		Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,116 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/vera-byte/vgo-iam/internal/model"
+)
+
+// bootstrapLockID 首次启动初始化使用的PostgreSQL advisory lock，避免多副本同时初始化
+const bootstrapLockID = 0x76676f69616d // "vgoiam"
+
+// ErrBootstrapPolicyConflict 同名管理员策略已存在且内容不同，不覆盖运维人员修改过的策略
+var ErrBootstrapPolicyConflict = errors.New("bootstrap policy already exists with a different document")
+
+// BootstrapStore 首次启动初始化存储接口
+type BootstrapStore interface {
+	// CreateRootAdmin 数据库中没有任何用户时，在同一事务中创建根管理员、管理员策略及访问密钥
+	// 密文与用户ID绑定，因此在创建用户后调用encryptSecret加密访问密钥
+	// 已存在用户时不做任何修改并返回false；同名策略已存在且内容不同时返回ErrBootstrapPolicyConflict
+	CreateRootAdmin(user *model.User, policy *model.Policy, ak *model.AccessKey, encryptSecret func(ak *model.AccessKey) error) (bool, error)
+}
+
+// bootstrapStore 首次启动初始化存储实现
+type bootstrapStore struct {
+	session *dbr.Session
+}
+
+// NewBootstrapStore 创建首次启动初始化存储实例
+func NewBootstrapStore(session *dbr.Session) BootstrapStore {
+	return &bootstrapStore{session: session}
+}
+
+func (s *bootstrapStore) CreateRootAdmin(user *model.User, policy *model.Policy, ak *model.AccessKey, encryptSecret func(ak *model.AccessKey) error) (bool, error) {
+	tx, err := s.session.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	// 1. 加锁后检查是否已初始化
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", bootstrapLockID); err != nil {
+		return false, err
+	}
+	var count int
+	if err := tx.Select("COUNT(*)").From("users").LoadOne(&count); err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	// 2. 创建根管理员
+	if err := tx.InsertInto("users").
+		Columns("name", "display_name", "email").
+		Values(user.Name, user.DisplayName, user.Email).
+		Returning("id").
+		Load(&user.ID); err != nil {
+		return false, err
+	}
+
+	// 3. 创建管理员策略，同名策略已存在时不修改，内容与管理员权限一致才复用，否则返回ErrBootstrapPolicyConflict
+	if _, err := tx.InsertBySql(
+		"INSERT INTO policies (name, description, policy_document) VALUES (?, ?, ?) ON CONFLICT (name) DO NOTHING",
+		policy.Name, policy.Description, policy.PolicyDocument,
+	).Exec(); err != nil {
+		return false, err
+	}
+	var existing struct {
+		ID      int  `db:"id"`
+		Matches bool `db:"matches"`
+	}
+	if err := tx.SelectBySql(
+		"SELECT id, policy_document = ?::jsonb AS matches FROM policies WHERE name = ?",
+		policy.PolicyDocument, policy.Name,
+	).LoadOne(&existing); err != nil {
+		return false, err
+	}
+	if !existing.Matches {
+		return false, ErrBootstrapPolicyConflict
+	}
+	policy.ID = existing.ID
+	if _, err := tx.InsertInto("user_policies").
+		Columns("user_id", "policy_id").
+		Values(user.ID, policy.ID).
+		Exec(); err != nil {
+		return false, err
+	}
+
+	// 4. 创建访问密钥
+	ak.UserID = user.ID
+	if err := encryptSecret(ak); err != nil {
+		return false, err
+	}
+	if _, err := tx.InsertInto("access_keys").
+		Columns(
+			"user_id",
+			"access_key_id",
+			"encrypted_secret_access_key",
+			"secret_version",
+			"status",
+			"expires_at",
+		).
+		Values(
+			ak.UserID,
+			ak.AccessKeyID,
+			encodeSecret(ak.EncryptedSecretKey),
+			ak.SecretVersion,
+			ak.Status,
+			ak.ExpiresAt,
+		).Exec(); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
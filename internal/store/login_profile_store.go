@@ -0,0 +1,178 @@
+package store
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/vera-byte/vgo-iam/internal/model"
+)
+
+// ErrLoginProfileExists 用户已有登录配置
+var ErrLoginProfileExists = errors.New("login profile already exists")
+
+// LoginProfileStore 登录配置存储接口，密码哈希保存在users.password_hash
+type LoginProfileStore interface {
+	GetByUserName(userName string) (*model.LoginProfile, error)
+	Create(userID int, passwordHash string, resetRequired bool, historySize int) error
+	UpdatePassword(userID int, passwordHash string, resetRequired bool, historySize int) error
+	UpdateResetRequired(userID int, resetRequired bool) error
+	Delete(userID int) error
+	ListPasswordHistory(userID, limit int) ([]string, error)
+}
+
+// loginProfileStore 登录配置存储实现
+type loginProfileStore struct {
+	session *dbr.Session
+}
+
+// NewLoginProfileStore 创建登录配置存储实例
+func NewLoginProfileStore(session *dbr.Session) LoginProfileStore {
+	return &loginProfileStore{session: session}
+}
+
+// GetByUserName 获取用户的登录配置，用户不存在或没有登录配置时返回dbr.ErrNotFound
+func (s *loginProfileStore) GetByUserName(userName string) (*model.LoginProfile, error) {
+	var profile model.LoginProfile
+	err := s.session.Select(
+		"id AS user_id",
+		"name AS user_name",
+		"password_hash",
+		"password_changed_at",
+		"password_reset_required",
+		"login_profile_created_at AS created_at",
+	).From("users").
+		Where("name = ? AND password_hash IS NOT NULL", userName).
+		LoadOne(&profile)
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// Create 创建登录配置，用户已有登录配置时返回ErrLoginProfileExists
+func (s *loginProfileStore) Create(userID int, passwordHash string, resetRequired bool, historySize int) error {
+	tx, err := s.session.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	now := time.Now()
+	result, err := tx.Update("users").
+		Set("password_hash", passwordHash).
+		Set("password_changed_at", now).
+		Set("password_reset_required", resetRequired).
+		Set("login_profile_created_at", now).
+		Set("updated_at", now).
+		Where("id = ? AND password_hash IS NULL", userID).
+		Exec()
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrLoginProfileExists
+	}
+	if err := appendPasswordHistory(tx, userID, passwordHash, historySize); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdatePassword 修改密码并记录到历史密码，用户没有登录配置时返回dbr.ErrNotFound
+func (s *loginProfileStore) UpdatePassword(userID int, passwordHash string, resetRequired bool, historySize int) error {
+	tx, err := s.session.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	now := time.Now()
+	result, err := tx.Update("users").
+		Set("password_hash", passwordHash).
+		Set("password_changed_at", now).
+		Set("password_reset_required", resetRequired).
+		Set("updated_at", now).
+		Where("id = ? AND password_hash IS NOT NULL", userID).
+		Exec()
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return dbr.ErrNotFound
+	}
+	if err := appendPasswordHistory(tx, userID, passwordHash, historySize); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateResetRequired 只修改下次登录是否必须修改密码，用户没有登录配置时返回dbr.ErrNotFound
+func (s *loginProfileStore) UpdateResetRequired(userID int, resetRequired bool) error {
+	result, err := s.session.Update("users").
+		Set("password_reset_required", resetRequired).
+		Set("updated_at", time.Now()).
+		Where("id = ? AND password_hash IS NOT NULL", userID).
+		Exec()
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return dbr.ErrNotFound
+	}
+	return nil
+}
+
+// Delete 删除登录配置，保留历史密码以便重新创建时仍禁止重复使用
+// 用户没有登录配置时返回dbr.ErrNotFound
+func (s *loginProfileStore) Delete(userID int) error {
+	result, err := s.session.Update("users").
+		Set("password_hash", nil).
+		Set("password_changed_at", nil).
+		Set("password_reset_required", false).
+		Set("login_profile_created_at", nil).
+		Set("updated_at", time.Now()).
+		Where("id = ? AND password_hash IS NOT NULL", userID).
+		Exec()
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return dbr.ErrNotFound
+	}
+	return nil
+}
+
+// ListPasswordHistory 获取最近limit次设置的密码哈希，最新的在前，包含当前密码
+func (s *loginProfileStore) ListPasswordHistory(userID, limit int) ([]string, error) {
+	var hashes []string
+	if limit <= 0 {
+		return hashes, nil
+	}
+	_, err := s.session.Select("password_hash").
+		From("password_history").
+		Where("user_id = ?", userID).
+		OrderDesc("id").
+		Limit(uint64(limit)).
+		Load(&hashes)
+	return hashes, err
+}
+
+// appendPasswordHistory 记录新密码哈希，只保留最近historySize条
+func appendPasswordHistory(tx *dbr.Tx, userID int, passwordHash string, historySize int) error {
+	if historySize <= 0 {
+		_, err := tx.DeleteFrom("password_history").Where("user_id = ?", userID).Exec()
+		return err
+	}
+	if _, err := tx.InsertInto("password_history").
+		Columns("user_id", "password_hash").
+		Values(userID, passwordHash).
+		Exec(); err != nil {
+		return err
+	}
+	_, err := tx.DeleteFrom("password_history").
+		Where("user_id = ? AND id NOT IN (SELECT id FROM password_history WHERE user_id = ? ORDER BY id DESC LIMIT ?)",
+			userID, userID, historySize).
+		Exec()
+	return err
+}
@@ -0,0 +1,168 @@
+package store
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/vera-byte/vgo-iam/internal/model"
+)
+
+// ErrMFADeviceExists 同名MFA设备已存在
+var ErrMFADeviceExists = errors.New("mfa device already exists")
+
+// MFADeviceStore 虚拟MFA设备存储接口
+type MFADeviceStore interface {
+	Create(device *model.MFADevice) error
+	GetBySerialNumber(serialNumber string) (*model.MFADevice, error)
+	ListByUser(userID int) ([]*model.MFADevice, error)
+	Activate(id int, step int64) error
+	ConsumeStep(id int, step int64) (bool, error)
+	Delete(id int) error
+	ListAfter(afterID, limit int) ([]*model.MFADevice, error)
+	ReplaceEncryptedSecret(id int, old, new []byte) (bool, error)
+}
+
+// mfaDeviceStore 虚拟MFA设备存储实现
+type mfaDeviceStore struct {
+	session *dbr.Session
+}
+
+// NewMFADeviceStore 创建虚拟MFA设备存储实例
+func NewMFADeviceStore(session *dbr.Session) MFADeviceStore {
+	return &mfaDeviceStore{session: session}
+}
+
+// mfaDeviceColumns 查询MFA设备时使用的列
+var mfaDeviceColumns = []interface{}{
+	"d.id",
+	"d.user_id",
+	"u.name AS user_name",
+	"d.serial_number",
+	"d.encrypted_secret",
+	"d.status",
+	"d.last_used_step",
+	"d.created_at",
+	"d.enabled_at",
+}
+
+// Create 创建待启用的MFA设备，序列号已存在时返回ErrMFADeviceExists
+func (s *mfaDeviceStore) Create(device *model.MFADevice) error {
+	result, err := s.session.InsertBySql(
+		`INSERT INTO mfa_devices (user_id, serial_number, encrypted_secret, status, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (serial_number) DO NOTHING`,
+		device.UserID, device.SerialNumber, device.EncryptedSecret, device.Status, device.CreatedAt,
+	).Exec()
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrMFADeviceExists
+	}
+	return nil
+}
+
+// GetBySerialNumber 根据序列号获取MFA设备，不存在时返回dbr.ErrNotFound
+func (s *mfaDeviceStore) GetBySerialNumber(serialNumber string) (*model.MFADevice, error) {
+	var device model.MFADevice
+	err := s.session.Select(mfaDeviceColumns...).
+		From("mfa_devices d").
+		Join("users u", "u.id = d.user_id").
+		Where("d.serial_number = ?", serialNumber).
+		LoadOne(&device)
+	if err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// ListByUser 获取用户的全部MFA设备，按创建顺序排列
+func (s *mfaDeviceStore) ListByUser(userID int) ([]*model.MFADevice, error) {
+	var devices []*model.MFADevice
+	_, err := s.session.Select(mfaDeviceColumns...).
+		From("mfa_devices d").
+		Join("users u", "u.id = d.user_id").
+		Where("d.user_id = ?", userID).
+		OrderAsc("d.id").
+		Load(&devices)
+	return devices, err
+}
+
+// Activate 启用待启用的MFA设备，并记录启用时使用的时间步
+// 设备不存在或已启用时返回dbr.ErrNotFound
+func (s *mfaDeviceStore) Activate(id int, step int64) error {
+	result, err := s.session.Update("mfa_devices").
+		Set("status", model.MFADeviceStatusActive).
+		Set("last_used_step", step).
+		Set("enabled_at", time.Now()).
+		Where("id = ? AND status = ?", id, model.MFADeviceStatusPending).
+		Exec()
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return dbr.ErrNotFound
+	}
+	return nil
+}
+
+// ConsumeStep 原子地记录已使用的时间步，时间步不大于上次使用的时间步时返回false
+// 同一验证码只能使用一次，并发请求中只有一个成功
+func (s *mfaDeviceStore) ConsumeStep(id int, step int64) (bool, error) {
+	result, err := s.session.Update("mfa_devices").
+		Set("last_used_step", step).
+		Where("id = ? AND status = ? AND last_used_step < ?", id, model.MFADeviceStatusActive, step).
+		Exec()
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// Delete 删除MFA设备及其密钥
+func (s *mfaDeviceStore) Delete(id int) error {
+	result, err := s.session.DeleteFrom("mfa_devices").
+		Where("id = ?", id).
+		Exec()
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return dbr.ErrNotFound
+	}
+	return nil
+}
+
+// ListAfter 按主键顺序获取afterID之后的MFA设备，用于分批重新加密密钥
+func (s *mfaDeviceStore) ListAfter(afterID, limit int) ([]*model.MFADevice, error) {
+	var devices []*model.MFADevice
+	_, err := s.session.Select(mfaDeviceColumns...).
+		From("mfa_devices d").
+		Join("users u", "u.id = d.user_id").
+		Where("d.id > ?", afterID).
+		OrderAsc("d.id").
+		Limit(uint64(limit)).
+		Load(&devices)
+	return devices, err
+}
+
+// ReplaceEncryptedSecret 在密文未被修改时替换为重新加密的密文，设备已被删除时返回false
+func (s *mfaDeviceStore) ReplaceEncryptedSecret(id int, old, new []byte) (bool, error) {
+	result, err := s.session.Update("mfa_devices").
+		Set("encrypted_secret", new).
+		Where("id = ? AND encrypted_secret = ?", id, old).
+		Exec()
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
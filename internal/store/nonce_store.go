@@ -0,0 +1,48 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+)
+
+// NonceStore 基于PostgreSQL的nonce存储，多个服务副本共享
+type NonceStore struct {
+	session *dbr.Session
+}
+
+// NewNonceStore 创建nonce存储实例
+func NewNonceStore(session *dbr.Session) *NonceStore {
+	return &NonceStore{session: session}
+}
+
+// CheckAndStore 原子地记录nonce，未过期的重复nonce返回false
+// 已过期的同名记录会被覆盖
+func (s *NonceStore) CheckAndStore(ctx context.Context, key string, expiresAt time.Time) (bool, error) {
+	result, err := s.session.InsertBySql(
+		`INSERT INTO request_nonces (nonce_key, expires_at) VALUES (?, ?)
+		ON CONFLICT (nonce_key) DO UPDATE SET expires_at = EXCLUDED.expires_at
+		WHERE request_nonces.expires_at <= NOW()`,
+		key, expiresAt,
+	).ExecContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// DeleteExpired 清理已过期的nonce
+func (s *NonceStore) DeleteExpired(now time.Time) (int64, error) {
+	result, err := s.session.DeleteFrom("request_nonces").
+		Where("expires_at <= ?", now).
+		Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
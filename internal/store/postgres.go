@@ -36,7 +36,7 @@ func NewPostgresStore(dsn string) (*PostgresStore, error) {
 	defer cancel()
 	if pingErr := db.PingContext(ctx); pingErr != nil {
 		db.Close()
-		return nil, fmt.Errorf("db ping failed: %w", err)
+		return nil, fmt.Errorf("db ping failed: %w", pingErr)
 	}
 
 	// 优化连接池配置
@@ -44,7 +44,7 @@ func NewPostgresStore(dsn string) (*PostgresStore, error) {
 	db.SetMaxIdleConns(5)                   // 小于MaxOpenConns
 	db.SetConnMaxLifetime(30 * time.Minute) // 避免云服务断开
 	db.SetConnMaxIdleTime(5 * time.Minute)  // 主动回收闲置连接
-	monitorConnection(db, 10*time.Second)
+	go monitorConnection(db, 10*time.Second)
 	// 创建dbr连接（关键修正）
 	conn, err := dbr.Open("postgres", dsn, nil)
 	if err != nil {
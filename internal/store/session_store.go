@@ -0,0 +1,77 @@
+package store
+
+import (
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/vera-byte/vgo-iam/internal/model"
+)
+
+// SessionStore 登录会话存储接口，会话以令牌的SHA-256摘要为主键
+type SessionStore interface {
+	Create(tokenHash string, session *model.LoginSession) error
+	Get(tokenHash string, now time.Time) (*model.LoginSession, error)
+	DeleteByUser(userID int) (int64, error)
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+// sessionStore 登录会话存储实现
+type sessionStore struct {
+	session *dbr.Session
+}
+
+// NewSessionStore 创建登录会话存储实例
+func NewSessionStore(session *dbr.Session) SessionStore {
+	return &sessionStore{session: session}
+}
+
+// Create 保存登录会话
+func (s *sessionStore) Create(tokenHash string, session *model.LoginSession) error {
+	_, err := s.session.InsertInto("login_sessions").
+		Columns("token_hash", "user_id", "source_ip", "mfa_present", "created_at", "expires_at").
+		Values(tokenHash, session.UserID, session.SourceIP, session.MFAPresent, session.CreatedAt, session.ExpiresAt).
+		Exec()
+	return err
+}
+
+// Get 获取未过期的登录会话，不存在或已过期时返回dbr.ErrNotFound
+func (s *sessionStore) Get(tokenHash string, now time.Time) (*model.LoginSession, error) {
+	var session model.LoginSession
+	err := s.session.Select(
+		"s.user_id",
+		"u.name AS user_name",
+		"COALESCE(s.source_ip, '') AS source_ip",
+		"s.mfa_present",
+		"s.created_at",
+		"s.expires_at",
+	).From("login_sessions s").
+		Join("users u", "u.id = s.user_id").
+		Where("s.token_hash = ? AND s.expires_at > ?", tokenHash, now).
+		LoadOne(&session)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// DeleteByUser 删除用户的所有登录会话，返回删除的数量
+func (s *sessionStore) DeleteByUser(userID int) (int64, error) {
+	result, err := s.session.DeleteFrom("login_sessions").
+		Where("user_id = ?", userID).
+		Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// DeleteExpired 清理已过期的登录会话
+func (s *sessionStore) DeleteExpired(now time.Time) (int64, error) {
+	result, err := s.session.DeleteFrom("login_sessions").
+		Where("expires_at <= ?", now).
+		Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/vera-byte/vgo-iam/internal/model"
+)
+
+// ErrSigningKeyExists 已存在相同状态的next或active签名密钥
+var ErrSigningKeyExists = errors.New("signing key with this status already exists")
+
+// SigningKeyStore 访问令牌签名密钥存储接口
+type SigningKeyStore interface {
+	List() ([]*model.SigningKey, error)
+	Create(key *model.SigningKey) error
+	Rotate(activeID, nextID int, now time.Time) (bool, error)
+	DeleteRetired(before time.Time) (int64, error)
+	ReplaceEncryptedPrivateKey(id int, old, new []byte) (bool, error)
+}
+
+// signingKeyStore 访问令牌签名密钥存储实现
+type signingKeyStore struct {
+	session *dbr.Session
+}
+
+// NewSigningKeyStore 创建访问令牌签名密钥存储实例
+func NewSigningKeyStore(session *dbr.Session) SigningKeyStore {
+	return &signingKeyStore{session: session}
+}
+
+// List 获取全部签名密钥，按创建顺序排列
+func (s *signingKeyStore) List() ([]*model.SigningKey, error) {
+	var keys []*model.SigningKey
+	_, err := s.session.Select("id", "kid", "algorithm", "public_key", "encrypted_private_key",
+		"status", "created_at", "activated_at", "retired_at").
+		From("token_signing_keys").
+		OrderAsc("id").
+		Load(&keys)
+	return keys, err
+}
+
+// Create 保存新的签名密钥，已存在相同状态的next或active密钥时返回ErrSigningKeyExists
+func (s *signingKeyStore) Create(key *model.SigningKey) error {
+	result, err := s.session.InsertBySql(
+		`INSERT INTO token_signing_keys (kid, algorithm, public_key, encrypted_private_key, status, created_at, activated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT DO NOTHING`,
+		key.KeyID, key.Algorithm, key.PublicKey, key.EncryptedPrivateKey, key.Status, key.CreatedAt, key.ActivatedAt,
+	).Exec()
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return ErrSigningKeyExists
+	}
+	return nil
+}
+
+// Rotate 在同一事务中将active密钥转为retired、next密钥转为active，activeID为0时只启用next密钥
+// 任一密钥的状态已被其他副本修改时不做任何修改并返回false
+func (s *signingKeyStore) Rotate(activeID, nextID int, now time.Time) (bool, error) {
+	tx, err := s.session.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	if activeID > 0 {
+		result, err := tx.Update("token_signing_keys").
+			Set("status", model.SigningKeyStatusRetired).
+			Set("retired_at", now).
+			Where("id = ? AND status = ?", activeID, model.SigningKeyStatusActive).
+			Exec()
+		if err != nil {
+			return false, err
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return false, nil
+		}
+	}
+	result, err := tx.Update("token_signing_keys").
+		Set("status", model.SigningKeyStatusActive).
+		Set("activated_at", now).
+		Where("id = ? AND status = ?", nextID, model.SigningKeyStatusNext).
+		Exec()
+	if err != nil {
+		return false, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return false, nil
+	}
+	return true, tx.Commit()
+}
+
+// DeleteRetired 删除before之前被轮换的密钥
+func (s *signingKeyStore) DeleteRetired(before time.Time) (int64, error) {
+	result, err := s.session.DeleteFrom("token_signing_keys").
+		Where("status = ? AND retired_at < ?", model.SigningKeyStatusRetired, before).
+		Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ReplaceEncryptedPrivateKey 在密文未被修改时替换为重新加密的密文，密钥已被删除时返回false
+func (s *signingKeyStore) ReplaceEncryptedPrivateKey(id int, old, new []byte) (bool, error) {
+	result, err := s.session.Update("token_signing_keys").
+		Set("encrypted_private_key", new).
+		Where("id = ? AND encrypted_private_key = ?", id, old).
+		Exec()
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
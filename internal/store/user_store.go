@@ -87,11 +87,11 @@ func (s *userStore) List() ([]*model.User, error) {
 	return users, err
 }
 
+// Update 更新用户基本信息，密码由LoginProfileStore维护
 func (s *userStore) Update(user *model.User) error {
 	_, err := s.session.Update("users").
 		Set("display_name", user.DisplayName).
 		Set("email", user.Email).
-		Set("password_hash", user.Password).
 		Set("updated_at", time.Now()).
 		Where("id = ?", user.ID).
 		Exec()
@@ -126,6 +126,7 @@ func (s *userStore) ListPolicies(userID int) ([]*model.Policy, error) {
 		From("policies p").
 		Join("user_policies up", "p.id = up.policy_id").
 		Where("up.user_id = ?", userID).
+		OrderBy("p.id").
 		Load(&policies)
 	return policies, err
 }
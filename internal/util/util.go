@@ -3,10 +3,13 @@ package util
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/spf13/viper"
 	"github.com/vera-byte/vgo-iam/internal/config"
@@ -46,18 +49,59 @@ func ValidateUserName(username string) bool {
 	return match
 }
 
-// ValidatePasswordStrength 验证密码强度
+// ErrWeakPassword 密码不符合密码策略
+var ErrWeakPassword = errors.New("password does not meet the password policy")
+
+// maxPasswordLength 密码最大长度(字节)，限制哈希计算的输入大小
+const maxPasswordLength = 256
+
+// DefaultPasswordPolicy 默认密码策略: 至少8个字符，包含大小写字母、数字和符号
+var DefaultPasswordPolicy = config.PasswordPolicyConfig{
+	MinLength:        8,
+	RequireUppercase: true,
+	RequireLowercase: true,
+	RequireDigits:    true,
+	RequireSymbols:   true,
+}
+
+// ValidatePasswordStrength 按默认密码策略验证密码强度
 func ValidatePasswordStrength(password string) bool {
-	if len(password) < 8 {
-		return false
-	}
+	return ValidatePassword(password, DefaultPasswordPolicy) == nil
+}
 
-	hasUpper := regexp.MustCompile(`[A-Z]`).MatchString(password)
-	hasLower := regexp.MustCompile(`[a-z]`).MatchString(password)
-	hasDigit := regexp.MustCompile(`[0-9]`).MatchString(password)
-	hasSpecial := regexp.MustCompile(`[!@#$%^&*()_+{}|:"<>?~\-=[\]\\;',./]`).MatchString(password)
+// ValidatePassword 按密码策略校验密码的长度和字符类别，不符合时返回包装了ErrWeakPassword的错误
+func ValidatePassword(password string, policy config.PasswordPolicyConfig) error {
+	if len(password) > maxPasswordLength {
+		return fmt.Errorf("%w: at most %d bytes", ErrWeakPassword, maxPasswordLength)
+	}
+	if utf8.RuneCountInString(password) < policy.MinLength {
+		return fmt.Errorf("%w: at least %d characters", ErrWeakPassword, policy.MinLength)
+	}
 
-	return hasUpper && hasLower && hasDigit && hasSpecial
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case !unicode.IsLetter(r):
+			hasSymbol = true
+		}
+	}
+	switch {
+	case policy.RequireUppercase && !hasUpper:
+		return fmt.Errorf("%w: at least one uppercase letter", ErrWeakPassword)
+	case policy.RequireLowercase && !hasLower:
+		return fmt.Errorf("%w: at least one lowercase letter", ErrWeakPassword)
+	case policy.RequireDigits && !hasDigit:
+		return fmt.Errorf("%w: at least one digit", ErrWeakPassword)
+	case policy.RequireSymbols && !hasSymbol:
+		return fmt.Errorf("%w: at least one symbol", ErrWeakPassword)
+	}
+	return nil
 }
 
 // ValidatePolicyDocument 验证策略文档格式
@@ -68,11 +112,11 @@ func ValidatePolicyDocument(policyDoc string) bool {
 		(strings.Contains(policyDoc, `"Allow"`) || strings.Contains(policyDoc, `"Deny"`))
 }
 
-// GenerateAccessKeyID 生成访问密钥ID
+// GenerateAccessKeyID 生成20个字符的访问密钥ID
 func GenerateAccessKeyID() string {
-	b := make([]byte, 10)
+	b := make([]byte, 15)
 	_, _ = rand.Read(b)
-	return base64.RawURLEncoding.EncodeToString(b)[:20]
+	return base64.RawURLEncoding.EncodeToString(b)
 }
 
 // GenerateSecretAccessKey 生成密钥
@@ -104,16 +148,60 @@ func LoadConfig(configPath string) (*config.AppConfig, error) {
 	v.SetConfigName(filepath.Base(configPath)) // 不带扩展名的文件名
 	v.AddConfigPath(filepath.Dir(configPath))  // 配置文件所在目录
 
-	// 2. 自动读取环境变量（可选）
+	// 2. 默认值
+	v.SetDefault("security.key_provider", "env")
+	v.SetDefault("security.master_key_env", "IAM_MASTER_KEY")
+	v.SetDefault("security.master_key_id", "1")
+	v.SetDefault("security.kms.token_env", "IAM_KMS_TOKEN")
+	v.SetDefault("security.kms.timeout", "10s")
+	v.SetDefault("security.nonce_store", "memory")
+	v.SetDefault("access_key.default_ttl", "2160h") // 90天
+	v.SetDefault("access_key.max_ttl", "8760h")     // 365天
+	v.SetDefault("access_key.expiry_check_interval", "1h")
+	v.SetDefault("access_key.expiry_warning_days", 7)
+	v.SetDefault("access_key.rotation_grace_period", "24h")
+	v.SetDefault("access_key.last_used_flush_interval", "10s")
+	v.SetDefault("access_key.max_keys_per_user", 2)
+	v.SetDefault("login.session_ttl", "1h")
+	v.SetDefault("login.password_policy.min_length", 8)
+	v.SetDefault("login.password_policy.require_uppercase", true)
+	v.SetDefault("login.password_policy.require_lowercase", true)
+	v.SetDefault("login.password_policy.require_digits", true)
+	v.SetDefault("login.password_policy.require_symbols", true)
+	v.SetDefault("login.password_policy.reuse_history", 5)
+	v.SetDefault("login.password_policy.max_age", "2160h") // 90天
+	v.SetDefault("login.mfa.issuer", "vgo-iam")
+	v.SetDefault("login.mfa.skew_steps", 1)
+	v.SetDefault("bootstrap.root_user", "root")
+	v.SetDefault("bootstrap.root_email", "root@localhost.localdomain")
+	v.SetDefault("auth.default_mode", "authorized")
+	v.SetDefault("auth.throttle.max_failures_per_key", 20)
+	v.SetDefault("auth.throttle.max_failures_per_ip", 100)
+	v.SetDefault("auth.throttle.window", "5m")
+	v.SetDefault("auth.lockout.max_failures_per_user", 5)
+	v.SetDefault("auth.lockout.max_failures_per_ip", 50)
+	v.SetDefault("auth.lockout.window", "15m")
+	v.SetDefault("auth.lockout.duration", "15m")
+	v.SetDefault("auth.lockout.base_delay", "1s")
+	v.SetDefault("auth.lockout.max_delay", "30s")
+	v.SetDefault("token.port", "8080")
+	v.SetDefault("token.issuer", "vgo-iam")
+	v.SetDefault("token.ttl", "15m")
+	v.SetDefault("token.key_rotation_interval", "24h")
+	v.SetDefault("ext_authz.port", "9191")
+	v.SetDefault("ext_authz.service", "http")
+	v.SetDefault("ext_authz.default_mode", "authorized")
+
+	// 3. 自动读取环境变量（可选）
 	v.AutomaticEnv()
 	v.SetEnvPrefix("IAM") // 环境变量前缀 IAM_SERVER_HOST
 
-	// 3. 读取配置文件
+	// 4. 读取配置文件
 	if err := v.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
-	// 4. 反序列化到结构体
+	// 5. 反序列化到结构体
 	var cfg config.AppConfig
 	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_access_keys_expires_at;
+ALTER TABLE access_keys DROP COLUMN IF EXISTS expires_at;
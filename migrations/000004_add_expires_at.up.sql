@@ -0,0 +1,5 @@
+-- 为访问密钥添加过期时间字段，NULL 表示永不过期
+ALTER TABLE access_keys ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+
+-- 过期检查任务按过期时间扫描
+CREATE INDEX IF NOT EXISTS idx_access_keys_expires_at ON access_keys(expires_at);
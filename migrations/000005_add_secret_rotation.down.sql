@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_access_keys_previous_secret_expires_at;
+ALTER TABLE access_keys DROP COLUMN IF EXISTS last_rotated_at;
+ALTER TABLE access_keys DROP COLUMN IF EXISTS previous_secret_expires_at;
+ALTER TABLE access_keys DROP COLUMN IF EXISTS previous_encrypted_secret_access_key;
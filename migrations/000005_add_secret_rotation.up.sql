@@ -0,0 +1,7 @@
+-- 访问密钥轮换：旧密钥在宽限期内仍可用于验签
+ALTER TABLE access_keys ADD COLUMN IF NOT EXISTS previous_encrypted_secret_access_key TEXT;
+ALTER TABLE access_keys ADD COLUMN IF NOT EXISTS previous_secret_expires_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE access_keys ADD COLUMN IF NOT EXISTS last_rotated_at TIMESTAMP WITH TIME ZONE;
+
+-- 清理任务按宽限期结束时间扫描
+CREATE INDEX IF NOT EXISTS idx_access_keys_previous_secret_expires_at ON access_keys(previous_secret_expires_at);
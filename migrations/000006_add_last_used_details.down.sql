@@ -0,0 +1,2 @@
+ALTER TABLE access_keys DROP COLUMN IF EXISTS last_used_ip;
+ALTER TABLE access_keys DROP COLUMN IF EXISTS last_used_method;
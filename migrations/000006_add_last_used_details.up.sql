@@ -0,0 +1,3 @@
+-- 记录访问密钥最后一次使用的RPC方法和来源IP
+ALTER TABLE access_keys ADD COLUMN IF NOT EXISTS last_used_method VARCHAR(255);
+ALTER TABLE access_keys ADD COLUMN IF NOT EXISTS last_used_ip VARCHAR(64);
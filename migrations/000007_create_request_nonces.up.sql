@@ -0,0 +1,7 @@
+-- 已使用的请求nonce，用于多副本部署时拒绝重放请求
+CREATE TABLE IF NOT EXISTS request_nonces (
+    nonce_key VARCHAR(255) PRIMARY KEY,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_request_nonces_expires_at ON request_nonces(expires_at);
@@ -0,0 +1 @@
+ALTER TABLE access_keys DROP COLUMN IF EXISTS secret_version;
@@ -0,0 +1,2 @@
+-- 访问密钥版本，每次轮换加1，与访问密钥ID、用户ID一起作为密文的AES-GCM附加数据
+ALTER TABLE access_keys ADD COLUMN IF NOT EXISTS secret_version INTEGER NOT NULL DEFAULT 1;
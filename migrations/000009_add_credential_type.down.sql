@@ -0,0 +1,6 @@
+DELETE FROM access_keys WHERE credential_type = 'api_key';
+ALTER TABLE access_keys DROP CONSTRAINT IF EXISTS access_keys_credential_secret_check;
+ALTER TABLE access_keys ALTER COLUMN encrypted_secret_access_key SET NOT NULL;
+ALTER TABLE access_keys DROP COLUMN IF EXISTS previous_secret_hash;
+ALTER TABLE access_keys DROP COLUMN IF EXISTS secret_hash;
+ALTER TABLE access_keys DROP COLUMN IF EXISTS credential_type;
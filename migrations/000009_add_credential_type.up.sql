@@ -0,0 +1,10 @@
+-- 凭证类型: signing 保存可解密的密文用于校验请求签名；api_key 只保存Argon2id哈希，按令牌校验
+ALTER TABLE access_keys ADD COLUMN IF NOT EXISTS credential_type VARCHAR(20) NOT NULL DEFAULT 'signing';
+ALTER TABLE access_keys ADD COLUMN IF NOT EXISTS secret_hash TEXT;
+ALTER TABLE access_keys ADD COLUMN IF NOT EXISTS previous_secret_hash TEXT;
+ALTER TABLE access_keys ALTER COLUMN encrypted_secret_access_key DROP NOT NULL;
+
+ALTER TABLE access_keys ADD CONSTRAINT access_keys_credential_secret_check CHECK (
+    (credential_type = 'signing' AND encrypted_secret_access_key IS NOT NULL AND secret_hash IS NULL) OR
+    (credential_type = 'api_key' AND secret_hash IS NOT NULL AND encrypted_secret_access_key IS NULL)
+);
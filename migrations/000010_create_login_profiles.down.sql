@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS login_sessions;
+DROP TABLE IF EXISTS password_history;
+ALTER TABLE users DROP COLUMN IF EXISTS login_profile_created_at;
+ALTER TABLE users DROP COLUMN IF EXISTS password_reset_required;
+ALTER TABLE users DROP COLUMN IF EXISTS password_changed_at;
+UPDATE users SET password_hash = NULL;
+ALTER TABLE users ALTER COLUMN password_hash TYPE VARCHAR(255);
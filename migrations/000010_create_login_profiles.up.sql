@@ -0,0 +1,29 @@
+-- 控制台登录: users.password_hash 保存Argon2id哈希，为空表示用户没有登录配置
+ALTER TABLE users ALTER COLUMN password_hash TYPE TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_reset_required BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS login_profile_created_at TIMESTAMP WITH TIME ZONE;
+-- 早期 userStore.Update 可能把明文写入了 password_hash，这些值不能作为登录配置
+UPDATE users SET password_hash = NULL WHERE password_hash IS NOT NULL AND password_hash NOT LIKE '$argon2id$%';
+
+-- 历史密码哈希，用于禁止重复使用最近的密码
+CREATE TABLE IF NOT EXISTS password_history (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    password_hash TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_history_user_id ON password_history(user_id, id);
+
+-- 登录会话，只保存会话令牌的SHA-256摘要
+CREATE TABLE IF NOT EXISTS login_sessions (
+    token_hash CHAR(64) PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source_ip VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_sessions_user_id ON login_sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_login_sessions_expires_at ON login_sessions(expires_at);
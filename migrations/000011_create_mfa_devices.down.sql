@@ -0,0 +1,2 @@
+ALTER TABLE login_sessions DROP COLUMN IF EXISTS mfa_present;
+DROP TABLE IF EXISTS mfa_devices;
@@ -0,0 +1,16 @@
+-- 虚拟MFA设备，TOTP密钥使用主密钥加密并绑定到设备序列号和用户
+CREATE TABLE IF NOT EXISTS mfa_devices (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    serial_number VARCHAR(128) NOT NULL UNIQUE,
+    encrypted_secret BYTEA NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    last_used_step BIGINT NOT NULL DEFAULT 0, -- 最后一次接受的TOTP时间步，防止验证码重放
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    enabled_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_mfa_devices_user_id ON mfa_devices(user_id);
+
+-- 会话是否通过MFA认证，对应策略条件 iam:MultiFactorAuthPresent
+ALTER TABLE login_sessions ADD COLUMN IF NOT EXISTS mfa_present BOOLEAN NOT NULL DEFAULT FALSE;
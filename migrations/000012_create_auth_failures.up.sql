@@ -0,0 +1,11 @@
+-- 认证失败计数，subject 为 user:<name>、ip:<address> 或 key:<access_key_id>
+-- 多副本部署时共享计数和锁定状态
+CREATE TABLE IF NOT EXISTS auth_failures (
+    subject VARCHAR(200) PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    window_started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    locked_until TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_failures_last_failure_at ON auth_failures(last_failure_at);
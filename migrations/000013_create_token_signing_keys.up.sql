@@ -0,0 +1,16 @@
+-- 访问令牌(JWT)的Ed25519签名密钥，私钥使用主密钥加密并绑定到kid
+-- 状态: next(已发布到JWKS，尚未用于签名) -> active(用于签名) -> retired(只用于校验，过期后删除)
+CREATE TABLE IF NOT EXISTS token_signing_keys (
+    id SERIAL PRIMARY KEY,
+    kid VARCHAR(64) NOT NULL UNIQUE,
+    algorithm VARCHAR(16) NOT NULL DEFAULT 'EdDSA',
+    public_key BYTEA NOT NULL,
+    encrypted_private_key BYTEA NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    activated_at TIMESTAMP WITH TIME ZONE,
+    retired_at TIMESTAMP WITH TIME ZONE
+);
+
+-- 任何时候最多只有一个active密钥和一个next密钥，多副本同时轮换时只有一个成功
+CREATE UNIQUE INDEX IF NOT EXISTS idx_token_signing_keys_status ON token_signing_keys(status) WHERE status <> 'retired';
@@ -0,0 +1 @@
+ALTER TABLE access_keys DROP COLUMN IF EXISTS expiry_notified_at;
@@ -0,0 +1,2 @@
+-- 即将过期告警的发送时间，每个访问密钥只告警一次，多个副本之间不重复
+ALTER TABLE access_keys ADD COLUMN IF NOT EXISTS expiry_notified_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,159 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	iamv1 "github.com/vera-byte/vgo-iam/pkg/proto"
+)
+
+const (
+	// DefaultRefreshInterval 默认的策略刷新间隔
+	DefaultRefreshInterval = 30 * time.Second
+	// DefaultMaxStaleness 默认允许使用的最旧数据，超过后拒绝所有请求
+	DefaultMaxStaleness = 5 * time.Minute
+)
+
+// ErrStale 缓存的策略超过MaxStaleness且无法从IAM刷新
+var ErrStale = errors.New("cached policies are stale")
+
+// PolicyFetcher 从IAM获取用户策略，iamv1.IAMClient实现了该接口
+type PolicyFetcher interface {
+	GetPrincipalPolicies(ctx context.Context, in *iamv1.GetPrincipalPoliciesRequest, opts ...grpc.CallOption) (*iamv1.GetPrincipalPoliciesResponse, error)
+}
+
+// Options 本地授权配置
+type Options struct {
+	RefreshInterval time.Duration // 后台刷新间隔，0使用DefaultRefreshInterval
+	MaxStaleness    time.Duration // 数据最长可信时间，0使用DefaultMaxStaleness
+}
+
+// entry 单个用户的策略缓存
+type entry struct {
+	docs       []*Document
+	etag       string
+	verifiedAt time.Time // 最近一次确认与IAM一致的时间
+}
+
+// Authorizer 从IAM下载用户策略并在本地评估权限
+// 策略通过etag轮询保持最新，数据超过MaxStaleness且无法刷新时拒绝请求
+type Authorizer struct {
+	fetcher PolicyFetcher
+	opts    Options
+	now     func() time.Time
+
+	mu      sync.RWMutex
+	entries map[string]*entry
+}
+
+// NewAuthorizer 创建本地授权器，需要另外启动Run保持数据新鲜
+func NewAuthorizer(fetcher PolicyFetcher, opts Options) *Authorizer {
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = DefaultRefreshInterval
+	}
+	if opts.MaxStaleness <= 0 {
+		opts.MaxStaleness = DefaultMaxStaleness
+	}
+	return &Authorizer{
+		fetcher: fetcher,
+		opts:    opts,
+		now:     time.Now,
+		entries: make(map[string]*entry),
+	}
+}
+
+// IsAllowed 判断用户是否可以对资源执行操作
+// 首次评估某个用户时同步下载策略；数据过旧且刷新失败时返回false和ErrStale
+func (a *Authorizer) IsAllowed(ctx context.Context, userName, action, resource string) (bool, error) {
+	return a.IsAllowedWithContext(ctx, userName, action, resource, nil)
+}
+
+// IsAllowedWithContext 与IsAllowed相同，策略中的Condition使用reqCtx匹配，
+// 如调用者通过MFA认证时传入 {ContextKeyMFAPresent: "true"}
+func (a *Authorizer) IsAllowedWithContext(ctx context.Context, userName, action, resource string, reqCtx Context) (bool, error) {
+	e := a.entry(userName)
+	if e == nil || a.now().Sub(e.verifiedAt) > a.opts.MaxStaleness {
+		var err error
+		if e, err = a.refresh(ctx, userName); err != nil {
+			if errors.Is(err, ErrStale) {
+				return false, err
+			}
+			return false, fmt.Errorf("failed to load policies for %s: %w", userName, err)
+		}
+	}
+	return EvaluateWithContext(e.docs, action, resource, reqCtx), nil
+}
+
+// Run 周期性刷新已缓存用户的策略，直到ctx取消
+func (a *Authorizer) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.opts.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.mu.RLock()
+			users := make([]string, 0, len(a.entries))
+			for userName := range a.entries {
+				users = append(users, userName)
+			}
+			a.mu.RUnlock()
+
+			// 刷新失败时保留旧数据，超过MaxStaleness后IsAllowed会拒绝
+			for _, userName := range users {
+				_, _ = a.refresh(ctx, userName)
+			}
+		}
+	}
+}
+
+// entry 返回用户的缓存
+func (a *Authorizer) entry(userName string) *entry {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.entries[userName]
+}
+
+// refresh 携带etag从IAM获取策略，策略未变化时只更新确认时间
+// 获取失败时，如果已有缓存且超过MaxStaleness则返回ErrStale
+func (a *Authorizer) refresh(ctx context.Context, userName string) (*entry, error) {
+	current := a.entry(userName)
+	req := &iamv1.GetPrincipalPoliciesRequest{UserName: userName}
+	if current != nil {
+		req.Etag = current.etag
+	}
+
+	resp, err := a.fetcher.GetPrincipalPolicies(ctx, req)
+	if err != nil {
+		if current != nil && a.now().Sub(current.verifiedAt) > a.opts.MaxStaleness {
+			return nil, fmt.Errorf("%w: %v", ErrStale, err)
+		}
+		return nil, err
+	}
+
+	next := &entry{etag: resp.Etag, verifiedAt: a.now()}
+	if resp.NotModified && current != nil {
+		next.docs = current.docs
+	} else {
+		next.docs = make([]*Document, 0, len(resp.Policies))
+		for _, p := range resp.Policies {
+			doc, err := ParseDocument(p.PolicyDocument)
+			if err != nil {
+				return nil, fmt.Errorf("policy %s: %w", p.Name, err)
+			}
+			next.docs = append(next.docs, doc)
+		}
+	}
+
+	a.mu.Lock()
+	a.entries[userName] = next
+	a.mu.Unlock()
+	return next, nil
+}
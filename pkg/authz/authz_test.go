@@ -0,0 +1,133 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	iamv1 "github.com/vera-byte/vgo-iam/pkg/proto"
+)
+
+func TestEvaluate(t *testing.T) {
+	deny, _ := ParseDocument(`{"Statement":[{"Effect":"Deny","Action":["iam:DeleteAccessKey"],"Resource":["*"]}]}`)
+	allow, _ := ParseDocument(`{"Statement":[{"Effect":"Allow","Action":["iam:*"],"Resource":["iam:user:*"]}]}`)
+	docs := []*Document{deny, allow}
+
+	tests := []struct {
+		action, resource string
+		want             bool
+	}{
+		{"iam:GetUser", "iam:user:alice", true},
+		{"iam:DeleteAccessKey", "iam:user:alice", false},
+		{"iam:GetUser", "iam:policy:admin", false},
+		{"orders:List", "iam:user:alice", false},
+	}
+	for _, tt := range tests {
+		if got := Evaluate(docs, tt.action, tt.resource); got != tt.want {
+			t.Errorf("Evaluate(%s, %s) = %v, want %v", tt.action, tt.resource, got, tt.want)
+		}
+	}
+
+	if _, err := ParseDocument("not json"); !errors.Is(err, ErrInvalidDocument) {
+		t.Errorf("expected ErrInvalidDocument, got %v", err)
+	}
+}
+
+func TestEvaluateCondition(t *testing.T) {
+	requireMFA, err := ParseDocument(`{"Statement":[
+		{"Effect":"Deny","Action":["iam:DeleteAccessKey"],"Resource":["*"],"Condition":{"BoolIfExists":{"iam:MultiFactorAuthPresent":false}}},
+		{"Effect":"Allow","Action":["iam:*"],"Resource":["*"],"Condition":{"Bool":{"iam:MultiFactorAuthPresent":"true"}}},
+		{"Effect":"Allow","Action":["iam:GetUser"],"Resource":["*"]}
+	]}`)
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+	docs := []*Document{requireMFA}
+	withMFA := Context{ContextKeyMFAPresent: "true"}
+	withoutMFA := Context{ContextKeyMFAPresent: "false"}
+
+	tests := []struct {
+		action string
+		reqCtx Context
+		want   bool
+	}{
+		{"iam:DeleteAccessKey", withMFA, true},
+		{"iam:DeleteAccessKey", withoutMFA, false},
+		{"iam:DeleteAccessKey", nil, false},
+		{"iam:CreateUser", withoutMFA, false},
+		{"iam:GetUser", withoutMFA, true},
+	}
+	for _, tt := range tests {
+		if got := EvaluateWithContext(docs, tt.action, "iam:user:alice", tt.reqCtx); got != tt.want {
+			t.Errorf("EvaluateWithContext(%s, %v) = %v, want %v", tt.action, tt.reqCtx, got, tt.want)
+		}
+	}
+
+	if _, err := ParseDocument(`{"Statement":[{"Effect":"Deny","Action":["*"],"Resource":["*"],"Condition":{"IpAddress":{"aws:SourceIp":"10.0.0.0/8"}}}]}`); !errors.Is(err, ErrInvalidDocument) {
+		t.Errorf("expected ErrInvalidDocument for unsupported operator, got %v", err)
+	}
+}
+
+// fakeFetcher 模拟IAM的GetPrincipalPolicies
+type fakeFetcher struct {
+	etag     string
+	document string
+	calls    int
+	err      error
+}
+
+func (f *fakeFetcher) GetPrincipalPolicies(ctx context.Context, in *iamv1.GetPrincipalPoliciesRequest, opts ...grpc.CallOption) (*iamv1.GetPrincipalPoliciesResponse, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	if in.Etag == f.etag {
+		return &iamv1.GetPrincipalPoliciesResponse{Etag: f.etag, NotModified: true}, nil
+	}
+	return &iamv1.GetPrincipalPoliciesResponse{
+		Etag:     f.etag,
+		Policies: []*iamv1.PrincipalPolicy{{Name: "p", PolicyDocument: f.document}},
+	}, nil
+}
+
+func TestAuthorizerFailsClosedWhenStale(t *testing.T) {
+	fetcher := &fakeFetcher{
+		etag:     "v1",
+		document: `{"Statement":[{"Effect":"Allow","Action":["orders:List"],"Resource":["*"]}]}`,
+	}
+	a := NewAuthorizer(fetcher, Options{MaxStaleness: time.Minute})
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// 首次评估同步下载策略，之后使用缓存
+	for i := 0; i < 2; i++ {
+		allowed, err := a.IsAllowed(ctx, "alice", "orders:List", "orders:order:1")
+		if err != nil || !allowed {
+			t.Fatalf("expected allowed, got %v, %v", allowed, err)
+		}
+	}
+	if fetcher.calls != 1 {
+		t.Errorf("expected 1 fetch, got %d", fetcher.calls)
+	}
+
+	// 数据过旧时刷新，etag未变化
+	now = now.Add(2 * time.Minute)
+	if allowed, err := a.IsAllowed(ctx, "alice", "orders:List", "orders:order:1"); err != nil || !allowed {
+		t.Fatalf("expected allowed after not_modified refresh, got %v, %v", allowed, err)
+	}
+	if fetcher.calls != 2 {
+		t.Errorf("expected 2 fetches, got %d", fetcher.calls)
+	}
+
+	// IAM不可用且数据过旧时拒绝
+	fetcher.err = errors.New("unavailable")
+	now = now.Add(2 * time.Minute)
+	allowed, err := a.IsAllowed(ctx, "alice", "orders:List", "orders:order:1")
+	if allowed || !errors.Is(err, ErrStale) {
+		t.Errorf("expected ErrStale, got %v, %v", allowed, err)
+	}
+}
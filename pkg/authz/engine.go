@@ -0,0 +1,224 @@
+// Package authz 策略评估库
+// IAM服务端与下游服务使用同一套评估逻辑，下游服务可通过Authorizer在本地评估权限
+package authz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidDocument 策略文档格式错误
+var ErrInvalidDocument = errors.New("invalid policy document format")
+
+// ContextKeyMFAPresent 调用者本次认证是否通过了MFA，值为"true"/"false"
+const ContextKeyMFAPresent = "iam:MultiFactorAuthPresent"
+
+// ifExistsSuffix 条件运算符后缀，上下文中没有该键时条件视为满足
+const ifExistsSuffix = "IfExists"
+
+// Context 请求上下文，语句的Condition按键匹配其中的值
+type Context map[string]string
+
+// Statement 策略语句
+type Statement struct {
+	Effect    string    `json:"effect"`              // Allow/Deny
+	Action    []string  `json:"action"`              // 操作列表
+	Resource  []string  `json:"resource"`            // 资源列表
+	Condition Condition `json:"condition,omitempty"` // 条件，全部满足时语句才匹配
+}
+
+// Condition 条件运算符 -> 上下文键 -> 允许的值，如 {"Bool": {"iam:MultiFactorAuthPresent": "true"}}
+// 支持Bool、StringEquals、StringNotEquals，运算符加IfExists后缀时上下文中没有该键也视为满足
+type Condition map[string]map[string]ConditionValues
+
+// ConditionValues 条件值，策略中可以写单个字符串、布尔值或数组
+type ConditionValues []string
+
+// UnmarshalJSON 将单个值或数组统一解析为字符串列表
+func (v *ConditionValues) UnmarshalJSON(data []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	items, ok := raw.([]interface{})
+	if !ok {
+		items = []interface{}{raw}
+	}
+	values := make(ConditionValues, 0, len(items))
+	for _, item := range items {
+		switch value := item.(type) {
+		case string:
+			values = append(values, value)
+		case bool:
+			values = append(values, strconv.FormatBool(value))
+		default:
+			return fmt.Errorf("unsupported condition value %v", item)
+		}
+	}
+	*v = values
+	return nil
+}
+
+// Document 策略文档
+type Document struct {
+	Version   string      `json:"version"`
+	Statement []Statement `json:"statement"`
+}
+
+// ParseDocument 解析JSON格式的策略文档
+func ParseDocument(doc string) (*Document, error) {
+	var d Document
+	if err := json.Unmarshal([]byte(doc), &d); err != nil {
+		return nil, ErrInvalidDocument
+	}
+	// 不支持的条件运算符会让Deny语句失效，解析时直接拒绝
+	for _, statement := range d.Statement {
+		for operator := range statement.Condition {
+			if _, ok := conditionOperators[strings.TrimSuffix(operator, ifExistsSuffix)]; !ok {
+				return nil, fmt.Errorf("%w: unsupported condition operator %s", ErrInvalidDocument, operator)
+			}
+		}
+	}
+	return &d, nil
+}
+
+// Match 按顺序检查语句，返回第一个同时匹配操作和资源的语句是否允许
+// 没有语句匹配时matched为false；带Condition的语句在没有上下文时按上下文为空匹配
+func (d *Document) Match(action, resource string) (allowed, matched bool) {
+	return d.MatchWithContext(action, resource, nil)
+}
+
+// MatchWithContext 与Match相同，带Condition的语句只在请求上下文满足条件时匹配
+func (d *Document) MatchWithContext(action, resource string, reqCtx Context) (allowed, matched bool) {
+	for _, statement := range d.Statement {
+		if !MatchResource(statement.Resource, resource) {
+			continue
+		}
+		if !MatchAction(statement.Action, action) {
+			continue
+		}
+		if !statement.Condition.Match(reqCtx) {
+			continue
+		}
+		return statement.Effect == "Allow", true
+	}
+	return false, false
+}
+
+// Evaluate 按顺序评估策略，第一个匹配的语句决定结果，没有匹配时拒绝
+func Evaluate(docs []*Document, action, resource string) bool {
+	return EvaluateWithContext(docs, action, resource, nil)
+}
+
+// EvaluateWithContext 按顺序评估策略，语句的Condition使用reqCtx匹配
+func EvaluateWithContext(docs []*Document, action, resource string, reqCtx Context) bool {
+	for _, doc := range docs {
+		if allowed, matched := doc.MatchWithContext(action, resource, reqCtx); matched {
+			return allowed
+		}
+	}
+	return false
+}
+
+// conditionOperators 支持的条件运算符，参数为上下文中的值和条件允许的值
+var conditionOperators = map[string]func(actual string, values ConditionValues) bool{
+	"Bool": func(actual string, values ConditionValues) bool {
+		for _, v := range values {
+			if strings.EqualFold(v, actual) {
+				return true
+			}
+		}
+		return false
+	},
+	"StringEquals": func(actual string, values ConditionValues) bool {
+		for _, v := range values {
+			if v == actual {
+				return true
+			}
+		}
+		return false
+	},
+	"StringNotEquals": func(actual string, values ConditionValues) bool {
+		for _, v := range values {
+			if v == actual {
+				return false
+			}
+		}
+		return true
+	},
+}
+
+// Match 检查请求上下文是否满足全部条件，没有条件时总是满足
+// 上下文中没有条件引用的键时，只有带IfExists后缀的条件满足
+func (c Condition) Match(reqCtx Context) bool {
+	for operator, keys := range c {
+		name := strings.TrimSuffix(operator, ifExistsSuffix)
+		ifExists := name != operator
+		match, ok := conditionOperators[name]
+		if !ok {
+			return false
+		}
+		for key, values := range keys {
+			actual, present := reqCtx[key]
+			if !present {
+				if ifExists {
+					continue
+				}
+				return false
+			}
+			if !match(actual, values) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// MatchAction 检查请求的操作是否匹配策略中的操作模式
+// 支持 "*" 和 "service:*" 通配
+func MatchAction(patterns []string, action string) bool {
+	for _, pattern := range patterns {
+		if pattern == "*" || pattern == action {
+			return true
+		}
+		if strings.HasSuffix(pattern, ":*") {
+			servicePrefix := strings.TrimSuffix(pattern, ":*")
+			if strings.HasPrefix(action, servicePrefix+":") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MatchResource 检查请求的资源是否匹配策略中的资源模式
+// 支持 "*" 和按 ":" 分段的通配，如 "iam:user:*"
+func MatchResource(patterns []string, resource string) bool {
+	for _, pattern := range patterns {
+		if pattern == "*" || pattern == resource {
+			return true
+		}
+		if matchARNPattern(pattern, resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchARNPattern 实现ARN格式的通配符匹配，段数必须一致
+func matchARNPattern(pattern, arn string) bool {
+	patternParts := strings.Split(pattern, ":")
+	arnParts := strings.Split(arn, ":")
+	if len(patternParts) != len(arnParts) {
+		return false
+	}
+	for i := range patternParts {
+		if patternParts[i] != "*" && patternParts[i] != arnParts[i] {
+			return false
+		}
+	}
+	return true
+}
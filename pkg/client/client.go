@@ -0,0 +1,218 @@
+// Package client IAM服务的Go客户端，自动为每个调用签名
+package client
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+
+	iamv1 "github.com/vera-byte/vgo-iam/pkg/proto"
+)
+
+// Client IAM客户端
+type Client struct {
+	conn *grpc.ClientConn
+	iam  iamv1.IAMClient
+}
+
+// New 创建IAM客户端，provider为nil时使用默认凭证链
+// opts中需要包含传输凭证，如 grpc.WithTransportCredentials(...)
+func New(target string, provider Provider, opts ...grpc.DialOption) (*Client, error) {
+	if provider == nil {
+		provider = NewDefaultProvider()
+	}
+	opts = append(opts,
+		grpc.WithChainUnaryInterceptor(UnaryClientInterceptor(provider)),
+		grpc.WithChainStreamInterceptor(StreamClientInterceptor(provider)),
+	)
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, iam: iamv1.NewIAMClient(conn)}, nil
+}
+
+// Close 关闭连接
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// IAM 返回底层的gRPC客户端，用于调用没有封装的方法
+func (c *Client) IAM() iamv1.IAMClient {
+	return c.iam
+}
+
+// CreateUser 创建用户
+func (c *Client) CreateUser(ctx context.Context, name, displayName, email string) (*iamv1.User, error) {
+	return c.iam.CreateUser(ctx, &iamv1.CreateUserRequest{Name: name, DisplayName: displayName, Email: email})
+}
+
+// GetUser 获取用户
+func (c *Client) GetUser(ctx context.Context, name string) (*iamv1.User, error) {
+	return c.iam.GetUser(ctx, &iamv1.GetUserRequest{Name: name})
+}
+
+// CreateLoginProfile 为用户创建控制台登录密码，resetRequired为true时首次登录必须修改密码
+func (c *Client) CreateLoginProfile(ctx context.Context, userName, password string, resetRequired bool) (*iamv1.LoginProfile, error) {
+	return c.iam.CreateLoginProfile(ctx, &iamv1.CreateLoginProfileRequest{UserName: userName, Password: password, PasswordResetRequired: resetRequired})
+}
+
+// UpdateLoginProfile 修改用户的登录密码，password为空时只修改resetRequired
+func (c *Client) UpdateLoginProfile(ctx context.Context, userName, password string, resetRequired bool) (*iamv1.LoginProfile, error) {
+	return c.iam.UpdateLoginProfile(ctx, &iamv1.UpdateLoginProfileRequest{UserName: userName, Password: password, PasswordResetRequired: resetRequired})
+}
+
+// DeleteLoginProfile 删除用户的登录配置，用户的会话立即失效
+func (c *Client) DeleteLoginProfile(ctx context.Context, userName string) error {
+	_, err := c.iam.DeleteLoginProfile(ctx, &iamv1.DeleteLoginProfileRequest{UserName: userName})
+	return err
+}
+
+// UnlockUser 解除用户及其API密钥因认证失败过多造成的锁定
+func (c *Client) UnlockUser(ctx context.Context, userName string) error {
+	_, err := c.iam.UnlockUser(ctx, &iamv1.UnlockUserRequest{UserName: userName})
+	return err
+}
+
+// Authenticate 使用用户名和密码登录，返回会话令牌，调用时通过 authorization: IAM-Session <token> 传递
+// 密码已过期或被要求重置时须提供newPassword，用户已启用MFA设备时须提供mfaCode
+func (c *Client) Authenticate(ctx context.Context, userName, password, newPassword, mfaCode string) (*iamv1.AuthenticateResponse, error) {
+	return c.iam.Authenticate(ctx, &iamv1.AuthenticateRequest{UserName: userName, Password: password, NewPassword: newPassword, MfaCode: mfaCode})
+}
+
+// GetSessionToken 使用访问密钥换取会话令牌，mfaCode非空时会话满足 iam:MultiFactorAuthPresent
+func (c *Client) GetSessionToken(ctx context.Context, mfaCode string) (*iamv1.GetSessionTokenResponse, error) {
+	return c.iam.GetSessionToken(ctx, &iamv1.GetSessionTokenRequest{MfaCode: mfaCode})
+}
+
+// CreateVirtualMFADevice 为用户创建虚拟MFA设备，返回的密钥和otpauth URI只返回一次
+func (c *Client) CreateVirtualMFADevice(ctx context.Context, userName, deviceName string) (*iamv1.MFADevice, error) {
+	return c.iam.CreateVirtualMFADevice(ctx, &iamv1.CreateVirtualMFADeviceRequest{UserName: userName, DeviceName: deviceName})
+}
+
+// EnableMFADevice 使用两个连续的验证码启用MFA设备
+func (c *Client) EnableMFADevice(ctx context.Context, userName, serialNumber, code1, code2 string) (*iamv1.MFADevice, error) {
+	return c.iam.EnableMFADevice(ctx, &iamv1.EnableMFADeviceRequest{
+		UserName:             userName,
+		SerialNumber:         serialNumber,
+		AuthenticationCode_1: code1,
+		AuthenticationCode_2: code2,
+	})
+}
+
+// DeactivateMFADevice 停用并删除MFA设备
+func (c *Client) DeactivateMFADevice(ctx context.Context, userName, serialNumber string) error {
+	_, err := c.iam.DeactivateMFADevice(ctx, &iamv1.DeactivateMFADeviceRequest{UserName: userName, SerialNumber: serialNumber})
+	return err
+}
+
+// ListMFADevices 列出用户的MFA设备
+func (c *Client) ListMFADevices(ctx context.Context, userName string) ([]*iamv1.MFADevice, error) {
+	resp, err := c.iam.ListMFADevices(ctx, &iamv1.ListMFADevicesRequest{UserName: userName})
+	if err != nil {
+		return nil, err
+	}
+	return resp.MfaDevices, nil
+}
+
+// CreatePolicy 创建策略
+func (c *Client) CreatePolicy(ctx context.Context, name, description, policyDocument string) (*iamv1.Policy, error) {
+	return c.iam.CreatePolicy(ctx, &iamv1.CreatePolicyRequest{Name: name, Description: description, PolicyDocument: policyDocument})
+}
+
+// AttachUserPolicy 为用户附加策略
+func (c *Client) AttachUserPolicy(ctx context.Context, userName, policyName string) error {
+	_, err := c.iam.AttachUserPolicy(ctx, &iamv1.AttachUserPolicyRequest{UserName: userName, PolicyName: policyName})
+	return err
+}
+
+// CreateAccessKey 创建访问密钥，ttl为0时使用服务端默认有效期
+// 返回的SecretAccessKey只在创建时返回
+func (c *Client) CreateAccessKey(ctx context.Context, userName string, ttl time.Duration) (*iamv1.AccessKey, error) {
+	return c.iam.CreateAccessKey(ctx, &iamv1.CreateAccessKeyRequest{UserName: userName, TtlSeconds: int64(ttl / time.Second)})
+}
+
+// CreateAPIKey 创建api_key类型的访问密钥，服务端只保存密钥哈希，ttl为0时使用服务端默认有效期
+// 令牌为 <AccessKeyId>.<SecretAccessKey>，只能通过VerifyApiKey校验，不能用于请求签名
+func (c *Client) CreateAPIKey(ctx context.Context, userName string, ttl time.Duration) (*iamv1.AccessKey, error) {
+	return c.iam.CreateAccessKey(ctx, &iamv1.CreateAccessKeyRequest{
+		UserName:       userName,
+		TtlSeconds:     int64(ttl / time.Second),
+		CredentialType: "api_key",
+	})
+}
+
+// ListAccessKeys 列出用户的访问密钥
+func (c *Client) ListAccessKeys(ctx context.Context, userName string) ([]*iamv1.AccessKey, error) {
+	resp, err := c.iam.ListAccessKeys(ctx, &iamv1.ListAccessKeysRequest{UserName: userName})
+	if err != nil {
+		return nil, err
+	}
+	return resp.AccessKeys, nil
+}
+
+// UpdateAccessKeyStatus 更新访问密钥状态: active/inactive
+func (c *Client) UpdateAccessKeyStatus(ctx context.Context, accessKeyID, status string) (*iamv1.AccessKey, error) {
+	return c.iam.UpdateAccessKeyStatus(ctx, &iamv1.UpdateAccessKeyStatusRequest{AccessKeyId: accessKeyID, Status: status})
+}
+
+// RotateAccessKey 轮换访问密钥，旧密钥在服务端默认宽限期内仍然有效
+func (c *Client) RotateAccessKey(ctx context.Context, accessKeyID string) (*iamv1.AccessKey, error) {
+	return c.iam.RotateAccessKey(ctx, &iamv1.RotateAccessKeyRequest{AccessKeyId: accessKeyID})
+}
+
+// RotateAccessKeyWithGrace 轮换访问密钥并指定旧密钥的宽限期，grace为0时旧密钥立即失效
+func (c *Client) RotateAccessKeyWithGrace(ctx context.Context, accessKeyID string, grace time.Duration) (*iamv1.AccessKey, error) {
+	seconds := int64(grace / time.Second)
+	return c.iam.RotateAccessKey(ctx, &iamv1.RotateAccessKeyRequest{AccessKeyId: accessKeyID, GracePeriodSeconds: &seconds})
+}
+
+// DeleteAccessKey 删除访问密钥，force为true时允许删除仍处于激活状态的密钥
+func (c *Client) DeleteAccessKey(ctx context.Context, accessKeyID string, force bool) error {
+	_, err := c.iam.DeleteAccessKey(ctx, &iamv1.DeleteAccessKeyRequest{AccessKeyId: accessKeyID, Force: force})
+	return err
+}
+
+// GetAccessKeyLastUsed 获取访问密钥最后使用信息
+func (c *Client) GetAccessKeyLastUsed(ctx context.Context, accessKeyID string) (*iamv1.GetAccessKeyLastUsedResponse, error) {
+	return c.iam.GetAccessKeyLastUsed(ctx, &iamv1.GetAccessKeyLastUsedRequest{AccessKeyId: accessKeyID})
+}
+
+// VerifyAccessKey 校验下游服务收到的请求签名
+func (c *Client) VerifyAccessKey(ctx context.Context, req *iamv1.VerifyRequest) (*iamv1.VerifyResponse, error) {
+	return c.iam.VerifyAccessKey(ctx, req)
+}
+
+// VerifyApiKey 校验下游服务收到的API密钥令牌，返回所属用户名
+func (c *Client) VerifyApiKey(ctx context.Context, apiKey string) (*iamv1.VerifyApiKeyResponse, error) {
+	return c.iam.VerifyApiKey(ctx, &iamv1.VerifyApiKeyRequest{ApiKey: apiKey})
+}
+
+// ValidateToken 校验令牌端点签发的访问令牌(不含Bearer前缀)，返回令牌中的身份和scope
+func (c *Client) ValidateToken(ctx context.Context, token string) (*iamv1.ValidateTokenResponse, error) {
+	return c.iam.ValidateToken(ctx, &iamv1.ValidateTokenRequest{Token: token})
+}
+
+// GetJWKS 获取校验访问令牌的公钥，按令牌头部的kid选择公钥在本地校验签名
+func (c *Client) GetJWKS(ctx context.Context) ([]*iamv1.JSONWebKey, error) {
+	resp, err := c.iam.GetJWKS(ctx, &iamv1.GetJWKSRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Keys, nil
+}
+
+// CheckPermission 检查用户是否有权限对资源执行操作
+func (c *Client) CheckPermission(ctx context.Context, userName, action, resource string) (bool, error) {
+	resp, err := c.iam.CheckPermission(ctx, &iamv1.CheckPermissionRequest{UserName: userName, Action: action, Resource: resource})
+	if err != nil {
+		return false, err
+	}
+	return resp.Allowed, nil
+}
+
+// GetPrincipalPolicies 获取用户的全部策略，etag与服务端一致时只返回not_modified
+func (c *Client) GetPrincipalPolicies(ctx context.Context, userName, etag string) (*iamv1.GetPrincipalPoliciesResponse, error) {
+	return c.iam.GetPrincipalPolicies(ctx, &iamv1.GetPrincipalPoliciesRequest{UserName: userName, Etag: etag})
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	iamv1 "github.com/vera-byte/vgo-iam/pkg/proto"
+	"github.com/vera-byte/vgo-iam/pkg/signer"
+)
+
+func TestProviderChain(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "credentials")
+	content := "[default]\niam_access_key_id = AKIDFILE\niam_secret_access_key = file-secret\n\n" +
+		"[ci]\niam_access_key_id = AKIDCI\niam_secret_access_key = ci-secret\n"
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(EnvAccessKeyID, "")
+	t.Setenv(EnvSecretAccessKey, "")
+	chain := NewChainProvider(EnvProvider{}, &SharedCredentialsProvider{Filename: file, Profile: "ci"})
+	c, err := chain.Retrieve()
+	if err != nil || c.AccessKeyID != "AKIDCI" {
+		t.Fatalf("expected profile ci from file, got %+v, %v", c, err)
+	}
+
+	t.Setenv(EnvAccessKeyID, "AKIDENV")
+	t.Setenv(EnvSecretAccessKey, "env-secret")
+	c, err = chain.Retrieve()
+	if err != nil || c.AccessKeyID != "AKIDENV" {
+		t.Fatalf("expected env credentials first, got %+v, %v", c, err)
+	}
+
+	missing := NewChainProvider(&SharedCredentialsProvider{Filename: filepath.Join(t.TempDir(), "none")}, NewStaticProvider("", ""))
+	if _, err := missing.Retrieve(); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("expected ErrNoCredentials, got %v", err)
+	}
+}
+
+func TestUnaryClientInterceptorSignsRequest(t *testing.T) {
+	const secret = "test-secret"
+	interceptor := UnaryClientInterceptor(NewStaticProvider("AKIDTEST", secret))
+	req := &iamv1.GetUserRequest{Name: "alice"}
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		a, err := signer.ParseAuthorization(md.Get(signer.HeaderAuthorization)[0])
+		if err != nil {
+			t.Fatalf("ParseAuthorization failed: %v", err)
+		}
+		if a.AccessKeyID != "AKIDTEST" || !a.HasSignedHeader(signer.HeaderNonce) {
+			t.Errorf("unexpected authorization: %+v", a)
+		}
+		canonical, err := signer.GRPCCanonicalRequest(method, md, a.SignedHeaders, req)
+		if err != nil {
+			t.Fatalf("GRPCCanonicalRequest failed: %v", err)
+		}
+		if !signer.VerifySignature(a, md.Get(signer.HeaderDate)[0], canonical.String(), []string{secret}) {
+			t.Error("signature verification failed")
+		}
+		return nil
+	}
+
+	if err := interceptor(context.Background(), iamv1.IAM_GetUser_FullMethodName, req, &iamv1.User{}, nil, invoker); err != nil {
+		t.Fatalf("interceptor failed: %v", err)
+	}
+}
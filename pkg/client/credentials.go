@@ -0,0 +1,210 @@
+package client
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	// EnvAccessKeyID 访问密钥ID环境变量
+	EnvAccessKeyID = "IAM_ACCESS_KEY_ID"
+	// EnvSecretAccessKey 访问密钥环境变量
+	EnvSecretAccessKey = "IAM_SECRET_ACCESS_KEY"
+	// EnvSharedCredentialsFile 共享凭证文件路径环境变量
+	EnvSharedCredentialsFile = "IAM_SHARED_CREDENTIALS_FILE"
+	// EnvProfile 共享凭证文件中使用的profile环境变量
+	EnvProfile = "IAM_PROFILE"
+
+	// DefaultProfile 默认profile
+	DefaultProfile = "default"
+
+	keyAccessKeyID     = "iam_access_key_id"
+	keySecretAccessKey = "iam_secret_access_key"
+)
+
+// ErrNoCredentials 没有可用的凭证
+var ErrNoCredentials = errors.New("no valid credentials found")
+
+// Credentials 访问密钥凭证
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// valid 凭证是否完整
+func (c Credentials) valid() bool {
+	return c.AccessKeyID != "" && c.SecretAccessKey != ""
+}
+
+// Provider 凭证提供者
+type Provider interface {
+	Retrieve() (Credentials, error)
+}
+
+// StaticProvider 固定凭证
+type StaticProvider struct {
+	Credentials
+}
+
+// NewStaticProvider 创建固定凭证提供者
+func NewStaticProvider(accessKeyID, secretAccessKey string) *StaticProvider {
+	return &StaticProvider{Credentials{AccessKeyID: accessKeyID, SecretAccessKey: secretAccessKey}}
+}
+
+// Retrieve 返回固定凭证
+func (p *StaticProvider) Retrieve() (Credentials, error) {
+	if !p.valid() {
+		return Credentials{}, ErrNoCredentials
+	}
+	return p.Credentials, nil
+}
+
+// EnvProvider 从环境变量IAM_ACCESS_KEY_ID、IAM_SECRET_ACCESS_KEY读取凭证
+type EnvProvider struct{}
+
+// Retrieve 读取环境变量中的凭证
+func (EnvProvider) Retrieve() (Credentials, error) {
+	c := Credentials{
+		AccessKeyID:     os.Getenv(EnvAccessKeyID),
+		SecretAccessKey: os.Getenv(EnvSecretAccessKey),
+	}
+	if !c.valid() {
+		return Credentials{}, ErrNoCredentials
+	}
+	return c, nil
+}
+
+// SharedCredentialsProvider 从共享凭证文件读取凭证，文件只读取一次
+// 文件格式与服务端首次启动写入的根凭证文件一致：
+//
+//	[default]
+//	iam_access_key_id = AKID...
+//	iam_secret_access_key = ...
+type SharedCredentialsProvider struct {
+	// Filename 为空时依次使用IAM_SHARED_CREDENTIALS_FILE和~/.vgo-iam/credentials
+	Filename string
+	// Profile 为空时依次使用IAM_PROFILE和default
+	Profile string
+
+	once  sync.Once
+	creds Credentials
+	err   error
+}
+
+// Retrieve 读取共享凭证文件中指定profile的凭证
+func (p *SharedCredentialsProvider) Retrieve() (Credentials, error) {
+	p.once.Do(func() {
+		p.creds, p.err = loadSharedCredentials(p.filename(), p.profile())
+	})
+	return p.creds, p.err
+}
+
+func (p *SharedCredentialsProvider) filename() string {
+	if p.Filename != "" {
+		return p.Filename
+	}
+	if f := os.Getenv(EnvSharedCredentialsFile); f != "" {
+		return f
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".vgo-iam", "credentials")
+}
+
+func (p *SharedCredentialsProvider) profile() string {
+	if p.Profile != "" {
+		return p.Profile
+	}
+	if profile := os.Getenv(EnvProfile); profile != "" {
+		return profile
+	}
+	return DefaultProfile
+}
+
+// loadSharedCredentials 解析INI格式的共享凭证文件
+func loadSharedCredentials(filename, profile string) (Credentials, error) {
+	if filename == "" {
+		return Credentials{}, ErrNoCredentials
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Credentials{}, ErrNoCredentials
+		}
+		return Credentials{}, fmt.Errorf("failed to open shared credentials file: %w", err)
+	}
+	defer f.Close()
+
+	var (
+		c       Credentials
+		section string
+	)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		if section != profile {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case keyAccessKeyID:
+			c.AccessKeyID = strings.TrimSpace(value)
+		case keySecretAccessKey:
+			c.SecretAccessKey = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Credentials{}, fmt.Errorf("failed to read shared credentials file: %w", err)
+	}
+	if !c.valid() {
+		return Credentials{}, fmt.Errorf("profile %q in %s: %w", profile, filename, ErrNoCredentials)
+	}
+	return c, nil
+}
+
+// ChainProvider 依次尝试多个提供者，返回第一个可用的凭证
+type ChainProvider struct {
+	Providers []Provider
+}
+
+// NewChainProvider 创建凭证提供者链
+func NewChainProvider(providers ...Provider) *ChainProvider {
+	return &ChainProvider{Providers: providers}
+}
+
+// NewDefaultProvider 默认凭证链：环境变量、共享凭证文件
+func NewDefaultProvider() *ChainProvider {
+	return NewChainProvider(EnvProvider{}, &SharedCredentialsProvider{})
+}
+
+// Retrieve 返回第一个可用的凭证，全部不可用时返回ErrNoCredentials
+// 提供者返回ErrNoCredentials以外的错误时（如文件格式错误）直接返回该错误
+func (p *ChainProvider) Retrieve() (Credentials, error) {
+	for _, provider := range p.Providers {
+		c, err := provider.Retrieve()
+		if err == nil {
+			return c, nil
+		}
+		if !errors.Is(err, ErrNoCredentials) {
+			return Credentials{}, err
+		}
+	}
+	return Credentials{}, ErrNoCredentials
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+
+	"github.com/vera-byte/vgo-iam/pkg/signer"
+)
+
+// 签名需要覆盖请求消息，grpc.PerRPCCredentials拿不到请求消息，因此使用客户端拦截器签名
+
+// UnaryClientInterceptor 返回为每个一元调用签名的客户端拦截器
+// 每次调用都会携带随机的x-iam-nonce，服务端据此拒绝重放请求
+func UnaryClientInterceptor(provider Provider) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, err := signContext(ctx, provider, method, req)
+		if err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor 返回为流式调用签名的客户端拦截器
+// 流在建立时签名，规范请求的请求体为空
+func StreamClientInterceptor(provider Provider) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, err := signContext(ctx, provider, method, nil)
+		if err != nil {
+			return nil, err
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// signContext 获取凭证并为调用签名
+func signContext(ctx context.Context, provider Provider, method string, req interface{}) (context.Context, error) {
+	creds, err := provider.Retrieve()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve credentials: %w", err)
+	}
+	return signer.SignGRPCRequest(ctx, method, req, creds.AccessKeyID, creds.SecretAccessKey, time.Now(),
+		map[string]string{signer.HeaderNonce: uuid.NewString()})
+}
@@ -211,30 +211,32 @@ func (x *User) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
-// 策略相关消息
-type CreatePolicyRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Description    string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	PolicyDocument string                 `protobuf:"bytes,3,opt,name=policy_document,json=policyDocument,proto3" json:"policy_document,omitempty"` // JSON字符串
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
-}
-
-func (x *CreatePolicyRequest) Reset() {
-	*x = CreatePolicyRequest{}
+// 登录相关消息
+type LoginProfile struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	UserName              string                 `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	CreatedAt             *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	PasswordChangedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=password_changed_at,json=passwordChangedAt,proto3" json:"password_changed_at,omitempty"`
+	PasswordExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=password_expires_at,json=passwordExpiresAt,proto3" json:"password_expires_at,omitempty"`              // 为空表示密码永不过期
+	PasswordResetRequired bool                   `protobuf:"varint,5,opt,name=password_reset_required,json=passwordResetRequired,proto3" json:"password_reset_required,omitempty"` // 下次登录时必须修改密码
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *LoginProfile) Reset() {
+	*x = LoginProfile{}
 	mi := &file_proto_iam_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreatePolicyRequest) String() string {
+func (x *LoginProfile) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreatePolicyRequest) ProtoMessage() {}
+func (*LoginProfile) ProtoMessage() {}
 
-func (x *CreatePolicyRequest) ProtoReflect() protoreflect.Message {
+func (x *LoginProfile) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_iam_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -246,54 +248,69 @@ func (x *CreatePolicyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreatePolicyRequest.ProtoReflect.Descriptor instead.
-func (*CreatePolicyRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use LoginProfile.ProtoReflect.Descriptor instead.
+func (*LoginProfile) Descriptor() ([]byte, []int) {
 	return file_proto_iam_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *CreatePolicyRequest) GetName() string {
+func (x *LoginProfile) GetUserName() string {
 	if x != nil {
-		return x.Name
+		return x.UserName
 	}
 	return ""
 }
 
-func (x *CreatePolicyRequest) GetDescription() string {
+func (x *LoginProfile) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Description
+		return x.CreatedAt
 	}
-	return ""
+	return nil
 }
 
-func (x *CreatePolicyRequest) GetPolicyDocument() string {
+func (x *LoginProfile) GetPasswordChangedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.PolicyDocument
+		return x.PasswordChangedAt
 	}
-	return ""
+	return nil
 }
 
-type AttachUserPolicyRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserName      string                 `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
-	PolicyName    string                 `protobuf:"bytes,2,opt,name=policy_name,json=policyName,proto3" json:"policy_name,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *LoginProfile) GetPasswordExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PasswordExpiresAt
+	}
+	return nil
 }
 
-func (x *AttachUserPolicyRequest) Reset() {
-	*x = AttachUserPolicyRequest{}
+func (x *LoginProfile) GetPasswordResetRequired() bool {
+	if x != nil {
+		return x.PasswordResetRequired
+	}
+	return false
+}
+
+type CreateLoginProfileRequest struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	UserName              string                 `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	Password              string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	PasswordResetRequired bool                   `protobuf:"varint,3,opt,name=password_reset_required,json=passwordResetRequired,proto3" json:"password_reset_required,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *CreateLoginProfileRequest) Reset() {
+	*x = CreateLoginProfileRequest{}
 	mi := &file_proto_iam_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AttachUserPolicyRequest) String() string {
+func (x *CreateLoginProfileRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AttachUserPolicyRequest) ProtoMessage() {}
+func (*CreateLoginProfileRequest) ProtoMessage() {}
 
-func (x *AttachUserPolicyRequest) ProtoReflect() protoreflect.Message {
+func (x *CreateLoginProfileRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_iam_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -305,46 +322,55 @@ func (x *AttachUserPolicyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AttachUserPolicyRequest.ProtoReflect.Descriptor instead.
-func (*AttachUserPolicyRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CreateLoginProfileRequest.ProtoReflect.Descriptor instead.
+func (*CreateLoginProfileRequest) Descriptor() ([]byte, []int) {
 	return file_proto_iam_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *AttachUserPolicyRequest) GetUserName() string {
+func (x *CreateLoginProfileRequest) GetUserName() string {
 	if x != nil {
 		return x.UserName
 	}
 	return ""
 }
 
-func (x *AttachUserPolicyRequest) GetPolicyName() string {
+func (x *CreateLoginProfileRequest) GetPassword() string {
 	if x != nil {
-		return x.PolicyName
+		return x.Password
 	}
 	return ""
 }
 
-type AttachUserPolicyResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *CreateLoginProfileRequest) GetPasswordResetRequired() bool {
+	if x != nil {
+		return x.PasswordResetRequired
+	}
+	return false
 }
 
-func (x *AttachUserPolicyResponse) Reset() {
-	*x = AttachUserPolicyResponse{}
+type UpdateLoginProfileRequest struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	UserName              string                 `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	Password              string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"` // 为空时只修改password_reset_required
+	PasswordResetRequired bool                   `protobuf:"varint,3,opt,name=password_reset_required,json=passwordResetRequired,proto3" json:"password_reset_required,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *UpdateLoginProfileRequest) Reset() {
+	*x = UpdateLoginProfileRequest{}
 	mi := &file_proto_iam_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AttachUserPolicyResponse) String() string {
+func (x *UpdateLoginProfileRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AttachUserPolicyResponse) ProtoMessage() {}
+func (*UpdateLoginProfileRequest) ProtoMessage() {}
 
-func (x *AttachUserPolicyResponse) ProtoReflect() protoreflect.Message {
+func (x *UpdateLoginProfileRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_iam_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -356,44 +382,53 @@ func (x *AttachUserPolicyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AttachUserPolicyResponse.ProtoReflect.Descriptor instead.
-func (*AttachUserPolicyResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use UpdateLoginProfileRequest.ProtoReflect.Descriptor instead.
+func (*UpdateLoginProfileRequest) Descriptor() ([]byte, []int) {
 	return file_proto_iam_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *AttachUserPolicyResponse) GetSuccess() bool {
+func (x *UpdateLoginProfileRequest) GetUserName() string {
 	if x != nil {
-		return x.Success
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *UpdateLoginProfileRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *UpdateLoginProfileRequest) GetPasswordResetRequired() bool {
+	if x != nil {
+		return x.PasswordResetRequired
 	}
 	return false
 }
 
-type Policy struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description    string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	PolicyDocument string                 `protobuf:"bytes,4,opt,name=policy_document,json=policyDocument,proto3" json:"policy_document,omitempty"`
-	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+type DeleteLoginProfileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserName      string                 `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Policy) Reset() {
-	*x = Policy{}
+func (x *DeleteLoginProfileRequest) Reset() {
+	*x = DeleteLoginProfileRequest{}
 	mi := &file_proto_iam_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Policy) String() string {
+func (x *DeleteLoginProfileRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Policy) ProtoMessage() {}
+func (*DeleteLoginProfileRequest) ProtoMessage() {}
 
-func (x *Policy) ProtoReflect() protoreflect.Message {
+func (x *DeleteLoginProfileRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_iam_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -405,76 +440,212 @@ func (x *Policy) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Policy.ProtoReflect.Descriptor instead.
-func (*Policy) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeleteLoginProfileRequest.ProtoReflect.Descriptor instead.
+func (*DeleteLoginProfileRequest) Descriptor() ([]byte, []int) {
 	return file_proto_iam_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *Policy) GetId() int64 {
+func (x *DeleteLoginProfileRequest) GetUserName() string {
 	if x != nil {
-		return x.Id
+		return x.UserName
 	}
-	return 0
+	return ""
 }
 
-func (x *Policy) GetName() string {
+type DeleteLoginProfileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLoginProfileResponse) Reset() {
+	*x = DeleteLoginProfileResponse{}
+	mi := &file_proto_iam_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLoginProfileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLoginProfileResponse) ProtoMessage() {}
+
+func (x *DeleteLoginProfileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[7]
 	if x != nil {
-		return x.Name
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLoginProfileResponse.ProtoReflect.Descriptor instead.
+func (*DeleteLoginProfileResponse) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{7}
+}
+
+type AuthenticateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserName      string                 `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	NewPassword   string                 `protobuf:"bytes,3,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"` // 密码已过期或被要求重置时必填
+	MfaCode       string                 `protobuf:"bytes,4,opt,name=mfa_code,json=mfaCode,proto3" json:"mfa_code,omitempty"`             // 用户已启用MFA设备时必填
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthenticateRequest) Reset() {
+	*x = AuthenticateRequest{}
+	mi := &file_proto_iam_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthenticateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticateRequest) ProtoMessage() {}
+
+func (x *AuthenticateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticateRequest.ProtoReflect.Descriptor instead.
+func (*AuthenticateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AuthenticateRequest) GetUserName() string {
+	if x != nil {
+		return x.UserName
 	}
 	return ""
 }
 
-func (x *Policy) GetDescription() string {
+func (x *AuthenticateRequest) GetPassword() string {
 	if x != nil {
-		return x.Description
+		return x.Password
 	}
 	return ""
 }
 
-func (x *Policy) GetPolicyDocument() string {
+func (x *AuthenticateRequest) GetNewPassword() string {
 	if x != nil {
-		return x.PolicyDocument
+		return x.NewPassword
 	}
 	return ""
 }
 
-func (x *Policy) GetCreatedAt() *timestamppb.Timestamp {
+func (x *AuthenticateRequest) GetMfaCode() string {
 	if x != nil {
-		return x.CreatedAt
+		return x.MfaCode
 	}
-	return nil
+	return ""
 }
 
-func (x *Policy) GetUpdatedAt() *timestamppb.Timestamp {
+type AuthenticateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionToken  string                 `protobuf:"bytes,1,opt,name=session_token,json=sessionToken,proto3" json:"session_token,omitempty"` // 通过 authorization: IAM-Session <token> 调用其他RPC
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	UserName      string                 `protobuf:"bytes,3,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	MfaPresent    bool                   `protobuf:"varint,4,opt,name=mfa_present,json=mfaPresent,proto3" json:"mfa_present,omitempty"` // 本次登录是否通过了MFA
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthenticateResponse) Reset() {
+	*x = AuthenticateResponse{}
+	mi := &file_proto_iam_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthenticateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticateResponse) ProtoMessage() {}
+
+func (x *AuthenticateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[9]
 	if x != nil {
-		return x.UpdatedAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticateResponse.ProtoReflect.Descriptor instead.
+func (*AuthenticateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AuthenticateResponse) GetSessionToken() string {
+	if x != nil {
+		return x.SessionToken
+	}
+	return ""
+}
+
+func (x *AuthenticateResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
 	}
 	return nil
 }
 
-// 访问密钥相关消息
-type CreateAccessKeyRequest struct {
+func (x *AuthenticateResponse) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *AuthenticateResponse) GetMfaPresent() bool {
+	if x != nil {
+		return x.MfaPresent
+	}
+	return false
+}
+
+type UnlockUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserName      string                 `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateAccessKeyRequest) Reset() {
-	*x = CreateAccessKeyRequest{}
-	mi := &file_proto_iam_proto_msgTypes[7]
+func (x *UnlockUserRequest) Reset() {
+	*x = UnlockUserRequest{}
+	mi := &file_proto_iam_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateAccessKeyRequest) String() string {
+func (x *UnlockUserRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateAccessKeyRequest) ProtoMessage() {}
+func (*UnlockUserRequest) ProtoMessage() {}
 
-func (x *CreateAccessKeyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_iam_proto_msgTypes[7]
+func (x *UnlockUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -485,85 +656,1817 @@ func (x *CreateAccessKeyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateAccessKeyRequest.ProtoReflect.Descriptor instead.
-func (*CreateAccessKeyRequest) Descriptor() ([]byte, []int) {
-	return file_proto_iam_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use UnlockUserRequest.ProtoReflect.Descriptor instead.
+func (*UnlockUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *CreateAccessKeyRequest) GetUserName() string {
+func (x *UnlockUserRequest) GetUserName() string {
 	if x != nil {
 		return x.UserName
 	}
 	return ""
 }
 
-type ListAccessKeysRequest struct {
+type UnlockUserResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserName      string                 `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListAccessKeysRequest) Reset() {
-	*x = ListAccessKeysRequest{}
-	mi := &file_proto_iam_proto_msgTypes[8]
+func (x *UnlockUserResponse) Reset() {
+	*x = UnlockUserResponse{}
+	mi := &file_proto_iam_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListAccessKeysRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *UnlockUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockUserResponse) ProtoMessage() {}
+
+func (x *UnlockUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockUserResponse.ProtoReflect.Descriptor instead.
+func (*UnlockUserResponse) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{11}
+}
+
+type GetSessionTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MfaCode       string                 `protobuf:"bytes,1,opt,name=mfa_code,json=mfaCode,proto3" json:"mfa_code,omitempty"` // 可选，校验通过后会话满足 iam:MultiFactorAuthPresent
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSessionTokenRequest) Reset() {
+	*x = GetSessionTokenRequest{}
+	mi := &file_proto_iam_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSessionTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSessionTokenRequest) ProtoMessage() {}
+
+func (x *GetSessionTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSessionTokenRequest.ProtoReflect.Descriptor instead.
+func (*GetSessionTokenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetSessionTokenRequest) GetMfaCode() string {
+	if x != nil {
+		return x.MfaCode
+	}
+	return ""
+}
+
+type GetSessionTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionToken  string                 `protobuf:"bytes,1,opt,name=session_token,json=sessionToken,proto3" json:"session_token,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	MfaPresent    bool                   `protobuf:"varint,3,opt,name=mfa_present,json=mfaPresent,proto3" json:"mfa_present,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSessionTokenResponse) Reset() {
+	*x = GetSessionTokenResponse{}
+	mi := &file_proto_iam_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSessionTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSessionTokenResponse) ProtoMessage() {}
+
+func (x *GetSessionTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSessionTokenResponse.ProtoReflect.Descriptor instead.
+func (*GetSessionTokenResponse) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetSessionTokenResponse) GetSessionToken() string {
+	if x != nil {
+		return x.SessionToken
+	}
+	return ""
+}
+
+func (x *GetSessionTokenResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *GetSessionTokenResponse) GetMfaPresent() bool {
+	if x != nil {
+		return x.MfaPresent
+	}
+	return false
+}
+
+// MFA相关消息
+type MFADevice struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserName      string                 `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	SerialNumber  string                 `protobuf:"bytes,2,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"` // iam:mfa:<user_name>/<device_name>
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                                 // pending/active
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	EnabledAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=enabled_at,json=enabledAt,proto3" json:"enabled_at,omitempty"`
+	Secret        string                 `protobuf:"bytes,6,opt,name=secret,proto3" json:"secret,omitempty"`                           // Base32编码的TOTP密钥，仅创建时返回
+	OtpauthUri    string                 `protobuf:"bytes,7,opt,name=otpauth_uri,json=otpauthUri,proto3" json:"otpauth_uri,omitempty"` // 供验证器应用扫描的URI，仅创建时返回
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MFADevice) Reset() {
+	*x = MFADevice{}
+	mi := &file_proto_iam_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MFADevice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MFADevice) ProtoMessage() {}
+
+func (x *MFADevice) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MFADevice.ProtoReflect.Descriptor instead.
+func (*MFADevice) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *MFADevice) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *MFADevice) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *MFADevice) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *MFADevice) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *MFADevice) GetEnabledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EnabledAt
+	}
+	return nil
+}
+
+func (x *MFADevice) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *MFADevice) GetOtpauthUri() string {
+	if x != nil {
+		return x.OtpauthUri
+	}
+	return ""
+}
+
+type CreateVirtualMFADeviceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserName      string                 `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	DeviceName    string                 `protobuf:"bytes,2,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateVirtualMFADeviceRequest) Reset() {
+	*x = CreateVirtualMFADeviceRequest{}
+	mi := &file_proto_iam_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVirtualMFADeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVirtualMFADeviceRequest) ProtoMessage() {}
+
+func (x *CreateVirtualMFADeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVirtualMFADeviceRequest.ProtoReflect.Descriptor instead.
+func (*CreateVirtualMFADeviceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CreateVirtualMFADeviceRequest) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *CreateVirtualMFADeviceRequest) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+type EnableMFADeviceRequest struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	UserName             string                 `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	SerialNumber         string                 `protobuf:"bytes,2,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	AuthenticationCode_1 string                 `protobuf:"bytes,3,opt,name=authentication_code_1,json=authenticationCode1,proto3" json:"authentication_code_1,omitempty"` // 两个连续的验证码
+	AuthenticationCode_2 string                 `protobuf:"bytes,4,opt,name=authentication_code_2,json=authenticationCode2,proto3" json:"authentication_code_2,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *EnableMFADeviceRequest) Reset() {
+	*x = EnableMFADeviceRequest{}
+	mi := &file_proto_iam_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnableMFADeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnableMFADeviceRequest) ProtoMessage() {}
+
+func (x *EnableMFADeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnableMFADeviceRequest.ProtoReflect.Descriptor instead.
+func (*EnableMFADeviceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *EnableMFADeviceRequest) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *EnableMFADeviceRequest) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *EnableMFADeviceRequest) GetAuthenticationCode_1() string {
+	if x != nil {
+		return x.AuthenticationCode_1
+	}
+	return ""
+}
+
+func (x *EnableMFADeviceRequest) GetAuthenticationCode_2() string {
+	if x != nil {
+		return x.AuthenticationCode_2
+	}
+	return ""
+}
+
+type DeactivateMFADeviceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserName      string                 `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	SerialNumber  string                 `protobuf:"bytes,2,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeactivateMFADeviceRequest) Reset() {
+	*x = DeactivateMFADeviceRequest{}
+	mi := &file_proto_iam_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeactivateMFADeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeactivateMFADeviceRequest) ProtoMessage() {}
+
+func (x *DeactivateMFADeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeactivateMFADeviceRequest.ProtoReflect.Descriptor instead.
+func (*DeactivateMFADeviceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DeactivateMFADeviceRequest) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *DeactivateMFADeviceRequest) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+type DeactivateMFADeviceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeactivateMFADeviceResponse) Reset() {
+	*x = DeactivateMFADeviceResponse{}
+	mi := &file_proto_iam_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeactivateMFADeviceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeactivateMFADeviceResponse) ProtoMessage() {}
+
+func (x *DeactivateMFADeviceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeactivateMFADeviceResponse.ProtoReflect.Descriptor instead.
+func (*DeactivateMFADeviceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{18}
+}
+
+type ListMFADevicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserName      string                 `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMFADevicesRequest) Reset() {
+	*x = ListMFADevicesRequest{}
+	mi := &file_proto_iam_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMFADevicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMFADevicesRequest) ProtoMessage() {}
+
+func (x *ListMFADevicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMFADevicesRequest.ProtoReflect.Descriptor instead.
+func (*ListMFADevicesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListMFADevicesRequest) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+type ListMFADevicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MfaDevices    []*MFADevice           `protobuf:"bytes,1,rep,name=mfa_devices,json=mfaDevices,proto3" json:"mfa_devices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMFADevicesResponse) Reset() {
+	*x = ListMFADevicesResponse{}
+	mi := &file_proto_iam_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMFADevicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMFADevicesResponse) ProtoMessage() {}
+
+func (x *ListMFADevicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMFADevicesResponse.ProtoReflect.Descriptor instead.
+func (*ListMFADevicesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListMFADevicesResponse) GetMfaDevices() []*MFADevice {
+	if x != nil {
+		return x.MfaDevices
+	}
+	return nil
+}
+
+// 策略相关消息
+type CreatePolicyRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description    string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	PolicyDocument string                 `protobuf:"bytes,3,opt,name=policy_document,json=policyDocument,proto3" json:"policy_document,omitempty"` // JSON字符串
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreatePolicyRequest) Reset() {
+	*x = CreatePolicyRequest{}
+	mi := &file_proto_iam_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePolicyRequest) ProtoMessage() {}
+
+func (x *CreatePolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePolicyRequest.ProtoReflect.Descriptor instead.
+func (*CreatePolicyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *CreatePolicyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreatePolicyRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreatePolicyRequest) GetPolicyDocument() string {
+	if x != nil {
+		return x.PolicyDocument
+	}
+	return ""
+}
+
+type AttachUserPolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserName      string                 `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	PolicyName    string                 `protobuf:"bytes,2,opt,name=policy_name,json=policyName,proto3" json:"policy_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachUserPolicyRequest) Reset() {
+	*x = AttachUserPolicyRequest{}
+	mi := &file_proto_iam_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachUserPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachUserPolicyRequest) ProtoMessage() {}
+
+func (x *AttachUserPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachUserPolicyRequest.ProtoReflect.Descriptor instead.
+func (*AttachUserPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *AttachUserPolicyRequest) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *AttachUserPolicyRequest) GetPolicyName() string {
+	if x != nil {
+		return x.PolicyName
+	}
+	return ""
+}
+
+type AttachUserPolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachUserPolicyResponse) Reset() {
+	*x = AttachUserPolicyResponse{}
+	mi := &file_proto_iam_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachUserPolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachUserPolicyResponse) ProtoMessage() {}
+
+func (x *AttachUserPolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachUserPolicyResponse.ProtoReflect.Descriptor instead.
+func (*AttachUserPolicyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *AttachUserPolicyResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type Policy struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description    string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	PolicyDocument string                 `protobuf:"bytes,4,opt,name=policy_document,json=policyDocument,proto3" json:"policy_document,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Policy) Reset() {
+	*x = Policy{}
+	mi := &file_proto_iam_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Policy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Policy) ProtoMessage() {}
+
+func (x *Policy) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Policy.ProtoReflect.Descriptor instead.
+func (*Policy) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *Policy) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Policy) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Policy) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Policy) GetPolicyDocument() string {
+	if x != nil {
+		return x.PolicyDocument
+	}
+	return ""
+}
+
+func (x *Policy) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Policy) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// 访问密钥相关消息
+type CreateAccessKeyRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserName       string                 `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	TtlSeconds     int64                  `protobuf:"varint,2,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`            // 有效期（秒），0表示使用服务端默认值
+	CredentialType string                 `protobuf:"bytes,3,opt,name=credential_type,json=credentialType,proto3" json:"credential_type,omitempty"` // signing（默认）/api_key，api_key只保存密钥哈希
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateAccessKeyRequest) Reset() {
+	*x = CreateAccessKeyRequest{}
+	mi := &file_proto_iam_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAccessKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAccessKeyRequest) ProtoMessage() {}
+
+func (x *CreateAccessKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAccessKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateAccessKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *CreateAccessKeyRequest) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *CreateAccessKeyRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+func (x *CreateAccessKeyRequest) GetCredentialType() string {
+	if x != nil {
+		return x.CredentialType
+	}
+	return ""
+}
+
+type ListAccessKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserName      string                 `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAccessKeysRequest) Reset() {
+	*x = ListAccessKeysRequest{}
+	mi := &file_proto_iam_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAccessKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAccessKeysRequest) ProtoMessage() {}
+
+func (x *ListAccessKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAccessKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListAccessKeysRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListAccessKeysRequest) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+type UpdateAccessKeyStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessKeyId   string                 `protobuf:"bytes,1,opt,name=access_key_id,json=accessKeyId,proto3" json:"access_key_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // active/inactive
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAccessKeyStatusRequest) Reset() {
+	*x = UpdateAccessKeyStatusRequest{}
+	mi := &file_proto_iam_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAccessKeyStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAccessKeyStatusRequest) ProtoMessage() {}
+
+func (x *UpdateAccessKeyStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAccessKeyStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAccessKeyStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *UpdateAccessKeyStatusRequest) GetAccessKeyId() string {
+	if x != nil {
+		return x.AccessKeyId
+	}
+	return ""
+}
+
+func (x *UpdateAccessKeyStatusRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type RotateAccessKeyRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	AccessKeyId string                 `protobuf:"bytes,1,opt,name=access_key_id,json=accessKeyId,proto3" json:"access_key_id,omitempty"`
+	// 旧密钥宽限期（秒），未设置时使用服务端默认值，0表示旧密钥立即失效
+	GracePeriodSeconds *int64 `protobuf:"varint,2,opt,name=grace_period_seconds,json=gracePeriodSeconds,proto3,oneof" json:"grace_period_seconds,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *RotateAccessKeyRequest) Reset() {
+	*x = RotateAccessKeyRequest{}
+	mi := &file_proto_iam_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateAccessKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateAccessKeyRequest) ProtoMessage() {}
+
+func (x *RotateAccessKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateAccessKeyRequest.ProtoReflect.Descriptor instead.
+func (*RotateAccessKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RotateAccessKeyRequest) GetAccessKeyId() string {
+	if x != nil {
+		return x.AccessKeyId
+	}
+	return ""
+}
+
+func (x *RotateAccessKeyRequest) GetGracePeriodSeconds() int64 {
+	if x != nil && x.GracePeriodSeconds != nil {
+		return *x.GracePeriodSeconds
+	}
+	return 0
+}
+
+type DeleteAccessKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessKeyId   string                 `protobuf:"bytes,1,opt,name=access_key_id,json=accessKeyId,proto3" json:"access_key_id,omitempty"`
+	Force         bool                   `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"` // 为true时允许删除仍处于激活状态的密钥
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAccessKeyRequest) Reset() {
+	*x = DeleteAccessKeyRequest{}
+	mi := &file_proto_iam_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAccessKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAccessKeyRequest) ProtoMessage() {}
+
+func (x *DeleteAccessKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAccessKeyRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAccessKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *DeleteAccessKeyRequest) GetAccessKeyId() string {
+	if x != nil {
+		return x.AccessKeyId
+	}
+	return ""
+}
+
+func (x *DeleteAccessKeyRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+type DeleteAccessKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAccessKeyResponse) Reset() {
+	*x = DeleteAccessKeyResponse{}
+	mi := &file_proto_iam_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAccessKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAccessKeyResponse) ProtoMessage() {}
+
+func (x *DeleteAccessKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAccessKeyResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAccessKeyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *DeleteAccessKeyResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type AccessKey struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	AccessKeyId             string                 `protobuf:"bytes,1,opt,name=access_key_id,json=accessKeyId,proto3" json:"access_key_id,omitempty"`
+	SecretAccessKey         string                 `protobuf:"bytes,2,opt,name=secret_access_key,json=secretAccessKey,proto3" json:"secret_access_key,omitempty"` // 仅在创建时返回
+	Status                  string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	UserName                string                 `protobuf:"bytes,4,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	CreatedAt               *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt               *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ExpiresAt               *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`                                               // 为空表示永不过期
+	PreviousSecretExpiresAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=previous_secret_expires_at,json=previousSecretExpiresAt,proto3" json:"previous_secret_expires_at,omitempty"` // 轮换后旧密钥的失效时间
+	LastUsed                *AccessKeyLastUsed     `protobuf:"bytes,9,opt,name=last_used,json=lastUsed,proto3" json:"last_used,omitempty"`                                                  // 从未使用时为空
+	CredentialType          string                 `protobuf:"bytes,10,opt,name=credential_type,json=credentialType,proto3" json:"credential_type,omitempty"`                               // signing/api_key
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *AccessKey) Reset() {
+	*x = AccessKey{}
+	mi := &file_proto_iam_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessKey) ProtoMessage() {}
+
+func (x *AccessKey) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessKey.ProtoReflect.Descriptor instead.
+func (*AccessKey) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *AccessKey) GetAccessKeyId() string {
+	if x != nil {
+		return x.AccessKeyId
+	}
+	return ""
+}
+
+func (x *AccessKey) GetSecretAccessKey() string {
+	if x != nil {
+		return x.SecretAccessKey
+	}
+	return ""
+}
+
+func (x *AccessKey) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *AccessKey) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *AccessKey) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *AccessKey) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *AccessKey) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *AccessKey) GetPreviousSecretExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PreviousSecretExpiresAt
+	}
+	return nil
+}
+
+func (x *AccessKey) GetLastUsed() *AccessKeyLastUsed {
+	if x != nil {
+		return x.LastUsed
+	}
+	return nil
+}
+
+func (x *AccessKey) GetCredentialType() string {
+	if x != nil {
+		return x.CredentialType
+	}
+	return ""
+}
+
+type AccessKeyLastUsed struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LastUsedAt    *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
+	Method        string                 `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`                     // 最后调用的RPC方法
+	SourceIp      string                 `protobuf:"bytes,3,opt,name=source_ip,json=sourceIp,proto3" json:"source_ip,omitempty"` // 最后调用的来源IP
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccessKeyLastUsed) Reset() {
+	*x = AccessKeyLastUsed{}
+	mi := &file_proto_iam_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessKeyLastUsed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessKeyLastUsed) ProtoMessage() {}
+
+func (x *AccessKeyLastUsed) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessKeyLastUsed.ProtoReflect.Descriptor instead.
+func (*AccessKeyLastUsed) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *AccessKeyLastUsed) GetLastUsedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return nil
+}
+
+func (x *AccessKeyLastUsed) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *AccessKeyLastUsed) GetSourceIp() string {
+	if x != nil {
+		return x.SourceIp
+	}
+	return ""
+}
+
+type GetAccessKeyLastUsedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessKeyId   string                 `protobuf:"bytes,1,opt,name=access_key_id,json=accessKeyId,proto3" json:"access_key_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAccessKeyLastUsedRequest) Reset() {
+	*x = GetAccessKeyLastUsedRequest{}
+	mi := &file_proto_iam_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAccessKeyLastUsedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccessKeyLastUsedRequest) ProtoMessage() {}
+
+func (x *GetAccessKeyLastUsedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccessKeyLastUsedRequest.ProtoReflect.Descriptor instead.
+func (*GetAccessKeyLastUsedRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetAccessKeyLastUsedRequest) GetAccessKeyId() string {
+	if x != nil {
+		return x.AccessKeyId
+	}
+	return ""
+}
+
+type GetAccessKeyLastUsedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserName      string                 `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	LastUsed      *AccessKeyLastUsed     `protobuf:"bytes,2,opt,name=last_used,json=lastUsed,proto3" json:"last_used,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAccessKeyLastUsedResponse) Reset() {
+	*x = GetAccessKeyLastUsedResponse{}
+	mi := &file_proto_iam_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAccessKeyLastUsedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccessKeyLastUsedResponse) ProtoMessage() {}
+
+func (x *GetAccessKeyLastUsedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccessKeyLastUsedResponse.ProtoReflect.Descriptor instead.
+func (*GetAccessKeyLastUsedResponse) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetAccessKeyLastUsedResponse) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *GetAccessKeyLastUsedResponse) GetLastUsed() *AccessKeyLastUsed {
+	if x != nil {
+		return x.LastUsed
+	}
+	return nil
+}
+
+type ListAccessKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessKeys    []*AccessKey           `protobuf:"bytes,1,rep,name=access_keys,json=accessKeys,proto3" json:"access_keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAccessKeysResponse) Reset() {
+	*x = ListAccessKeysResponse{}
+	mi := &file_proto_iam_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAccessKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAccessKeysResponse) ProtoMessage() {}
+
+func (x *ListAccessKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAccessKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListAccessKeysResponse) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ListAccessKeysResponse) GetAccessKeys() []*AccessKey {
+	if x != nil {
+		return x.AccessKeys
+	}
+	return nil
+}
+
+// 验证相关消息
+// 签名规范见 docs/signing.md
+type VerifyRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AccessKeyId     string                 `protobuf:"bytes,1,opt,name=access_key_id,json=accessKeyId,proto3" json:"access_key_id,omitempty"`
+	Signature       string                 `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	RequestData     string                 `protobuf:"bytes,3,opt,name=request_data,json=requestData,proto3" json:"request_data,omitempty"`             // 调用方根据收到的请求计算出的规范请求
+	Timestamp       string                 `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                                    // 签名时间，格式 YYYYMMDDTHHMMSSZ
+	CredentialScope string                 `protobuf:"bytes,5,opt,name=credential_scope,json=credentialScope,proto3" json:"credential_scope,omitempty"` // <date>/<region>/<service>/iam_request
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *VerifyRequest) Reset() {
+	*x = VerifyRequest{}
+	mi := &file_proto_iam_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyRequest) ProtoMessage() {}
+
+func (x *VerifyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyRequest.ProtoReflect.Descriptor instead.
+func (*VerifyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *VerifyRequest) GetAccessKeyId() string {
+	if x != nil {
+		return x.AccessKeyId
+	}
+	return ""
+}
+
+func (x *VerifyRequest) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *VerifyRequest) GetRequestData() string {
+	if x != nil {
+		return x.RequestData
+	}
+	return ""
+}
+
+func (x *VerifyRequest) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *VerifyRequest) GetCredentialScope() string {
+	if x != nil {
+		return x.CredentialScope
+	}
+	return ""
+}
+
+type VerifyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	UserName      string                 `protobuf:"bytes,2,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyResponse) Reset() {
+	*x = VerifyResponse{}
+	mi := &file_proto_iam_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyResponse) ProtoMessage() {}
+
+func (x *VerifyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyResponse.ProtoReflect.Descriptor instead.
+func (*VerifyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *VerifyResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *VerifyResponse) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+type VerifyApiKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"` // <access_key_id>.<secret_access_key>
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyApiKeyRequest) Reset() {
+	*x = VerifyApiKeyRequest{}
+	mi := &file_proto_iam_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyApiKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyApiKeyRequest) ProtoMessage() {}
+
+func (x *VerifyApiKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyApiKeyRequest.ProtoReflect.Descriptor instead.
+func (*VerifyApiKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *VerifyApiKeyRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+type VerifyApiKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	UserName      string                 `protobuf:"bytes,2,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	AccessKeyId   string                 `protobuf:"bytes,3,opt,name=access_key_id,json=accessKeyId,proto3" json:"access_key_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyApiKeyResponse) Reset() {
+	*x = VerifyApiKeyResponse{}
+	mi := &file_proto_iam_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyApiKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyApiKeyResponse) ProtoMessage() {}
+
+func (x *VerifyApiKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyApiKeyResponse.ProtoReflect.Descriptor instead.
+func (*VerifyApiKeyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *VerifyApiKeyResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *VerifyApiKeyResponse) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *VerifyApiKeyResponse) GetAccessKeyId() string {
+	if x != nil {
+		return x.AccessKeyId
+	}
+	return ""
+}
+
+type ValidateTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"` // 不含 Bearer 前缀
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateTokenRequest) Reset() {
+	*x = ValidateTokenRequest{}
+	mi := &file_proto_iam_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateTokenRequest) ProtoMessage() {}
+
+func (x *ValidateTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateTokenRequest.ProtoReflect.Descriptor instead.
+func (*ValidateTokenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ValidateTokenRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type ValidateTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	UserName      string                 `protobuf:"bytes,2,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`            // sub
+	AccessKeyId   string                 `protobuf:"bytes,3,opt,name=access_key_id,json=accessKeyId,proto3" json:"access_key_id,omitempty"` // client_id
+	Account       string                 `protobuf:"bytes,4,opt,name=account,proto3" json:"account,omitempty"`
+	Groups        []string               `protobuf:"bytes,5,rep,name=groups,proto3" json:"groups,omitempty"`
+	Scope         string                 `protobuf:"bytes,6,opt,name=scope,proto3" json:"scope,omitempty"` // 空格分隔
+	IssuedAt      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	TokenId       string                 `protobuf:"bytes,9,opt,name=token_id,json=tokenId,proto3" json:"token_id,omitempty"` // jti
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateTokenResponse) Reset() {
+	*x = ValidateTokenResponse{}
+	mi := &file_proto_iam_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateTokenResponse) ProtoMessage() {}
+
+func (x *ValidateTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateTokenResponse.ProtoReflect.Descriptor instead.
+func (*ValidateTokenResponse) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ValidateTokenResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateTokenResponse) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *ValidateTokenResponse) GetAccessKeyId() string {
+	if x != nil {
+		return x.AccessKeyId
+	}
+	return ""
+}
+
+func (x *ValidateTokenResponse) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *ValidateTokenResponse) GetGroups() []string {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *ValidateTokenResponse) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
 }
 
-func (*ListAccessKeysRequest) ProtoMessage() {}
-
-func (x *ListAccessKeysRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_iam_proto_msgTypes[8]
+func (x *ValidateTokenResponse) GetIssuedAt() *timestamppb.Timestamp {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.IssuedAt
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use ListAccessKeysRequest.ProtoReflect.Descriptor instead.
-func (*ListAccessKeysRequest) Descriptor() ([]byte, []int) {
-	return file_proto_iam_proto_rawDescGZIP(), []int{8}
+func (x *ValidateTokenResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
 }
 
-func (x *ListAccessKeysRequest) GetUserName() string {
+func (x *ValidateTokenResponse) GetTokenId() string {
 	if x != nil {
-		return x.UserName
+		return x.TokenId
 	}
 	return ""
 }
 
-type UpdateAccessKeyStatusRequest struct {
+type GetJWKSRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccessKeyId   string                 `protobuf:"bytes,1,opt,name=access_key_id,json=accessKeyId,proto3" json:"access_key_id,omitempty"`
-	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // active/inactive
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateAccessKeyStatusRequest) Reset() {
-	*x = UpdateAccessKeyStatusRequest{}
-	mi := &file_proto_iam_proto_msgTypes[9]
+func (x *GetJWKSRequest) Reset() {
+	*x = GetJWKSRequest{}
+	mi := &file_proto_iam_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateAccessKeyStatusRequest) String() string {
+func (x *GetJWKSRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateAccessKeyStatusRequest) ProtoMessage() {}
+func (*GetJWKSRequest) ProtoMessage() {}
 
-func (x *UpdateAccessKeyStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_iam_proto_msgTypes[9]
+func (x *GetJWKSRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -574,52 +2477,39 @@ func (x *UpdateAccessKeyStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateAccessKeyStatusRequest.ProtoReflect.Descriptor instead.
-func (*UpdateAccessKeyStatusRequest) Descriptor() ([]byte, []int) {
-	return file_proto_iam_proto_rawDescGZIP(), []int{9}
-}
-
-func (x *UpdateAccessKeyStatusRequest) GetAccessKeyId() string {
-	if x != nil {
-		return x.AccessKeyId
-	}
-	return ""
-}
-
-func (x *UpdateAccessKeyStatusRequest) GetStatus() string {
-	if x != nil {
-		return x.Status
-	}
-	return ""
+// Deprecated: Use GetJWKSRequest.ProtoReflect.Descriptor instead.
+func (*GetJWKSRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{42}
 }
 
-type AccessKey struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	AccessKeyId     string                 `protobuf:"bytes,1,opt,name=access_key_id,json=accessKeyId,proto3" json:"access_key_id,omitempty"`
-	SecretAccessKey string                 `protobuf:"bytes,2,opt,name=secret_access_key,json=secretAccessKey,proto3" json:"secret_access_key,omitempty"` // 仅在创建时返回
-	Status          string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
-	UserName        string                 `protobuf:"bytes,4,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
-	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+// Ed25519公钥的JSON Web Key(RFC 8037)
+type JSONWebKey struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kty           string                 `protobuf:"bytes,1,opt,name=kty,proto3" json:"kty,omitempty"` // OKP
+	Crv           string                 `protobuf:"bytes,2,opt,name=crv,proto3" json:"crv,omitempty"` // Ed25519
+	X             string                 `protobuf:"bytes,3,opt,name=x,proto3" json:"x,omitempty"`     // base64url编码的公钥
+	Kid           string                 `protobuf:"bytes,4,opt,name=kid,proto3" json:"kid,omitempty"` // 与令牌头部的kid对应
+	Use           string                 `protobuf:"bytes,5,opt,name=use,proto3" json:"use,omitempty"` // sig
+	Alg           string                 `protobuf:"bytes,6,opt,name=alg,proto3" json:"alg,omitempty"` // EdDSA
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AccessKey) Reset() {
-	*x = AccessKey{}
-	mi := &file_proto_iam_proto_msgTypes[10]
+func (x *JSONWebKey) Reset() {
+	*x = JSONWebKey{}
+	mi := &file_proto_iam_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AccessKey) String() string {
+func (x *JSONWebKey) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AccessKey) ProtoMessage() {}
+func (*JSONWebKey) ProtoMessage() {}
 
-func (x *AccessKey) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_iam_proto_msgTypes[10]
+func (x *JSONWebKey) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -630,75 +2520,75 @@ func (x *AccessKey) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AccessKey.ProtoReflect.Descriptor instead.
-func (*AccessKey) Descriptor() ([]byte, []int) {
-	return file_proto_iam_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use JSONWebKey.ProtoReflect.Descriptor instead.
+func (*JSONWebKey) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *AccessKey) GetAccessKeyId() string {
+func (x *JSONWebKey) GetKty() string {
 	if x != nil {
-		return x.AccessKeyId
+		return x.Kty
 	}
 	return ""
 }
 
-func (x *AccessKey) GetSecretAccessKey() string {
+func (x *JSONWebKey) GetCrv() string {
 	if x != nil {
-		return x.SecretAccessKey
+		return x.Crv
 	}
 	return ""
 }
 
-func (x *AccessKey) GetStatus() string {
+func (x *JSONWebKey) GetX() string {
 	if x != nil {
-		return x.Status
+		return x.X
 	}
 	return ""
 }
 
-func (x *AccessKey) GetUserName() string {
+func (x *JSONWebKey) GetKid() string {
 	if x != nil {
-		return x.UserName
+		return x.Kid
 	}
 	return ""
 }
 
-func (x *AccessKey) GetCreatedAt() *timestamppb.Timestamp {
+func (x *JSONWebKey) GetUse() string {
 	if x != nil {
-		return x.CreatedAt
+		return x.Use
 	}
-	return nil
+	return ""
 }
 
-func (x *AccessKey) GetUpdatedAt() *timestamppb.Timestamp {
+func (x *JSONWebKey) GetAlg() string {
 	if x != nil {
-		return x.UpdatedAt
+		return x.Alg
 	}
-	return nil
+	return ""
 }
 
-type ListAccessKeysResponse struct {
+type GetJWKSResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccessKeys    []*AccessKey           `protobuf:"bytes,1,rep,name=access_keys,json=accessKeys,proto3" json:"access_keys,omitempty"`
+	Keys          []*JSONWebKey          `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListAccessKeysResponse) Reset() {
-	*x = ListAccessKeysResponse{}
-	mi := &file_proto_iam_proto_msgTypes[11]
+func (x *GetJWKSResponse) Reset() {
+	*x = GetJWKSResponse{}
+	mi := &file_proto_iam_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListAccessKeysResponse) String() string {
+func (x *GetJWKSResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListAccessKeysResponse) ProtoMessage() {}
+func (*GetJWKSResponse) ProtoMessage() {}
 
-func (x *ListAccessKeysResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_iam_proto_msgTypes[11]
+func (x *GetJWKSResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -709,44 +2599,44 @@ func (x *ListAccessKeysResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListAccessKeysResponse.ProtoReflect.Descriptor instead.
-func (*ListAccessKeysResponse) Descriptor() ([]byte, []int) {
-	return file_proto_iam_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use GetJWKSResponse.ProtoReflect.Descriptor instead.
+func (*GetJWKSResponse) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{44}
 }
 
-func (x *ListAccessKeysResponse) GetAccessKeys() []*AccessKey {
+func (x *GetJWKSResponse) GetKeys() []*JSONWebKey {
 	if x != nil {
-		return x.AccessKeys
+		return x.Keys
 	}
 	return nil
 }
 
-// 验证相关消息
-type VerifyRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccessKeyId   string                 `protobuf:"bytes,1,opt,name=access_key_id,json=accessKeyId,proto3" json:"access_key_id,omitempty"`
-	Signature     string                 `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
-	RequestData   string                 `protobuf:"bytes,3,opt,name=request_data,json=requestData,proto3" json:"request_data,omitempty"` // 序列化的请求数据
-	Timestamp     string                 `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                        // ISO8601格式
+type CheckPermissionRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	UserName string                 `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	Action   string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	Resource string                 `protobuf:"bytes,3,opt,name=resource,proto3" json:"resource,omitempty"`
+	// 策略条件使用的请求上下文，如 {"iam:MultiFactorAuthPresent": "true"}
+	Context       map[string]string `protobuf:"bytes,4,rep,name=context,proto3" json:"context,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *VerifyRequest) Reset() {
-	*x = VerifyRequest{}
-	mi := &file_proto_iam_proto_msgTypes[12]
+func (x *CheckPermissionRequest) Reset() {
+	*x = CheckPermissionRequest{}
+	mi := &file_proto_iam_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *VerifyRequest) String() string {
+func (x *CheckPermissionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*VerifyRequest) ProtoMessage() {}
+func (*CheckPermissionRequest) ProtoMessage() {}
 
-func (x *VerifyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_iam_proto_msgTypes[12]
+func (x *CheckPermissionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -757,62 +2647,61 @@ func (x *VerifyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use VerifyRequest.ProtoReflect.Descriptor instead.
-func (*VerifyRequest) Descriptor() ([]byte, []int) {
-	return file_proto_iam_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use CheckPermissionRequest.ProtoReflect.Descriptor instead.
+func (*CheckPermissionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{45}
 }
 
-func (x *VerifyRequest) GetAccessKeyId() string {
+func (x *CheckPermissionRequest) GetUserName() string {
 	if x != nil {
-		return x.AccessKeyId
+		return x.UserName
 	}
 	return ""
 }
 
-func (x *VerifyRequest) GetSignature() string {
+func (x *CheckPermissionRequest) GetAction() string {
 	if x != nil {
-		return x.Signature
+		return x.Action
 	}
 	return ""
 }
 
-func (x *VerifyRequest) GetRequestData() string {
+func (x *CheckPermissionRequest) GetResource() string {
 	if x != nil {
-		return x.RequestData
+		return x.Resource
 	}
 	return ""
 }
 
-func (x *VerifyRequest) GetTimestamp() string {
+func (x *CheckPermissionRequest) GetContext() map[string]string {
 	if x != nil {
-		return x.Timestamp
+		return x.Context
 	}
-	return ""
+	return nil
 }
 
-type VerifyResponse struct {
+type CheckPermissionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
-	UserName      string                 `protobuf:"bytes,2,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	Allowed       bool                   `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *VerifyResponse) Reset() {
-	*x = VerifyResponse{}
-	mi := &file_proto_iam_proto_msgTypes[13]
+func (x *CheckPermissionResponse) Reset() {
+	*x = CheckPermissionResponse{}
+	mi := &file_proto_iam_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *VerifyResponse) String() string {
+func (x *CheckPermissionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*VerifyResponse) ProtoMessage() {}
+func (*CheckPermissionResponse) ProtoMessage() {}
 
-func (x *VerifyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_iam_proto_msgTypes[13]
+func (x *CheckPermissionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -823,49 +2712,41 @@ func (x *VerifyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use VerifyResponse.ProtoReflect.Descriptor instead.
-func (*VerifyResponse) Descriptor() ([]byte, []int) {
-	return file_proto_iam_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use CheckPermissionResponse.ProtoReflect.Descriptor instead.
+func (*CheckPermissionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{46}
 }
 
-func (x *VerifyResponse) GetValid() bool {
+func (x *CheckPermissionResponse) GetAllowed() bool {
 	if x != nil {
-		return x.Valid
+		return x.Allowed
 	}
 	return false
 }
 
-func (x *VerifyResponse) GetUserName() string {
-	if x != nil {
-		return x.UserName
-	}
-	return ""
-}
-
-type CheckPermissionRequest struct {
+type GetPrincipalPoliciesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserName      string                 `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
-	Action        string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
-	Resource      string                 `protobuf:"bytes,3,opt,name=resource,proto3" json:"resource,omitempty"`
+	Etag          string                 `protobuf:"bytes,2,opt,name=etag,proto3" json:"etag,omitempty"` // 上次获取到的etag，策略未变化时返回not_modified
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CheckPermissionRequest) Reset() {
-	*x = CheckPermissionRequest{}
-	mi := &file_proto_iam_proto_msgTypes[14]
+func (x *GetPrincipalPoliciesRequest) Reset() {
+	*x = GetPrincipalPoliciesRequest{}
+	mi := &file_proto_iam_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CheckPermissionRequest) String() string {
+func (x *GetPrincipalPoliciesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CheckPermissionRequest) ProtoMessage() {}
+func (*GetPrincipalPoliciesRequest) ProtoMessage() {}
 
-func (x *CheckPermissionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_iam_proto_msgTypes[14]
+func (x *GetPrincipalPoliciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -876,54 +2757,101 @@ func (x *CheckPermissionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CheckPermissionRequest.ProtoReflect.Descriptor instead.
-func (*CheckPermissionRequest) Descriptor() ([]byte, []int) {
-	return file_proto_iam_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use GetPrincipalPoliciesRequest.ProtoReflect.Descriptor instead.
+func (*GetPrincipalPoliciesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{47}
 }
 
-func (x *CheckPermissionRequest) GetUserName() string {
+func (x *GetPrincipalPoliciesRequest) GetUserName() string {
 	if x != nil {
 		return x.UserName
 	}
 	return ""
 }
 
-func (x *CheckPermissionRequest) GetAction() string {
+func (x *GetPrincipalPoliciesRequest) GetEtag() string {
 	if x != nil {
-		return x.Action
+		return x.Etag
 	}
 	return ""
 }
 
-func (x *CheckPermissionRequest) GetResource() string {
+type PrincipalPolicy struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	PolicyDocument string                 `protobuf:"bytes,2,opt,name=policy_document,json=policyDocument,proto3" json:"policy_document,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PrincipalPolicy) Reset() {
+	*x = PrincipalPolicy{}
+	mi := &file_proto_iam_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PrincipalPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrincipalPolicy) ProtoMessage() {}
+
+func (x *PrincipalPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[48]
 	if x != nil {
-		return x.Resource
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrincipalPolicy.ProtoReflect.Descriptor instead.
+func (*PrincipalPolicy) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *PrincipalPolicy) GetName() string {
+	if x != nil {
+		return x.Name
 	}
 	return ""
 }
 
-type CheckPermissionResponse struct {
+func (x *PrincipalPolicy) GetPolicyDocument() string {
+	if x != nil {
+		return x.PolicyDocument
+	}
+	return ""
+}
+
+type GetPrincipalPoliciesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Allowed       bool                   `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
+	Policies      []*PrincipalPolicy     `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"` // 按评估顺序排列，not_modified时为空
+	Etag          string                 `protobuf:"bytes,2,opt,name=etag,proto3" json:"etag,omitempty"`
+	NotModified   bool                   `protobuf:"varint,3,opt,name=not_modified,json=notModified,proto3" json:"not_modified,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CheckPermissionResponse) Reset() {
-	*x = CheckPermissionResponse{}
-	mi := &file_proto_iam_proto_msgTypes[15]
+func (x *GetPrincipalPoliciesResponse) Reset() {
+	*x = GetPrincipalPoliciesResponse{}
+	mi := &file_proto_iam_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CheckPermissionResponse) String() string {
+func (x *GetPrincipalPoliciesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CheckPermissionResponse) ProtoMessage() {}
+func (*GetPrincipalPoliciesResponse) ProtoMessage() {}
 
-func (x *CheckPermissionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_iam_proto_msgTypes[15]
+func (x *GetPrincipalPoliciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_iam_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -934,14 +2862,28 @@ func (x *CheckPermissionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CheckPermissionResponse.ProtoReflect.Descriptor instead.
-func (*CheckPermissionResponse) Descriptor() ([]byte, []int) {
-	return file_proto_iam_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use GetPrincipalPoliciesResponse.ProtoReflect.Descriptor instead.
+func (*GetPrincipalPoliciesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_iam_proto_rawDescGZIP(), []int{49}
 }
 
-func (x *CheckPermissionResponse) GetAllowed() bool {
+func (x *GetPrincipalPoliciesResponse) GetPolicies() []*PrincipalPolicy {
 	if x != nil {
-		return x.Allowed
+		return x.Policies
+	}
+	return nil
+}
+
+func (x *GetPrincipalPoliciesResponse) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+func (x *GetPrincipalPoliciesResponse) GetNotModified() bool {
+	if x != nil {
+		return x.NotModified
 	}
 	return false
 }
@@ -965,7 +2907,77 @@ const file_proto_iam_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"t\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xb6\x02\n" +
+	"\fLoginProfile\x12\x1b\n" +
+	"\tuser_name\x18\x01 \x01(\tR\buserName\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12J\n" +
+	"\x13password_changed_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x11passwordChangedAt\x12J\n" +
+	"\x13password_expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x11passwordExpiresAt\x126\n" +
+	"\x17password_reset_required\x18\x05 \x01(\bR\x15passwordResetRequired\"\x8c\x01\n" +
+	"\x19CreateLoginProfileRequest\x12\x1b\n" +
+	"\tuser_name\x18\x01 \x01(\tR\buserName\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x126\n" +
+	"\x17password_reset_required\x18\x03 \x01(\bR\x15passwordResetRequired\"\x8c\x01\n" +
+	"\x19UpdateLoginProfileRequest\x12\x1b\n" +
+	"\tuser_name\x18\x01 \x01(\tR\buserName\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x126\n" +
+	"\x17password_reset_required\x18\x03 \x01(\bR\x15passwordResetRequired\"8\n" +
+	"\x19DeleteLoginProfileRequest\x12\x1b\n" +
+	"\tuser_name\x18\x01 \x01(\tR\buserName\"\x1c\n" +
+	"\x1aDeleteLoginProfileResponse\"\x8c\x01\n" +
+	"\x13AuthenticateRequest\x12\x1b\n" +
+	"\tuser_name\x18\x01 \x01(\tR\buserName\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x12!\n" +
+	"\fnew_password\x18\x03 \x01(\tR\vnewPassword\x12\x19\n" +
+	"\bmfa_code\x18\x04 \x01(\tR\amfaCode\"\xb4\x01\n" +
+	"\x14AuthenticateResponse\x12#\n" +
+	"\rsession_token\x18\x01 \x01(\tR\fsessionToken\x129\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x1b\n" +
+	"\tuser_name\x18\x03 \x01(\tR\buserName\x12\x1f\n" +
+	"\vmfa_present\x18\x04 \x01(\bR\n" +
+	"mfaPresent\"0\n" +
+	"\x11UnlockUserRequest\x12\x1b\n" +
+	"\tuser_name\x18\x01 \x01(\tR\buserName\"\x14\n" +
+	"\x12UnlockUserResponse\"3\n" +
+	"\x16GetSessionTokenRequest\x12\x19\n" +
+	"\bmfa_code\x18\x01 \x01(\tR\amfaCode\"\x9a\x01\n" +
+	"\x17GetSessionTokenResponse\x12#\n" +
+	"\rsession_token\x18\x01 \x01(\tR\fsessionToken\x129\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x1f\n" +
+	"\vmfa_present\x18\x03 \x01(\bR\n" +
+	"mfaPresent\"\x94\x02\n" +
+	"\tMFADevice\x12\x1b\n" +
+	"\tuser_name\x18\x01 \x01(\tR\buserName\x12#\n" +
+	"\rserial_number\x18\x02 \x01(\tR\fserialNumber\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"enabled_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tenabledAt\x12\x16\n" +
+	"\x06secret\x18\x06 \x01(\tR\x06secret\x12\x1f\n" +
+	"\votpauth_uri\x18\a \x01(\tR\n" +
+	"otpauthUri\"]\n" +
+	"\x1dCreateVirtualMFADeviceRequest\x12\x1b\n" +
+	"\tuser_name\x18\x01 \x01(\tR\buserName\x12\x1f\n" +
+	"\vdevice_name\x18\x02 \x01(\tR\n" +
+	"deviceName\"\xc2\x01\n" +
+	"\x16EnableMFADeviceRequest\x12\x1b\n" +
+	"\tuser_name\x18\x01 \x01(\tR\buserName\x12#\n" +
+	"\rserial_number\x18\x02 \x01(\tR\fserialNumber\x122\n" +
+	"\x15authentication_code_1\x18\x03 \x01(\tR\x13authenticationCode1\x122\n" +
+	"\x15authentication_code_2\x18\x04 \x01(\tR\x13authenticationCode2\"^\n" +
+	"\x1aDeactivateMFADeviceRequest\x12\x1b\n" +
+	"\tuser_name\x18\x01 \x01(\tR\buserName\x12#\n" +
+	"\rserial_number\x18\x02 \x01(\tR\fserialNumber\"\x1d\n" +
+	"\x1bDeactivateMFADeviceResponse\"4\n" +
+	"\x15ListMFADevicesRequest\x12\x1b\n" +
+	"\tuser_name\x18\x01 \x01(\tR\buserName\"L\n" +
+	"\x16ListMFADevicesResponse\x122\n" +
+	"\vmfa_devices\x18\x01 \x03(\v2\x11.iam.v1.MFADeviceR\n" +
+	"mfaDevices\"t\n" +
 	"\x13CreatePolicyRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12'\n" +
@@ -984,14 +2996,26 @@ const file_proto_iam_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"5\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x7f\n" +
 	"\x16CreateAccessKeyRequest\x12\x1b\n" +
-	"\tuser_name\x18\x01 \x01(\tR\buserName\"4\n" +
+	"\tuser_name\x18\x01 \x01(\tR\buserName\x12\x1f\n" +
+	"\vttl_seconds\x18\x02 \x01(\x03R\n" +
+	"ttlSeconds\x12'\n" +
+	"\x0fcredential_type\x18\x03 \x01(\tR\x0ecredentialType\"4\n" +
 	"\x15ListAccessKeysRequest\x12\x1b\n" +
 	"\tuser_name\x18\x01 \x01(\tR\buserName\"Z\n" +
 	"\x1cUpdateAccessKeyStatusRequest\x12\"\n" +
 	"\raccess_key_id\x18\x01 \x01(\tR\vaccessKeyId\x12\x16\n" +
-	"\x06status\x18\x02 \x01(\tR\x06status\"\x86\x02\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"\x8c\x01\n" +
+	"\x16RotateAccessKeyRequest\x12\"\n" +
+	"\raccess_key_id\x18\x01 \x01(\tR\vaccessKeyId\x125\n" +
+	"\x14grace_period_seconds\x18\x02 \x01(\x03H\x00R\x12gracePeriodSeconds\x88\x01\x01B\x17\n" +
+	"\x15_grace_period_seconds\"R\n" +
+	"\x16DeleteAccessKeyRequest\x12\"\n" +
+	"\raccess_key_id\x18\x01 \x01(\tR\vaccessKeyId\x12\x14\n" +
+	"\x05force\x18\x02 \x01(\bR\x05force\"3\n" +
+	"\x17DeleteAccessKeyResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xfb\x03\n" +
 	"\tAccessKey\x12\"\n" +
 	"\raccess_key_id\x18\x01 \x01(\tR\vaccessKeyId\x12*\n" +
 	"\x11secret_access_key\x18\x02 \x01(\tR\x0fsecretAccessKey\x12\x16\n" +
@@ -1000,35 +3024,114 @@ const file_proto_iam_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"L\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12W\n" +
+	"\x1aprevious_secret_expires_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x17previousSecretExpiresAt\x126\n" +
+	"\tlast_used\x18\t \x01(\v2\x19.iam.v1.AccessKeyLastUsedR\blastUsed\x12'\n" +
+	"\x0fcredential_type\x18\n" +
+	" \x01(\tR\x0ecredentialType\"\x86\x01\n" +
+	"\x11AccessKeyLastUsed\x12<\n" +
+	"\flast_used_at\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastUsedAt\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12\x1b\n" +
+	"\tsource_ip\x18\x03 \x01(\tR\bsourceIp\"A\n" +
+	"\x1bGetAccessKeyLastUsedRequest\x12\"\n" +
+	"\raccess_key_id\x18\x01 \x01(\tR\vaccessKeyId\"s\n" +
+	"\x1cGetAccessKeyLastUsedResponse\x12\x1b\n" +
+	"\tuser_name\x18\x01 \x01(\tR\buserName\x126\n" +
+	"\tlast_used\x18\x02 \x01(\v2\x19.iam.v1.AccessKeyLastUsedR\blastUsed\"L\n" +
 	"\x16ListAccessKeysResponse\x122\n" +
 	"\vaccess_keys\x18\x01 \x03(\v2\x11.iam.v1.AccessKeyR\n" +
-	"accessKeys\"\x92\x01\n" +
+	"accessKeys\"\xbd\x01\n" +
 	"\rVerifyRequest\x12\"\n" +
 	"\raccess_key_id\x18\x01 \x01(\tR\vaccessKeyId\x12\x1c\n" +
 	"\tsignature\x18\x02 \x01(\tR\tsignature\x12!\n" +
 	"\frequest_data\x18\x03 \x01(\tR\vrequestData\x12\x1c\n" +
-	"\ttimestamp\x18\x04 \x01(\tR\ttimestamp\"C\n" +
+	"\ttimestamp\x18\x04 \x01(\tR\ttimestamp\x12)\n" +
+	"\x10credential_scope\x18\x05 \x01(\tR\x0fcredentialScope\"C\n" +
 	"\x0eVerifyResponse\x12\x14\n" +
 	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x1b\n" +
-	"\tuser_name\x18\x02 \x01(\tR\buserName\"i\n" +
+	"\tuser_name\x18\x02 \x01(\tR\buserName\".\n" +
+	"\x13VerifyApiKeyRequest\x12\x17\n" +
+	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\"m\n" +
+	"\x14VerifyApiKeyResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x1b\n" +
+	"\tuser_name\x18\x02 \x01(\tR\buserName\x12\"\n" +
+	"\raccess_key_id\x18\x03 \x01(\tR\vaccessKeyId\",\n" +
+	"\x14ValidateTokenRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"\xc5\x02\n" +
+	"\x15ValidateTokenResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x1b\n" +
+	"\tuser_name\x18\x02 \x01(\tR\buserName\x12\"\n" +
+	"\raccess_key_id\x18\x03 \x01(\tR\vaccessKeyId\x12\x18\n" +
+	"\aaccount\x18\x04 \x01(\tR\aaccount\x12\x16\n" +
+	"\x06groups\x18\x05 \x03(\tR\x06groups\x12\x14\n" +
+	"\x05scope\x18\x06 \x01(\tR\x05scope\x127\n" +
+	"\tissued_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\bissuedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x19\n" +
+	"\btoken_id\x18\t \x01(\tR\atokenId\"\x10\n" +
+	"\x0eGetJWKSRequest\"t\n" +
+	"\n" +
+	"JSONWebKey\x12\x10\n" +
+	"\x03kty\x18\x01 \x01(\tR\x03kty\x12\x10\n" +
+	"\x03crv\x18\x02 \x01(\tR\x03crv\x12\f\n" +
+	"\x01x\x18\x03 \x01(\tR\x01x\x12\x10\n" +
+	"\x03kid\x18\x04 \x01(\tR\x03kid\x12\x10\n" +
+	"\x03use\x18\x05 \x01(\tR\x03use\x12\x10\n" +
+	"\x03alg\x18\x06 \x01(\tR\x03alg\"9\n" +
+	"\x0fGetJWKSResponse\x12&\n" +
+	"\x04keys\x18\x01 \x03(\v2\x12.iam.v1.JSONWebKeyR\x04keys\"\xec\x01\n" +
 	"\x16CheckPermissionRequest\x12\x1b\n" +
 	"\tuser_name\x18\x01 \x01(\tR\buserName\x12\x16\n" +
 	"\x06action\x18\x02 \x01(\tR\x06action\x12\x1a\n" +
-	"\bresource\x18\x03 \x01(\tR\bresource\"3\n" +
+	"\bresource\x18\x03 \x01(\tR\bresource\x12E\n" +
+	"\acontext\x18\x04 \x03(\v2+.iam.v1.CheckPermissionRequest.ContextEntryR\acontext\x1a:\n" +
+	"\fContextEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"3\n" +
 	"\x17CheckPermissionResponse\x12\x18\n" +
-	"\aallowed\x18\x01 \x01(\bR\aallowed2\x92\x05\n" +
+	"\aallowed\x18\x01 \x01(\bR\aallowed\"N\n" +
+	"\x1bGetPrincipalPoliciesRequest\x12\x1b\n" +
+	"\tuser_name\x18\x01 \x01(\tR\buserName\x12\x12\n" +
+	"\x04etag\x18\x02 \x01(\tR\x04etag\"N\n" +
+	"\x0fPrincipalPolicy\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12'\n" +
+	"\x0fpolicy_document\x18\x02 \x01(\tR\x0epolicyDocument\"\x8a\x01\n" +
+	"\x1cGetPrincipalPoliciesResponse\x123\n" +
+	"\bpolicies\x18\x01 \x03(\v2\x17.iam.v1.PrincipalPolicyR\bpolicies\x12\x12\n" +
+	"\x04etag\x18\x02 \x01(\tR\x04etag\x12!\n" +
+	"\fnot_modified\x18\x03 \x01(\bR\vnotModified2\x93\x10\n" +
 	"\x03IAM\x127\n" +
 	"\n" +
 	"CreateUser\x12\x19.iam.v1.CreateUserRequest\x1a\f.iam.v1.User\"\x00\x121\n" +
-	"\aGetUser\x12\x16.iam.v1.GetUserRequest\x1a\f.iam.v1.User\"\x00\x12=\n" +
+	"\aGetUser\x12\x16.iam.v1.GetUserRequest\x1a\f.iam.v1.User\"\x00\x12O\n" +
+	"\x12CreateLoginProfile\x12!.iam.v1.CreateLoginProfileRequest\x1a\x14.iam.v1.LoginProfile\"\x00\x12O\n" +
+	"\x12UpdateLoginProfile\x12!.iam.v1.UpdateLoginProfileRequest\x1a\x14.iam.v1.LoginProfile\"\x00\x12]\n" +
+	"\x12DeleteLoginProfile\x12!.iam.v1.DeleteLoginProfileRequest\x1a\".iam.v1.DeleteLoginProfileResponse\"\x00\x12K\n" +
+	"\fAuthenticate\x12\x1b.iam.v1.AuthenticateRequest\x1a\x1c.iam.v1.AuthenticateResponse\"\x00\x12T\n" +
+	"\x0fGetSessionToken\x12\x1e.iam.v1.GetSessionTokenRequest\x1a\x1f.iam.v1.GetSessionTokenResponse\"\x00\x12E\n" +
+	"\n" +
+	"UnlockUser\x12\x19.iam.v1.UnlockUserRequest\x1a\x1a.iam.v1.UnlockUserResponse\"\x00\x12T\n" +
+	"\x16CreateVirtualMFADevice\x12%.iam.v1.CreateVirtualMFADeviceRequest\x1a\x11.iam.v1.MFADevice\"\x00\x12F\n" +
+	"\x0fEnableMFADevice\x12\x1e.iam.v1.EnableMFADeviceRequest\x1a\x11.iam.v1.MFADevice\"\x00\x12`\n" +
+	"\x13DeactivateMFADevice\x12\".iam.v1.DeactivateMFADeviceRequest\x1a#.iam.v1.DeactivateMFADeviceResponse\"\x00\x12Q\n" +
+	"\x0eListMFADevices\x12\x1d.iam.v1.ListMFADevicesRequest\x1a\x1e.iam.v1.ListMFADevicesResponse\"\x00\x12=\n" +
 	"\fCreatePolicy\x12\x1b.iam.v1.CreatePolicyRequest\x1a\x0e.iam.v1.Policy\"\x00\x12W\n" +
 	"\x10AttachUserPolicy\x12\x1f.iam.v1.AttachUserPolicyRequest\x1a .iam.v1.AttachUserPolicyResponse\"\x00\x12F\n" +
 	"\x0fCreateAccessKey\x12\x1e.iam.v1.CreateAccessKeyRequest\x1a\x11.iam.v1.AccessKey\"\x00\x12Q\n" +
 	"\x0eListAccessKeys\x12\x1d.iam.v1.ListAccessKeysRequest\x1a\x1e.iam.v1.ListAccessKeysResponse\"\x00\x12R\n" +
-	"\x15UpdateAccessKeyStatus\x12$.iam.v1.UpdateAccessKeyStatusRequest\x1a\x11.iam.v1.AccessKey\"\x00\x12B\n" +
-	"\x0fVerifyAccessKey\x12\x15.iam.v1.VerifyRequest\x1a\x16.iam.v1.VerifyResponse\"\x00\x12T\n" +
-	"\x0fCheckPermission\x12\x1e.iam.v1.CheckPermissionRequest\x1a\x1f.iam.v1.CheckPermissionResponse\"\x00B3Z1github.com/vera-byte/vgo-iam/internal/proto;iamv1b\x06proto3"
+	"\x15UpdateAccessKeyStatus\x12$.iam.v1.UpdateAccessKeyStatusRequest\x1a\x11.iam.v1.AccessKey\"\x00\x12F\n" +
+	"\x0fRotateAccessKey\x12\x1e.iam.v1.RotateAccessKeyRequest\x1a\x11.iam.v1.AccessKey\"\x00\x12T\n" +
+	"\x0fDeleteAccessKey\x12\x1e.iam.v1.DeleteAccessKeyRequest\x1a\x1f.iam.v1.DeleteAccessKeyResponse\"\x00\x12c\n" +
+	"\x14GetAccessKeyLastUsed\x12#.iam.v1.GetAccessKeyLastUsedRequest\x1a$.iam.v1.GetAccessKeyLastUsedResponse\"\x00\x12B\n" +
+	"\x0fVerifyAccessKey\x12\x15.iam.v1.VerifyRequest\x1a\x16.iam.v1.VerifyResponse\"\x00\x12K\n" +
+	"\fVerifyApiKey\x12\x1b.iam.v1.VerifyApiKeyRequest\x1a\x1c.iam.v1.VerifyApiKeyResponse\"\x00\x12N\n" +
+	"\rValidateToken\x12\x1c.iam.v1.ValidateTokenRequest\x1a\x1d.iam.v1.ValidateTokenResponse\"\x00\x12<\n" +
+	"\aGetJWKS\x12\x16.iam.v1.GetJWKSRequest\x1a\x17.iam.v1.GetJWKSResponse\"\x00\x12T\n" +
+	"\x0fCheckPermission\x12\x1e.iam.v1.CheckPermissionRequest\x1a\x1f.iam.v1.CheckPermissionResponse\"\x00\x12c\n" +
+	"\x14GetPrincipalPolicies\x12#.iam.v1.GetPrincipalPoliciesRequest\x1a$.iam.v1.GetPrincipalPoliciesResponse\"\x00B3Z1github.com/vera-byte/vgo-iam/internal/proto;iamv1b\x06proto3"
 
 var (
 	file_proto_iam_proto_rawDescOnce sync.Once
@@ -1042,57 +3145,144 @@ func file_proto_iam_proto_rawDescGZIP() []byte {
 	return file_proto_iam_proto_rawDescData
 }
 
-var file_proto_iam_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_proto_iam_proto_msgTypes = make([]protoimpl.MessageInfo, 51)
 var file_proto_iam_proto_goTypes = []any{
-	(*CreateUserRequest)(nil),            // 0: iam.v1.CreateUserRequest
-	(*GetUserRequest)(nil),               // 1: iam.v1.GetUserRequest
-	(*User)(nil),                         // 2: iam.v1.User
-	(*CreatePolicyRequest)(nil),          // 3: iam.v1.CreatePolicyRequest
-	(*AttachUserPolicyRequest)(nil),      // 4: iam.v1.AttachUserPolicyRequest
-	(*AttachUserPolicyResponse)(nil),     // 5: iam.v1.AttachUserPolicyResponse
-	(*Policy)(nil),                       // 6: iam.v1.Policy
-	(*CreateAccessKeyRequest)(nil),       // 7: iam.v1.CreateAccessKeyRequest
-	(*ListAccessKeysRequest)(nil),        // 8: iam.v1.ListAccessKeysRequest
-	(*UpdateAccessKeyStatusRequest)(nil), // 9: iam.v1.UpdateAccessKeyStatusRequest
-	(*AccessKey)(nil),                    // 10: iam.v1.AccessKey
-	(*ListAccessKeysResponse)(nil),       // 11: iam.v1.ListAccessKeysResponse
-	(*VerifyRequest)(nil),                // 12: iam.v1.VerifyRequest
-	(*VerifyResponse)(nil),               // 13: iam.v1.VerifyResponse
-	(*CheckPermissionRequest)(nil),       // 14: iam.v1.CheckPermissionRequest
-	(*CheckPermissionResponse)(nil),      // 15: iam.v1.CheckPermissionResponse
-	(*timestamppb.Timestamp)(nil),        // 16: google.protobuf.Timestamp
+	(*CreateUserRequest)(nil),             // 0: iam.v1.CreateUserRequest
+	(*GetUserRequest)(nil),                // 1: iam.v1.GetUserRequest
+	(*User)(nil),                          // 2: iam.v1.User
+	(*LoginProfile)(nil),                  // 3: iam.v1.LoginProfile
+	(*CreateLoginProfileRequest)(nil),     // 4: iam.v1.CreateLoginProfileRequest
+	(*UpdateLoginProfileRequest)(nil),     // 5: iam.v1.UpdateLoginProfileRequest
+	(*DeleteLoginProfileRequest)(nil),     // 6: iam.v1.DeleteLoginProfileRequest
+	(*DeleteLoginProfileResponse)(nil),    // 7: iam.v1.DeleteLoginProfileResponse
+	(*AuthenticateRequest)(nil),           // 8: iam.v1.AuthenticateRequest
+	(*AuthenticateResponse)(nil),          // 9: iam.v1.AuthenticateResponse
+	(*UnlockUserRequest)(nil),             // 10: iam.v1.UnlockUserRequest
+	(*UnlockUserResponse)(nil),            // 11: iam.v1.UnlockUserResponse
+	(*GetSessionTokenRequest)(nil),        // 12: iam.v1.GetSessionTokenRequest
+	(*GetSessionTokenResponse)(nil),       // 13: iam.v1.GetSessionTokenResponse
+	(*MFADevice)(nil),                     // 14: iam.v1.MFADevice
+	(*CreateVirtualMFADeviceRequest)(nil), // 15: iam.v1.CreateVirtualMFADeviceRequest
+	(*EnableMFADeviceRequest)(nil),        // 16: iam.v1.EnableMFADeviceRequest
+	(*DeactivateMFADeviceRequest)(nil),    // 17: iam.v1.DeactivateMFADeviceRequest
+	(*DeactivateMFADeviceResponse)(nil),   // 18: iam.v1.DeactivateMFADeviceResponse
+	(*ListMFADevicesRequest)(nil),         // 19: iam.v1.ListMFADevicesRequest
+	(*ListMFADevicesResponse)(nil),        // 20: iam.v1.ListMFADevicesResponse
+	(*CreatePolicyRequest)(nil),           // 21: iam.v1.CreatePolicyRequest
+	(*AttachUserPolicyRequest)(nil),       // 22: iam.v1.AttachUserPolicyRequest
+	(*AttachUserPolicyResponse)(nil),      // 23: iam.v1.AttachUserPolicyResponse
+	(*Policy)(nil),                        // 24: iam.v1.Policy
+	(*CreateAccessKeyRequest)(nil),        // 25: iam.v1.CreateAccessKeyRequest
+	(*ListAccessKeysRequest)(nil),         // 26: iam.v1.ListAccessKeysRequest
+	(*UpdateAccessKeyStatusRequest)(nil),  // 27: iam.v1.UpdateAccessKeyStatusRequest
+	(*RotateAccessKeyRequest)(nil),        // 28: iam.v1.RotateAccessKeyRequest
+	(*DeleteAccessKeyRequest)(nil),        // 29: iam.v1.DeleteAccessKeyRequest
+	(*DeleteAccessKeyResponse)(nil),       // 30: iam.v1.DeleteAccessKeyResponse
+	(*AccessKey)(nil),                     // 31: iam.v1.AccessKey
+	(*AccessKeyLastUsed)(nil),             // 32: iam.v1.AccessKeyLastUsed
+	(*GetAccessKeyLastUsedRequest)(nil),   // 33: iam.v1.GetAccessKeyLastUsedRequest
+	(*GetAccessKeyLastUsedResponse)(nil),  // 34: iam.v1.GetAccessKeyLastUsedResponse
+	(*ListAccessKeysResponse)(nil),        // 35: iam.v1.ListAccessKeysResponse
+	(*VerifyRequest)(nil),                 // 36: iam.v1.VerifyRequest
+	(*VerifyResponse)(nil),                // 37: iam.v1.VerifyResponse
+	(*VerifyApiKeyRequest)(nil),           // 38: iam.v1.VerifyApiKeyRequest
+	(*VerifyApiKeyResponse)(nil),          // 39: iam.v1.VerifyApiKeyResponse
+	(*ValidateTokenRequest)(nil),          // 40: iam.v1.ValidateTokenRequest
+	(*ValidateTokenResponse)(nil),         // 41: iam.v1.ValidateTokenResponse
+	(*GetJWKSRequest)(nil),                // 42: iam.v1.GetJWKSRequest
+	(*JSONWebKey)(nil),                    // 43: iam.v1.JSONWebKey
+	(*GetJWKSResponse)(nil),               // 44: iam.v1.GetJWKSResponse
+	(*CheckPermissionRequest)(nil),        // 45: iam.v1.CheckPermissionRequest
+	(*CheckPermissionResponse)(nil),       // 46: iam.v1.CheckPermissionResponse
+	(*GetPrincipalPoliciesRequest)(nil),   // 47: iam.v1.GetPrincipalPoliciesRequest
+	(*PrincipalPolicy)(nil),               // 48: iam.v1.PrincipalPolicy
+	(*GetPrincipalPoliciesResponse)(nil),  // 49: iam.v1.GetPrincipalPoliciesResponse
+	nil,                                   // 50: iam.v1.CheckPermissionRequest.ContextEntry
+	(*timestamppb.Timestamp)(nil),         // 51: google.protobuf.Timestamp
 }
 var file_proto_iam_proto_depIdxs = []int32{
-	16, // 0: iam.v1.User.created_at:type_name -> google.protobuf.Timestamp
-	16, // 1: iam.v1.User.updated_at:type_name -> google.protobuf.Timestamp
-	16, // 2: iam.v1.Policy.created_at:type_name -> google.protobuf.Timestamp
-	16, // 3: iam.v1.Policy.updated_at:type_name -> google.protobuf.Timestamp
-	16, // 4: iam.v1.AccessKey.created_at:type_name -> google.protobuf.Timestamp
-	16, // 5: iam.v1.AccessKey.updated_at:type_name -> google.protobuf.Timestamp
-	10, // 6: iam.v1.ListAccessKeysResponse.access_keys:type_name -> iam.v1.AccessKey
-	0,  // 7: iam.v1.IAM.CreateUser:input_type -> iam.v1.CreateUserRequest
-	1,  // 8: iam.v1.IAM.GetUser:input_type -> iam.v1.GetUserRequest
-	3,  // 9: iam.v1.IAM.CreatePolicy:input_type -> iam.v1.CreatePolicyRequest
-	4,  // 10: iam.v1.IAM.AttachUserPolicy:input_type -> iam.v1.AttachUserPolicyRequest
-	7,  // 11: iam.v1.IAM.CreateAccessKey:input_type -> iam.v1.CreateAccessKeyRequest
-	8,  // 12: iam.v1.IAM.ListAccessKeys:input_type -> iam.v1.ListAccessKeysRequest
-	9,  // 13: iam.v1.IAM.UpdateAccessKeyStatus:input_type -> iam.v1.UpdateAccessKeyStatusRequest
-	12, // 14: iam.v1.IAM.VerifyAccessKey:input_type -> iam.v1.VerifyRequest
-	14, // 15: iam.v1.IAM.CheckPermission:input_type -> iam.v1.CheckPermissionRequest
-	2,  // 16: iam.v1.IAM.CreateUser:output_type -> iam.v1.User
-	2,  // 17: iam.v1.IAM.GetUser:output_type -> iam.v1.User
-	6,  // 18: iam.v1.IAM.CreatePolicy:output_type -> iam.v1.Policy
-	5,  // 19: iam.v1.IAM.AttachUserPolicy:output_type -> iam.v1.AttachUserPolicyResponse
-	10, // 20: iam.v1.IAM.CreateAccessKey:output_type -> iam.v1.AccessKey
-	11, // 21: iam.v1.IAM.ListAccessKeys:output_type -> iam.v1.ListAccessKeysResponse
-	10, // 22: iam.v1.IAM.UpdateAccessKeyStatus:output_type -> iam.v1.AccessKey
-	13, // 23: iam.v1.IAM.VerifyAccessKey:output_type -> iam.v1.VerifyResponse
-	15, // 24: iam.v1.IAM.CheckPermission:output_type -> iam.v1.CheckPermissionResponse
-	16, // [16:25] is the sub-list for method output_type
-	7,  // [7:16] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	51, // 0: iam.v1.User.created_at:type_name -> google.protobuf.Timestamp
+	51, // 1: iam.v1.User.updated_at:type_name -> google.protobuf.Timestamp
+	51, // 2: iam.v1.LoginProfile.created_at:type_name -> google.protobuf.Timestamp
+	51, // 3: iam.v1.LoginProfile.password_changed_at:type_name -> google.protobuf.Timestamp
+	51, // 4: iam.v1.LoginProfile.password_expires_at:type_name -> google.protobuf.Timestamp
+	51, // 5: iam.v1.AuthenticateResponse.expires_at:type_name -> google.protobuf.Timestamp
+	51, // 6: iam.v1.GetSessionTokenResponse.expires_at:type_name -> google.protobuf.Timestamp
+	51, // 7: iam.v1.MFADevice.created_at:type_name -> google.protobuf.Timestamp
+	51, // 8: iam.v1.MFADevice.enabled_at:type_name -> google.protobuf.Timestamp
+	14, // 9: iam.v1.ListMFADevicesResponse.mfa_devices:type_name -> iam.v1.MFADevice
+	51, // 10: iam.v1.Policy.created_at:type_name -> google.protobuf.Timestamp
+	51, // 11: iam.v1.Policy.updated_at:type_name -> google.protobuf.Timestamp
+	51, // 12: iam.v1.AccessKey.created_at:type_name -> google.protobuf.Timestamp
+	51, // 13: iam.v1.AccessKey.updated_at:type_name -> google.protobuf.Timestamp
+	51, // 14: iam.v1.AccessKey.expires_at:type_name -> google.protobuf.Timestamp
+	51, // 15: iam.v1.AccessKey.previous_secret_expires_at:type_name -> google.protobuf.Timestamp
+	32, // 16: iam.v1.AccessKey.last_used:type_name -> iam.v1.AccessKeyLastUsed
+	51, // 17: iam.v1.AccessKeyLastUsed.last_used_at:type_name -> google.protobuf.Timestamp
+	32, // 18: iam.v1.GetAccessKeyLastUsedResponse.last_used:type_name -> iam.v1.AccessKeyLastUsed
+	31, // 19: iam.v1.ListAccessKeysResponse.access_keys:type_name -> iam.v1.AccessKey
+	51, // 20: iam.v1.ValidateTokenResponse.issued_at:type_name -> google.protobuf.Timestamp
+	51, // 21: iam.v1.ValidateTokenResponse.expires_at:type_name -> google.protobuf.Timestamp
+	43, // 22: iam.v1.GetJWKSResponse.keys:type_name -> iam.v1.JSONWebKey
+	50, // 23: iam.v1.CheckPermissionRequest.context:type_name -> iam.v1.CheckPermissionRequest.ContextEntry
+	48, // 24: iam.v1.GetPrincipalPoliciesResponse.policies:type_name -> iam.v1.PrincipalPolicy
+	0,  // 25: iam.v1.IAM.CreateUser:input_type -> iam.v1.CreateUserRequest
+	1,  // 26: iam.v1.IAM.GetUser:input_type -> iam.v1.GetUserRequest
+	4,  // 27: iam.v1.IAM.CreateLoginProfile:input_type -> iam.v1.CreateLoginProfileRequest
+	5,  // 28: iam.v1.IAM.UpdateLoginProfile:input_type -> iam.v1.UpdateLoginProfileRequest
+	6,  // 29: iam.v1.IAM.DeleteLoginProfile:input_type -> iam.v1.DeleteLoginProfileRequest
+	8,  // 30: iam.v1.IAM.Authenticate:input_type -> iam.v1.AuthenticateRequest
+	12, // 31: iam.v1.IAM.GetSessionToken:input_type -> iam.v1.GetSessionTokenRequest
+	10, // 32: iam.v1.IAM.UnlockUser:input_type -> iam.v1.UnlockUserRequest
+	15, // 33: iam.v1.IAM.CreateVirtualMFADevice:input_type -> iam.v1.CreateVirtualMFADeviceRequest
+	16, // 34: iam.v1.IAM.EnableMFADevice:input_type -> iam.v1.EnableMFADeviceRequest
+	17, // 35: iam.v1.IAM.DeactivateMFADevice:input_type -> iam.v1.DeactivateMFADeviceRequest
+	19, // 36: iam.v1.IAM.ListMFADevices:input_type -> iam.v1.ListMFADevicesRequest
+	21, // 37: iam.v1.IAM.CreatePolicy:input_type -> iam.v1.CreatePolicyRequest
+	22, // 38: iam.v1.IAM.AttachUserPolicy:input_type -> iam.v1.AttachUserPolicyRequest
+	25, // 39: iam.v1.IAM.CreateAccessKey:input_type -> iam.v1.CreateAccessKeyRequest
+	26, // 40: iam.v1.IAM.ListAccessKeys:input_type -> iam.v1.ListAccessKeysRequest
+	27, // 41: iam.v1.IAM.UpdateAccessKeyStatus:input_type -> iam.v1.UpdateAccessKeyStatusRequest
+	28, // 42: iam.v1.IAM.RotateAccessKey:input_type -> iam.v1.RotateAccessKeyRequest
+	29, // 43: iam.v1.IAM.DeleteAccessKey:input_type -> iam.v1.DeleteAccessKeyRequest
+	33, // 44: iam.v1.IAM.GetAccessKeyLastUsed:input_type -> iam.v1.GetAccessKeyLastUsedRequest
+	36, // 45: iam.v1.IAM.VerifyAccessKey:input_type -> iam.v1.VerifyRequest
+	38, // 46: iam.v1.IAM.VerifyApiKey:input_type -> iam.v1.VerifyApiKeyRequest
+	40, // 47: iam.v1.IAM.ValidateToken:input_type -> iam.v1.ValidateTokenRequest
+	42, // 48: iam.v1.IAM.GetJWKS:input_type -> iam.v1.GetJWKSRequest
+	45, // 49: iam.v1.IAM.CheckPermission:input_type -> iam.v1.CheckPermissionRequest
+	47, // 50: iam.v1.IAM.GetPrincipalPolicies:input_type -> iam.v1.GetPrincipalPoliciesRequest
+	2,  // 51: iam.v1.IAM.CreateUser:output_type -> iam.v1.User
+	2,  // 52: iam.v1.IAM.GetUser:output_type -> iam.v1.User
+	3,  // 53: iam.v1.IAM.CreateLoginProfile:output_type -> iam.v1.LoginProfile
+	3,  // 54: iam.v1.IAM.UpdateLoginProfile:output_type -> iam.v1.LoginProfile
+	7,  // 55: iam.v1.IAM.DeleteLoginProfile:output_type -> iam.v1.DeleteLoginProfileResponse
+	9,  // 56: iam.v1.IAM.Authenticate:output_type -> iam.v1.AuthenticateResponse
+	13, // 57: iam.v1.IAM.GetSessionToken:output_type -> iam.v1.GetSessionTokenResponse
+	11, // 58: iam.v1.IAM.UnlockUser:output_type -> iam.v1.UnlockUserResponse
+	14, // 59: iam.v1.IAM.CreateVirtualMFADevice:output_type -> iam.v1.MFADevice
+	14, // 60: iam.v1.IAM.EnableMFADevice:output_type -> iam.v1.MFADevice
+	18, // 61: iam.v1.IAM.DeactivateMFADevice:output_type -> iam.v1.DeactivateMFADeviceResponse
+	20, // 62: iam.v1.IAM.ListMFADevices:output_type -> iam.v1.ListMFADevicesResponse
+	24, // 63: iam.v1.IAM.CreatePolicy:output_type -> iam.v1.Policy
+	23, // 64: iam.v1.IAM.AttachUserPolicy:output_type -> iam.v1.AttachUserPolicyResponse
+	31, // 65: iam.v1.IAM.CreateAccessKey:output_type -> iam.v1.AccessKey
+	35, // 66: iam.v1.IAM.ListAccessKeys:output_type -> iam.v1.ListAccessKeysResponse
+	31, // 67: iam.v1.IAM.UpdateAccessKeyStatus:output_type -> iam.v1.AccessKey
+	31, // 68: iam.v1.IAM.RotateAccessKey:output_type -> iam.v1.AccessKey
+	30, // 69: iam.v1.IAM.DeleteAccessKey:output_type -> iam.v1.DeleteAccessKeyResponse
+	34, // 70: iam.v1.IAM.GetAccessKeyLastUsed:output_type -> iam.v1.GetAccessKeyLastUsedResponse
+	37, // 71: iam.v1.IAM.VerifyAccessKey:output_type -> iam.v1.VerifyResponse
+	39, // 72: iam.v1.IAM.VerifyApiKey:output_type -> iam.v1.VerifyApiKeyResponse
+	41, // 73: iam.v1.IAM.ValidateToken:output_type -> iam.v1.ValidateTokenResponse
+	44, // 74: iam.v1.IAM.GetJWKS:output_type -> iam.v1.GetJWKSResponse
+	46, // 75: iam.v1.IAM.CheckPermission:output_type -> iam.v1.CheckPermissionResponse
+	49, // 76: iam.v1.IAM.GetPrincipalPolicies:output_type -> iam.v1.GetPrincipalPoliciesResponse
+	51, // [51:77] is the sub-list for method output_type
+	25, // [25:51] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
 }
 
 func init() { file_proto_iam_proto_init() }
@@ -1100,13 +3290,14 @@ func file_proto_iam_proto_init() {
 	if File_proto_iam_proto != nil {
 		return
 	}
+	file_proto_iam_proto_msgTypes[28].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_iam_proto_rawDesc), len(file_proto_iam_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   16,
+			NumMessages:   51,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,15 +19,32 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	IAM_CreateUser_FullMethodName            = "/iam.v1.IAM/CreateUser"
-	IAM_GetUser_FullMethodName               = "/iam.v1.IAM/GetUser"
-	IAM_CreatePolicy_FullMethodName          = "/iam.v1.IAM/CreatePolicy"
-	IAM_AttachUserPolicy_FullMethodName      = "/iam.v1.IAM/AttachUserPolicy"
-	IAM_CreateAccessKey_FullMethodName       = "/iam.v1.IAM/CreateAccessKey"
-	IAM_ListAccessKeys_FullMethodName        = "/iam.v1.IAM/ListAccessKeys"
-	IAM_UpdateAccessKeyStatus_FullMethodName = "/iam.v1.IAM/UpdateAccessKeyStatus"
-	IAM_VerifyAccessKey_FullMethodName       = "/iam.v1.IAM/VerifyAccessKey"
-	IAM_CheckPermission_FullMethodName       = "/iam.v1.IAM/CheckPermission"
+	IAM_CreateUser_FullMethodName             = "/iam.v1.IAM/CreateUser"
+	IAM_GetUser_FullMethodName                = "/iam.v1.IAM/GetUser"
+	IAM_CreateLoginProfile_FullMethodName     = "/iam.v1.IAM/CreateLoginProfile"
+	IAM_UpdateLoginProfile_FullMethodName     = "/iam.v1.IAM/UpdateLoginProfile"
+	IAM_DeleteLoginProfile_FullMethodName     = "/iam.v1.IAM/DeleteLoginProfile"
+	IAM_Authenticate_FullMethodName           = "/iam.v1.IAM/Authenticate"
+	IAM_GetSessionToken_FullMethodName        = "/iam.v1.IAM/GetSessionToken"
+	IAM_UnlockUser_FullMethodName             = "/iam.v1.IAM/UnlockUser"
+	IAM_CreateVirtualMFADevice_FullMethodName = "/iam.v1.IAM/CreateVirtualMFADevice"
+	IAM_EnableMFADevice_FullMethodName        = "/iam.v1.IAM/EnableMFADevice"
+	IAM_DeactivateMFADevice_FullMethodName    = "/iam.v1.IAM/DeactivateMFADevice"
+	IAM_ListMFADevices_FullMethodName         = "/iam.v1.IAM/ListMFADevices"
+	IAM_CreatePolicy_FullMethodName           = "/iam.v1.IAM/CreatePolicy"
+	IAM_AttachUserPolicy_FullMethodName       = "/iam.v1.IAM/AttachUserPolicy"
+	IAM_CreateAccessKey_FullMethodName        = "/iam.v1.IAM/CreateAccessKey"
+	IAM_ListAccessKeys_FullMethodName         = "/iam.v1.IAM/ListAccessKeys"
+	IAM_UpdateAccessKeyStatus_FullMethodName  = "/iam.v1.IAM/UpdateAccessKeyStatus"
+	IAM_RotateAccessKey_FullMethodName        = "/iam.v1.IAM/RotateAccessKey"
+	IAM_DeleteAccessKey_FullMethodName        = "/iam.v1.IAM/DeleteAccessKey"
+	IAM_GetAccessKeyLastUsed_FullMethodName   = "/iam.v1.IAM/GetAccessKeyLastUsed"
+	IAM_VerifyAccessKey_FullMethodName        = "/iam.v1.IAM/VerifyAccessKey"
+	IAM_VerifyApiKey_FullMethodName           = "/iam.v1.IAM/VerifyApiKey"
+	IAM_ValidateToken_FullMethodName          = "/iam.v1.IAM/ValidateToken"
+	IAM_GetJWKS_FullMethodName                = "/iam.v1.IAM/GetJWKS"
+	IAM_CheckPermission_FullMethodName        = "/iam.v1.IAM/CheckPermission"
+	IAM_GetPrincipalPolicies_FullMethodName   = "/iam.v1.IAM/GetPrincipalPolicies"
 )
 
 // IAMClient is the client API for IAM service.
@@ -37,6 +54,21 @@ type IAMClient interface {
 	// 用户管理
 	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error)
 	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error)
+	// 控制台登录
+	CreateLoginProfile(ctx context.Context, in *CreateLoginProfileRequest, opts ...grpc.CallOption) (*LoginProfile, error)
+	UpdateLoginProfile(ctx context.Context, in *UpdateLoginProfileRequest, opts ...grpc.CallOption) (*LoginProfile, error)
+	DeleteLoginProfile(ctx context.Context, in *DeleteLoginProfileRequest, opts ...grpc.CallOption) (*DeleteLoginProfileResponse, error)
+	// 校验用户名和密码，返回会话令牌
+	Authenticate(ctx context.Context, in *AuthenticateRequest, opts ...grpc.CallOption) (*AuthenticateResponse, error)
+	// 使用访问密钥签名的请求换取会话令牌，附带MFA验证码时会话满足MFA条件
+	GetSessionToken(ctx context.Context, in *GetSessionTokenRequest, opts ...grpc.CallOption) (*GetSessionTokenResponse, error)
+	// 解除用户及其API密钥因认证失败过多造成的锁定
+	UnlockUser(ctx context.Context, in *UnlockUserRequest, opts ...grpc.CallOption) (*UnlockUserResponse, error)
+	// 虚拟MFA设备
+	CreateVirtualMFADevice(ctx context.Context, in *CreateVirtualMFADeviceRequest, opts ...grpc.CallOption) (*MFADevice, error)
+	EnableMFADevice(ctx context.Context, in *EnableMFADeviceRequest, opts ...grpc.CallOption) (*MFADevice, error)
+	DeactivateMFADevice(ctx context.Context, in *DeactivateMFADeviceRequest, opts ...grpc.CallOption) (*DeactivateMFADeviceResponse, error)
+	ListMFADevices(ctx context.Context, in *ListMFADevicesRequest, opts ...grpc.CallOption) (*ListMFADevicesResponse, error)
 	// 策略管理
 	CreatePolicy(ctx context.Context, in *CreatePolicyRequest, opts ...grpc.CallOption) (*Policy, error)
 	AttachUserPolicy(ctx context.Context, in *AttachUserPolicyRequest, opts ...grpc.CallOption) (*AttachUserPolicyResponse, error)
@@ -44,9 +76,20 @@ type IAMClient interface {
 	CreateAccessKey(ctx context.Context, in *CreateAccessKeyRequest, opts ...grpc.CallOption) (*AccessKey, error)
 	ListAccessKeys(ctx context.Context, in *ListAccessKeysRequest, opts ...grpc.CallOption) (*ListAccessKeysResponse, error)
 	UpdateAccessKeyStatus(ctx context.Context, in *UpdateAccessKeyStatusRequest, opts ...grpc.CallOption) (*AccessKey, error)
+	RotateAccessKey(ctx context.Context, in *RotateAccessKeyRequest, opts ...grpc.CallOption) (*AccessKey, error)
+	DeleteAccessKey(ctx context.Context, in *DeleteAccessKeyRequest, opts ...grpc.CallOption) (*DeleteAccessKeyResponse, error)
+	GetAccessKeyLastUsed(ctx context.Context, in *GetAccessKeyLastUsedRequest, opts ...grpc.CallOption) (*GetAccessKeyLastUsedResponse, error)
 	// 权限验证
 	VerifyAccessKey(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error)
+	// 校验api_key类型凭证的令牌
+	VerifyApiKey(ctx context.Context, in *VerifyApiKeyRequest, opts ...grpc.CallOption) (*VerifyApiKeyResponse, error)
+	// 校验OAuth2令牌端点签发的访问令牌(JWT)
+	ValidateToken(ctx context.Context, in *ValidateTokenRequest, opts ...grpc.CallOption) (*ValidateTokenResponse, error)
+	// 获取校验访问令牌的公钥(JWKS)，供下游服务在本地校验令牌签名
+	GetJWKS(ctx context.Context, in *GetJWKSRequest, opts ...grpc.CallOption) (*GetJWKSResponse, error)
 	CheckPermission(ctx context.Context, in *CheckPermissionRequest, opts ...grpc.CallOption) (*CheckPermissionResponse, error)
+	// 获取用户的全部策略，供下游服务在本地评估权限
+	GetPrincipalPolicies(ctx context.Context, in *GetPrincipalPoliciesRequest, opts ...grpc.CallOption) (*GetPrincipalPoliciesResponse, error)
 }
 
 type iAMClient struct {
@@ -77,6 +120,106 @@ func (c *iAMClient) GetUser(ctx context.Context, in *GetUserRequest, opts ...grp
 	return out, nil
 }
 
+func (c *iAMClient) CreateLoginProfile(ctx context.Context, in *CreateLoginProfileRequest, opts ...grpc.CallOption) (*LoginProfile, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoginProfile)
+	err := c.cc.Invoke(ctx, IAM_CreateLoginProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iAMClient) UpdateLoginProfile(ctx context.Context, in *UpdateLoginProfileRequest, opts ...grpc.CallOption) (*LoginProfile, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoginProfile)
+	err := c.cc.Invoke(ctx, IAM_UpdateLoginProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iAMClient) DeleteLoginProfile(ctx context.Context, in *DeleteLoginProfileRequest, opts ...grpc.CallOption) (*DeleteLoginProfileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteLoginProfileResponse)
+	err := c.cc.Invoke(ctx, IAM_DeleteLoginProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iAMClient) Authenticate(ctx context.Context, in *AuthenticateRequest, opts ...grpc.CallOption) (*AuthenticateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthenticateResponse)
+	err := c.cc.Invoke(ctx, IAM_Authenticate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iAMClient) GetSessionToken(ctx context.Context, in *GetSessionTokenRequest, opts ...grpc.CallOption) (*GetSessionTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSessionTokenResponse)
+	err := c.cc.Invoke(ctx, IAM_GetSessionToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iAMClient) UnlockUser(ctx context.Context, in *UnlockUserRequest, opts ...grpc.CallOption) (*UnlockUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnlockUserResponse)
+	err := c.cc.Invoke(ctx, IAM_UnlockUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iAMClient) CreateVirtualMFADevice(ctx context.Context, in *CreateVirtualMFADeviceRequest, opts ...grpc.CallOption) (*MFADevice, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MFADevice)
+	err := c.cc.Invoke(ctx, IAM_CreateVirtualMFADevice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iAMClient) EnableMFADevice(ctx context.Context, in *EnableMFADeviceRequest, opts ...grpc.CallOption) (*MFADevice, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MFADevice)
+	err := c.cc.Invoke(ctx, IAM_EnableMFADevice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iAMClient) DeactivateMFADevice(ctx context.Context, in *DeactivateMFADeviceRequest, opts ...grpc.CallOption) (*DeactivateMFADeviceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeactivateMFADeviceResponse)
+	err := c.cc.Invoke(ctx, IAM_DeactivateMFADevice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iAMClient) ListMFADevices(ctx context.Context, in *ListMFADevicesRequest, opts ...grpc.CallOption) (*ListMFADevicesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMFADevicesResponse)
+	err := c.cc.Invoke(ctx, IAM_ListMFADevices_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *iAMClient) CreatePolicy(ctx context.Context, in *CreatePolicyRequest, opts ...grpc.CallOption) (*Policy, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Policy)
@@ -127,6 +270,36 @@ func (c *iAMClient) UpdateAccessKeyStatus(ctx context.Context, in *UpdateAccessK
 	return out, nil
 }
 
+func (c *iAMClient) RotateAccessKey(ctx context.Context, in *RotateAccessKeyRequest, opts ...grpc.CallOption) (*AccessKey, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccessKey)
+	err := c.cc.Invoke(ctx, IAM_RotateAccessKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iAMClient) DeleteAccessKey(ctx context.Context, in *DeleteAccessKeyRequest, opts ...grpc.CallOption) (*DeleteAccessKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteAccessKeyResponse)
+	err := c.cc.Invoke(ctx, IAM_DeleteAccessKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iAMClient) GetAccessKeyLastUsed(ctx context.Context, in *GetAccessKeyLastUsedRequest, opts ...grpc.CallOption) (*GetAccessKeyLastUsedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAccessKeyLastUsedResponse)
+	err := c.cc.Invoke(ctx, IAM_GetAccessKeyLastUsed_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *iAMClient) VerifyAccessKey(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(VerifyResponse)
@@ -137,6 +310,36 @@ func (c *iAMClient) VerifyAccessKey(ctx context.Context, in *VerifyRequest, opts
 	return out, nil
 }
 
+func (c *iAMClient) VerifyApiKey(ctx context.Context, in *VerifyApiKeyRequest, opts ...grpc.CallOption) (*VerifyApiKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyApiKeyResponse)
+	err := c.cc.Invoke(ctx, IAM_VerifyApiKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iAMClient) ValidateToken(ctx context.Context, in *ValidateTokenRequest, opts ...grpc.CallOption) (*ValidateTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateTokenResponse)
+	err := c.cc.Invoke(ctx, IAM_ValidateToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iAMClient) GetJWKS(ctx context.Context, in *GetJWKSRequest, opts ...grpc.CallOption) (*GetJWKSResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetJWKSResponse)
+	err := c.cc.Invoke(ctx, IAM_GetJWKS_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *iAMClient) CheckPermission(ctx context.Context, in *CheckPermissionRequest, opts ...grpc.CallOption) (*CheckPermissionResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CheckPermissionResponse)
@@ -147,6 +350,16 @@ func (c *iAMClient) CheckPermission(ctx context.Context, in *CheckPermissionRequ
 	return out, nil
 }
 
+func (c *iAMClient) GetPrincipalPolicies(ctx context.Context, in *GetPrincipalPoliciesRequest, opts ...grpc.CallOption) (*GetPrincipalPoliciesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPrincipalPoliciesResponse)
+	err := c.cc.Invoke(ctx, IAM_GetPrincipalPolicies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // IAMServer is the server API for IAM service.
 // All implementations should embed UnimplementedIAMServer
 // for forward compatibility.
@@ -154,6 +367,21 @@ type IAMServer interface {
 	// 用户管理
 	CreateUser(context.Context, *CreateUserRequest) (*User, error)
 	GetUser(context.Context, *GetUserRequest) (*User, error)
+	// 控制台登录
+	CreateLoginProfile(context.Context, *CreateLoginProfileRequest) (*LoginProfile, error)
+	UpdateLoginProfile(context.Context, *UpdateLoginProfileRequest) (*LoginProfile, error)
+	DeleteLoginProfile(context.Context, *DeleteLoginProfileRequest) (*DeleteLoginProfileResponse, error)
+	// 校验用户名和密码，返回会话令牌
+	Authenticate(context.Context, *AuthenticateRequest) (*AuthenticateResponse, error)
+	// 使用访问密钥签名的请求换取会话令牌，附带MFA验证码时会话满足MFA条件
+	GetSessionToken(context.Context, *GetSessionTokenRequest) (*GetSessionTokenResponse, error)
+	// 解除用户及其API密钥因认证失败过多造成的锁定
+	UnlockUser(context.Context, *UnlockUserRequest) (*UnlockUserResponse, error)
+	// 虚拟MFA设备
+	CreateVirtualMFADevice(context.Context, *CreateVirtualMFADeviceRequest) (*MFADevice, error)
+	EnableMFADevice(context.Context, *EnableMFADeviceRequest) (*MFADevice, error)
+	DeactivateMFADevice(context.Context, *DeactivateMFADeviceRequest) (*DeactivateMFADeviceResponse, error)
+	ListMFADevices(context.Context, *ListMFADevicesRequest) (*ListMFADevicesResponse, error)
 	// 策略管理
 	CreatePolicy(context.Context, *CreatePolicyRequest) (*Policy, error)
 	AttachUserPolicy(context.Context, *AttachUserPolicyRequest) (*AttachUserPolicyResponse, error)
@@ -161,9 +389,20 @@ type IAMServer interface {
 	CreateAccessKey(context.Context, *CreateAccessKeyRequest) (*AccessKey, error)
 	ListAccessKeys(context.Context, *ListAccessKeysRequest) (*ListAccessKeysResponse, error)
 	UpdateAccessKeyStatus(context.Context, *UpdateAccessKeyStatusRequest) (*AccessKey, error)
+	RotateAccessKey(context.Context, *RotateAccessKeyRequest) (*AccessKey, error)
+	DeleteAccessKey(context.Context, *DeleteAccessKeyRequest) (*DeleteAccessKeyResponse, error)
+	GetAccessKeyLastUsed(context.Context, *GetAccessKeyLastUsedRequest) (*GetAccessKeyLastUsedResponse, error)
 	// 权限验证
 	VerifyAccessKey(context.Context, *VerifyRequest) (*VerifyResponse, error)
+	// 校验api_key类型凭证的令牌
+	VerifyApiKey(context.Context, *VerifyApiKeyRequest) (*VerifyApiKeyResponse, error)
+	// 校验OAuth2令牌端点签发的访问令牌(JWT)
+	ValidateToken(context.Context, *ValidateTokenRequest) (*ValidateTokenResponse, error)
+	// 获取校验访问令牌的公钥(JWKS)，供下游服务在本地校验令牌签名
+	GetJWKS(context.Context, *GetJWKSRequest) (*GetJWKSResponse, error)
 	CheckPermission(context.Context, *CheckPermissionRequest) (*CheckPermissionResponse, error)
+	// 获取用户的全部策略，供下游服务在本地评估权限
+	GetPrincipalPolicies(context.Context, *GetPrincipalPoliciesRequest) (*GetPrincipalPoliciesResponse, error)
 }
 
 // UnimplementedIAMServer should be embedded to have
@@ -179,6 +418,36 @@ func (UnimplementedIAMServer) CreateUser(context.Context, *CreateUserRequest) (*
 func (UnimplementedIAMServer) GetUser(context.Context, *GetUserRequest) (*User, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
 }
+func (UnimplementedIAMServer) CreateLoginProfile(context.Context, *CreateLoginProfileRequest) (*LoginProfile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateLoginProfile not implemented")
+}
+func (UnimplementedIAMServer) UpdateLoginProfile(context.Context, *UpdateLoginProfileRequest) (*LoginProfile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateLoginProfile not implemented")
+}
+func (UnimplementedIAMServer) DeleteLoginProfile(context.Context, *DeleteLoginProfileRequest) (*DeleteLoginProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteLoginProfile not implemented")
+}
+func (UnimplementedIAMServer) Authenticate(context.Context, *AuthenticateRequest) (*AuthenticateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Authenticate not implemented")
+}
+func (UnimplementedIAMServer) GetSessionToken(context.Context, *GetSessionTokenRequest) (*GetSessionTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSessionToken not implemented")
+}
+func (UnimplementedIAMServer) UnlockUser(context.Context, *UnlockUserRequest) (*UnlockUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlockUser not implemented")
+}
+func (UnimplementedIAMServer) CreateVirtualMFADevice(context.Context, *CreateVirtualMFADeviceRequest) (*MFADevice, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateVirtualMFADevice not implemented")
+}
+func (UnimplementedIAMServer) EnableMFADevice(context.Context, *EnableMFADeviceRequest) (*MFADevice, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnableMFADevice not implemented")
+}
+func (UnimplementedIAMServer) DeactivateMFADevice(context.Context, *DeactivateMFADeviceRequest) (*DeactivateMFADeviceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeactivateMFADevice not implemented")
+}
+func (UnimplementedIAMServer) ListMFADevices(context.Context, *ListMFADevicesRequest) (*ListMFADevicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMFADevices not implemented")
+}
 func (UnimplementedIAMServer) CreatePolicy(context.Context, *CreatePolicyRequest) (*Policy, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreatePolicy not implemented")
 }
@@ -194,12 +463,33 @@ func (UnimplementedIAMServer) ListAccessKeys(context.Context, *ListAccessKeysReq
 func (UnimplementedIAMServer) UpdateAccessKeyStatus(context.Context, *UpdateAccessKeyStatusRequest) (*AccessKey, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateAccessKeyStatus not implemented")
 }
+func (UnimplementedIAMServer) RotateAccessKey(context.Context, *RotateAccessKeyRequest) (*AccessKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateAccessKey not implemented")
+}
+func (UnimplementedIAMServer) DeleteAccessKey(context.Context, *DeleteAccessKeyRequest) (*DeleteAccessKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAccessKey not implemented")
+}
+func (UnimplementedIAMServer) GetAccessKeyLastUsed(context.Context, *GetAccessKeyLastUsedRequest) (*GetAccessKeyLastUsedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccessKeyLastUsed not implemented")
+}
 func (UnimplementedIAMServer) VerifyAccessKey(context.Context, *VerifyRequest) (*VerifyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method VerifyAccessKey not implemented")
 }
+func (UnimplementedIAMServer) VerifyApiKey(context.Context, *VerifyApiKeyRequest) (*VerifyApiKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyApiKey not implemented")
+}
+func (UnimplementedIAMServer) ValidateToken(context.Context, *ValidateTokenRequest) (*ValidateTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateToken not implemented")
+}
+func (UnimplementedIAMServer) GetJWKS(context.Context, *GetJWKSRequest) (*GetJWKSResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJWKS not implemented")
+}
 func (UnimplementedIAMServer) CheckPermission(context.Context, *CheckPermissionRequest) (*CheckPermissionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CheckPermission not implemented")
 }
+func (UnimplementedIAMServer) GetPrincipalPolicies(context.Context, *GetPrincipalPoliciesRequest) (*GetPrincipalPoliciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPrincipalPolicies not implemented")
+}
 func (UnimplementedIAMServer) testEmbeddedByValue() {}
 
 // UnsafeIAMServer may be embedded to opt out of forward compatibility for this service.
@@ -256,6 +546,186 @@ func _IAM_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IAM_CreateLoginProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateLoginProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IAMServer).CreateLoginProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IAM_CreateLoginProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IAMServer).CreateLoginProfile(ctx, req.(*CreateLoginProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IAM_UpdateLoginProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateLoginProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IAMServer).UpdateLoginProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IAM_UpdateLoginProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IAMServer).UpdateLoginProfile(ctx, req.(*UpdateLoginProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IAM_DeleteLoginProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteLoginProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IAMServer).DeleteLoginProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IAM_DeleteLoginProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IAMServer).DeleteLoginProfile(ctx, req.(*DeleteLoginProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IAM_Authenticate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthenticateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IAMServer).Authenticate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IAM_Authenticate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IAMServer).Authenticate(ctx, req.(*AuthenticateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IAM_GetSessionToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSessionTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IAMServer).GetSessionToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IAM_GetSessionToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IAMServer).GetSessionToken(ctx, req.(*GetSessionTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IAM_UnlockUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IAMServer).UnlockUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IAM_UnlockUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IAMServer).UnlockUser(ctx, req.(*UnlockUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IAM_CreateVirtualMFADevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateVirtualMFADeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IAMServer).CreateVirtualMFADevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IAM_CreateVirtualMFADevice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IAMServer).CreateVirtualMFADevice(ctx, req.(*CreateVirtualMFADeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IAM_EnableMFADevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnableMFADeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IAMServer).EnableMFADevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IAM_EnableMFADevice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IAMServer).EnableMFADevice(ctx, req.(*EnableMFADeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IAM_DeactivateMFADevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeactivateMFADeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IAMServer).DeactivateMFADevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IAM_DeactivateMFADevice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IAMServer).DeactivateMFADevice(ctx, req.(*DeactivateMFADeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IAM_ListMFADevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMFADevicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IAMServer).ListMFADevices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IAM_ListMFADevices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IAMServer).ListMFADevices(ctx, req.(*ListMFADevicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _IAM_CreatePolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreatePolicyRequest)
 	if err := dec(in); err != nil {
@@ -346,6 +816,60 @@ func _IAM_UpdateAccessKeyStatus_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IAM_RotateAccessKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateAccessKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IAMServer).RotateAccessKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IAM_RotateAccessKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IAMServer).RotateAccessKey(ctx, req.(*RotateAccessKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IAM_DeleteAccessKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAccessKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IAMServer).DeleteAccessKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IAM_DeleteAccessKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IAMServer).DeleteAccessKey(ctx, req.(*DeleteAccessKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IAM_GetAccessKeyLastUsed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccessKeyLastUsedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IAMServer).GetAccessKeyLastUsed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IAM_GetAccessKeyLastUsed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IAMServer).GetAccessKeyLastUsed(ctx, req.(*GetAccessKeyLastUsedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _IAM_VerifyAccessKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(VerifyRequest)
 	if err := dec(in); err != nil {
@@ -364,6 +888,60 @@ func _IAM_VerifyAccessKey_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IAM_VerifyApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyApiKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IAMServer).VerifyApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IAM_VerifyApiKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IAMServer).VerifyApiKey(ctx, req.(*VerifyApiKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IAM_ValidateToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IAMServer).ValidateToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IAM_ValidateToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IAMServer).ValidateToken(ctx, req.(*ValidateTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IAM_GetJWKS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJWKSRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IAMServer).GetJWKS(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IAM_GetJWKS_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IAMServer).GetJWKS(ctx, req.(*GetJWKSRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _IAM_CheckPermission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CheckPermissionRequest)
 	if err := dec(in); err != nil {
@@ -382,6 +960,24 @@ func _IAM_CheckPermission_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IAM_GetPrincipalPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPrincipalPoliciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IAMServer).GetPrincipalPolicies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IAM_GetPrincipalPolicies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IAMServer).GetPrincipalPolicies(ctx, req.(*GetPrincipalPoliciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // IAM_ServiceDesc is the grpc.ServiceDesc for IAM service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -397,6 +993,46 @@ var IAM_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUser",
 			Handler:    _IAM_GetUser_Handler,
 		},
+		{
+			MethodName: "CreateLoginProfile",
+			Handler:    _IAM_CreateLoginProfile_Handler,
+		},
+		{
+			MethodName: "UpdateLoginProfile",
+			Handler:    _IAM_UpdateLoginProfile_Handler,
+		},
+		{
+			MethodName: "DeleteLoginProfile",
+			Handler:    _IAM_DeleteLoginProfile_Handler,
+		},
+		{
+			MethodName: "Authenticate",
+			Handler:    _IAM_Authenticate_Handler,
+		},
+		{
+			MethodName: "GetSessionToken",
+			Handler:    _IAM_GetSessionToken_Handler,
+		},
+		{
+			MethodName: "UnlockUser",
+			Handler:    _IAM_UnlockUser_Handler,
+		},
+		{
+			MethodName: "CreateVirtualMFADevice",
+			Handler:    _IAM_CreateVirtualMFADevice_Handler,
+		},
+		{
+			MethodName: "EnableMFADevice",
+			Handler:    _IAM_EnableMFADevice_Handler,
+		},
+		{
+			MethodName: "DeactivateMFADevice",
+			Handler:    _IAM_DeactivateMFADevice_Handler,
+		},
+		{
+			MethodName: "ListMFADevices",
+			Handler:    _IAM_ListMFADevices_Handler,
+		},
 		{
 			MethodName: "CreatePolicy",
 			Handler:    _IAM_CreatePolicy_Handler,
@@ -417,14 +1053,42 @@ var IAM_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateAccessKeyStatus",
 			Handler:    _IAM_UpdateAccessKeyStatus_Handler,
 		},
+		{
+			MethodName: "RotateAccessKey",
+			Handler:    _IAM_RotateAccessKey_Handler,
+		},
+		{
+			MethodName: "DeleteAccessKey",
+			Handler:    _IAM_DeleteAccessKey_Handler,
+		},
+		{
+			MethodName: "GetAccessKeyLastUsed",
+			Handler:    _IAM_GetAccessKeyLastUsed_Handler,
+		},
 		{
 			MethodName: "VerifyAccessKey",
 			Handler:    _IAM_VerifyAccessKey_Handler,
 		},
+		{
+			MethodName: "VerifyApiKey",
+			Handler:    _IAM_VerifyApiKey_Handler,
+		},
+		{
+			MethodName: "ValidateToken",
+			Handler:    _IAM_ValidateToken_Handler,
+		},
+		{
+			MethodName: "GetJWKS",
+			Handler:    _IAM_GetJWKS_Handler,
+		},
 		{
 			MethodName: "CheckPermission",
 			Handler:    _IAM_CheckPermission_Handler,
 		},
+		{
+			MethodName: "GetPrincipalPolicies",
+			Handler:    _IAM_GetPrincipalPolicies_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/iam.proto",
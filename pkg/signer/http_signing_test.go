@@ -0,0 +1,85 @@
+package signer
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCanonicalURIAndQuery(t *testing.T) {
+	if got := CanonicalURI("/orders/a b/ü"); got != "/orders/a%20b/%C3%BC" {
+		t.Errorf("CanonicalURI = %s", got)
+	}
+	if got := CanonicalURI(""); got != "/" {
+		t.Errorf("CanonicalURI(\"\") = %s", got)
+	}
+
+	query, _ := url.ParseQuery("b=2&a=z&a=y&c=x%2Fy+z")
+	if got := CanonicalQueryString(query); got != "a=y&a=z&b=2&c=x%2Fy%20z" {
+		t.Errorf("CanonicalQueryString = %s", got)
+	}
+}
+
+func TestHTTPSignatureRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(r *http.Request)
+		valid  bool
+	}{
+		{"unmodified", func(r *http.Request) {}, true},
+		{"query changed", func(r *http.Request) { r.URL.RawQuery = "a=1&b=3" }, false},
+		{"path changed", func(r *http.Request) { r.URL.Path = "/v1/admin" }, false},
+		{"method changed", func(r *http.Request) { r.Method = "DELETE" }, false},
+		{"nonce changed", func(r *http.Request) { r.Header.Set(HeaderNonce, "nonce-2") }, false},
+		{"body changed", func(r *http.Request) {
+			r.Body = io.NopCloser(strings.NewReader(`{"name":"mallory"}`))
+		}, false},
+		{"body and content hash changed", func(r *http.Request) {
+			r.Body = io.NopCloser(strings.NewReader(`{"name":"mallory"}`))
+			r.Header.Set(HeaderContentSHA256, HashPayload([]byte(`{"name":"mallory"}`)))
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "http://orders.internal/v1/orders?b=2&a=1", strings.NewReader(`{"name":"alice"}`))
+			r.Header.Set(HeaderNonce, "nonce-1")
+			if err := SignHTTPRequest(r, vectorAccessKeyID, vectorSecret, "orders", vectorTime); err != nil {
+				t.Fatalf("SignHTTPRequest failed: %v", err)
+			}
+			tt.tamper(r)
+
+			if got := verifyHTTPRequest(r); got != tt.valid {
+				t.Errorf("verification = %v, want %v", got, tt.valid)
+			}
+		})
+	}
+}
+
+// verifyHTTPRequest 按服务端流程重新计算规范请求并校验签名
+func verifyHTTPRequest(r *http.Request) bool {
+	a, timestamp, err := ParseRequest(r)
+	if err != nil {
+		return false
+	}
+	payloadHash, err := ReadHTTPPayload(r)
+	if err != nil {
+		return false
+	}
+	canonical, err := HTTPCanonicalRequest(r, a.SignedHeaders, payloadHash)
+	if err != nil {
+		return false
+	}
+	return VerifySignature(a, timestamp, canonical.String(), []string{vectorSecret})
+}
+
+func TestReadHTTPPayloadRejectsMismatchedHash(t *testing.T) {
+	r := httptest.NewRequest("POST", "/v1/orders", strings.NewReader("payload"))
+	r.Header.Set(HeaderContentSHA256, HashPayload([]byte("other")))
+	if _, err := ReadHTTPPayload(r); !errors.Is(err, ErrPayloadHashMismatch) {
+		t.Errorf("expected ErrPayloadHashMismatch, got %v", err)
+	}
+}
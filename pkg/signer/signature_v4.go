@@ -0,0 +1,281 @@
+package signer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 签名规范详见 docs/signing.md
+const (
+	// Algorithm 签名算法标识
+	Algorithm = "IAM-HMAC-SHA256"
+	// DefaultRegion 默认签名区域
+	DefaultRegion = "default"
+	// ServiceName IAM服务的签名服务名
+	ServiceName = "iam"
+	// scopeTerminator 凭证范围结束标识
+	scopeTerminator = "iam_request"
+	// MaxClockSkew 允许的客户端与服务端时钟偏差
+	MaxClockSkew = 5 * time.Minute
+
+	timeFormat = "20060102T150405Z"
+	dateFormat = "20060102"
+)
+
+var (
+	// ErrMalformedAuthorization Authorization头格式错误
+	ErrMalformedAuthorization = errors.New("malformed authorization header")
+	// ErrInvalidTimestamp 时间戳格式错误或超出允许范围
+	ErrInvalidTimestamp = errors.New("invalid or expired timestamp")
+	// ErrInvalidScope 凭证范围与请求不匹配
+	ErrInvalidScope = errors.New("invalid credential scope")
+)
+
+// CredentialScope 凭证范围: 日期/区域/服务/iam_request
+type CredentialScope struct {
+	Date    string // YYYYMMDD，必须与签名时间戳的日期一致
+	Region  string
+	Service string
+}
+
+// NewCredentialScope 根据签名时间创建凭证范围
+func NewCredentialScope(t time.Time, region, service string) CredentialScope {
+	return CredentialScope{
+		Date:    t.UTC().Format(dateFormat),
+		Region:  region,
+		Service: service,
+	}
+}
+
+// String 返回凭证范围字符串
+func (s CredentialScope) String() string {
+	return strings.Join([]string{s.Date, s.Region, s.Service, scopeTerminator}, "/")
+}
+
+// ParseCredentialScope 解析凭证范围字符串: <date>/<region>/<service>/iam_request
+func ParseCredentialScope(scope string) (CredentialScope, error) {
+	parts := strings.Split(scope, "/")
+	if len(parts) != 4 || parts[3] != scopeTerminator {
+		return CredentialScope{}, ErrInvalidScope
+	}
+	return CredentialScope{Date: parts[0], Region: parts[1], Service: parts[2]}, nil
+}
+
+// Validate 校验凭证范围与签名时间戳、服务名是否一致
+func (s CredentialScope) Validate(timestamp, service string) error {
+	if len(timestamp) < len(dateFormat) || s.Date != timestamp[:len(dateFormat)] {
+		return ErrInvalidScope
+	}
+	if s.Region == "" || s.Service != service {
+		return ErrInvalidScope
+	}
+	return nil
+}
+
+// Authorization 解析后的签名认证信息
+type Authorization struct {
+	AccessKeyID   string
+	Scope         CredentialScope
+	SignedHeaders []string // 小写并排序的参与签名的头
+	Signature     string   // 16进制小写签名
+}
+
+// String 生成Authorization头的值
+func (a *Authorization) String() string {
+	return fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		Algorithm, a.AccessKeyID, a.Scope.String(), strings.Join(a.SignedHeaders, ";"), a.Signature)
+}
+
+// ParseAuthorization 解析Authorization头
+// 格式: IAM-HMAC-SHA256 Credential=<AKID>/<date>/<region>/<service>/iam_request, SignedHeaders=<h1;h2>, Signature=<hex>
+func ParseAuthorization(header string) (*Authorization, error) {
+	if !strings.HasPrefix(header, Algorithm+" ") {
+		return nil, ErrMalformedAuthorization
+	}
+
+	a := &Authorization{}
+	for _, part := range strings.Split(strings.TrimPrefix(header, Algorithm+" "), ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case strings.HasPrefix(part, "Credential="):
+			credential := strings.TrimPrefix(part, "Credential=")
+			i := strings.Index(credential, "/")
+			if i <= 0 {
+				return nil, ErrMalformedAuthorization
+			}
+			scope, err := ParseCredentialScope(credential[i+1:])
+			if err != nil {
+				return nil, ErrMalformedAuthorization
+			}
+			a.AccessKeyID = credential[:i]
+			a.Scope = scope
+		case strings.HasPrefix(part, "SignedHeaders="):
+			a.SignedHeaders = strings.Split(strings.TrimPrefix(part, "SignedHeaders="), ";")
+		case strings.HasPrefix(part, "Signature="):
+			a.Signature = strings.TrimPrefix(part, "Signature=")
+		}
+	}
+
+	if a.AccessKeyID == "" || a.Signature == "" || len(a.SignedHeaders) == 0 {
+		return nil, ErrMalformedAuthorization
+	}
+	if !sort.StringsAreSorted(a.SignedHeaders) {
+		return nil, ErrMalformedAuthorization
+	}
+	return a, nil
+}
+
+// HasSignedHeader 判断指定的头是否参与了签名
+func (a *Authorization) HasSignedHeader(name string) bool {
+	for _, h := range a.SignedHeaders {
+		if h == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CanonicalRequest 规范请求
+type CanonicalRequest struct {
+	Method        string            // HTTP方法，gRPC固定为POST
+	URI           string            // 请求路径，gRPC为完整方法名
+	Query         string            // 规范查询字符串，gRPC为空
+	Headers       map[string]string // 参与签名的头，键为小写
+	SignedHeaders []string          // 小写并排序的头名称
+	PayloadHash   string            // 请求体SHA256的16进制值
+}
+
+// String 生成规范请求字符串
+func (c *CanonicalRequest) String() string {
+	var headers strings.Builder
+	for _, name := range c.SignedHeaders {
+		headers.WriteString(name)
+		headers.WriteString(":")
+		headers.WriteString(c.Headers[name])
+		headers.WriteString("\n")
+	}
+	return strings.Join([]string{
+		c.Method,
+		c.URI,
+		c.Query,
+		headers.String(),
+		strings.Join(c.SignedHeaders, ";"),
+		c.PayloadHash,
+	}, "\n")
+}
+
+// CanonicalHeaderValue 规范化头的值：多个值以逗号连接，去除首尾空白并合并连续空格
+func CanonicalHeaderValue(values []string) string {
+	normalized := make([]string, 0, len(values))
+	for _, v := range values {
+		normalized = append(normalized, strings.Join(strings.Fields(v), " "))
+	}
+	return strings.Join(normalized, ",")
+}
+
+// CanonicalSignedHeaders 将头名称转为小写、去重并排序
+func CanonicalSignedHeaders(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	var headers []string
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		headers = append(headers, name)
+	}
+	sort.Strings(headers)
+	return headers
+}
+
+// HashPayload 计算请求体的SHA256，返回16进制字符串
+func HashPayload(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// BuildStringToSign 构建待签名字符串
+func BuildStringToSign(timestamp string, scope CredentialScope, canonicalRequest string) string {
+	return strings.Join([]string{
+		Algorithm,
+		timestamp,
+		scope.String(),
+		HashPayload([]byte(canonicalRequest)),
+	}, "\n")
+}
+
+// DeriveSigningKey 派生签名密钥
+func DeriveSigningKey(secretKey string, scope CredentialScope) []byte {
+	dateKey := hmacSha256([]byte("IAM"+secretKey), scope.Date)
+	regionKey := hmacSha256(dateKey, scope.Region)
+	serviceKey := hmacSha256(regionKey, scope.Service)
+	return hmacSha256(serviceKey, scopeTerminator)
+}
+
+// CalculateSignature 计算签名
+func CalculateSignature(stringToSign, secretKey string, scope CredentialScope) string {
+	return hex.EncodeToString(hmacSha256(DeriveSigningKey(secretKey, scope), stringToSign))
+}
+
+// VerifySignature 依次使用候选密钥验证签名，任一密钥验证通过即有效
+// 访问密钥轮换宽限期内，新旧密钥都可用于签名
+// 签名使用hmac.Equal比较，耗时与签名内容无关
+func VerifySignature(a *Authorization, timestamp, canonicalRequest string, secrets []string) bool {
+	stringToSign := BuildStringToSign(timestamp, a.Scope, canonicalRequest)
+	for _, secret := range secrets {
+		if hmac.Equal([]byte(CalculateSignature(stringToSign, secret, a.Scope)), []byte(a.Signature)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTimestamp 验证签名时间戳格式，并检查是否在允许的时钟偏差范围内
+func ValidateTimestamp(timestamp string, now time.Time) error {
+	t, err := ParseTimestamp(timestamp)
+	if err != nil {
+		return err
+	}
+	if now.Sub(t).Abs() > MaxClockSkew {
+		return ErrInvalidTimestamp
+	}
+	return nil
+}
+
+// FormatTimestamp 格式化签名时间戳
+func FormatTimestamp(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
+
+// ParseTimestamp 解析签名时间戳，格式错误时返回ErrInvalidTimestamp
+func ParseTimestamp(timestamp string) (time.Time, error) {
+	t, err := time.Parse(timeFormat, timestamp)
+	if err != nil {
+		return time.Time{}, ErrInvalidTimestamp
+	}
+	return t, nil
+}
+
+// hmacSha256 HMAC-SHA256计算
+func hmacSha256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// ParseRequest 解析HTTP请求中的签名信息，返回Authorization和x-iam-date时间戳
+func ParseRequest(r *http.Request) (*Authorization, string, error) {
+	authorization, err := ParseAuthorization(r.Header.Get("Authorization"))
+	if err != nil {
+		return nil, "", err
+	}
+	return authorization, r.Header.Get(HeaderDate), nil
+}
@@ -0,0 +1,148 @@
+package signer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	iamv1 "github.com/vera-byte/vgo-iam/pkg/proto"
+)
+
+// 测试向量与 docs/signing.md 第6节保持一致
+const (
+	vectorAccessKeyID = "AKIDEXAMPLE"
+	vectorSecret      = "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"
+	vectorMethod      = "/iam.v1.IAM/GetUser"
+)
+
+var vectorTime = time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+func TestSignatureVectors(t *testing.T) {
+	tests := []struct {
+		name          string
+		extraHeaders  map[string]string
+		canonical     string
+		stringToSign  string
+		authorization string
+	}{
+		{
+			name: "date only",
+			canonical: "POST\n/iam.v1.IAM/GetUser\n\n" +
+				"x-iam-date:20250115T120000Z\n\n" +
+				"x-iam-date\n" +
+				"48441a212769a2c31c87da5e7649295237d8ca4f7b5c8bd7d436e448c23df3d6",
+			stringToSign: "IAM-HMAC-SHA256\n20250115T120000Z\n20250115/default/iam/iam_request\n" +
+				"5f4a22d8ef582767ce4087a5762cfb1022c1e2a4858f3a5a08e5b5d322e56898",
+			authorization: "IAM-HMAC-SHA256 Credential=AKIDEXAMPLE/20250115/default/iam/iam_request, " +
+				"SignedHeaders=x-iam-date, Signature=52690cdfd327e02a1c6f33ed45874cb3fbb50b61ae75d1f618739914523c9736",
+		},
+		{
+			name:         "extra signed header",
+			extraHeaders: map[string]string{"x-request-id": "  req-0001   trace "},
+			canonical: "POST\n/iam.v1.IAM/GetUser\n\n" +
+				"x-iam-date:20250115T120000Z\nx-request-id:req-0001 trace\n\n" +
+				"x-iam-date;x-request-id\n" +
+				"48441a212769a2c31c87da5e7649295237d8ca4f7b5c8bd7d436e448c23df3d6",
+			stringToSign: "IAM-HMAC-SHA256\n20250115T120000Z\n20250115/default/iam/iam_request\n" +
+				"b93cdeafd0bcbd29778881af8c3a37f2619ed19451cc9596351398cd600ce471",
+			authorization: "IAM-HMAC-SHA256 Credential=AKIDEXAMPLE/20250115/default/iam/iam_request, " +
+				"SignedHeaders=x-iam-date;x-request-id, Signature=c531471e36ff5ca6d014367b6f04d71f74e6e4afb2dba076c68d6c787cb60659",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &iamv1.GetUserRequest{Name: "alice"}
+			ctx, err := SignGRPCRequest(context.Background(), vectorMethod, req,
+				vectorAccessKeyID, vectorSecret, vectorTime, tt.extraHeaders)
+			if err != nil {
+				t.Fatalf("SignGRPCRequest failed: %v", err)
+			}
+			md, _ := metadata.FromOutgoingContext(ctx)
+
+			if got := md.Get(HeaderAuthorization)[0]; got != tt.authorization {
+				t.Errorf("authorization mismatch\n got: %s\nwant: %s", got, tt.authorization)
+			}
+
+			a, err := ParseAuthorization(tt.authorization)
+			if err != nil {
+				t.Fatalf("ParseAuthorization failed: %v", err)
+			}
+			canonical, err := GRPCCanonicalRequest(vectorMethod, md, a.SignedHeaders, req)
+			if err != nil {
+				t.Fatalf("GRPCCanonicalRequest failed: %v", err)
+			}
+			if canonical.String() != tt.canonical {
+				t.Errorf("canonical request mismatch\n got: %q\nwant: %q", canonical.String(), tt.canonical)
+			}
+			if got := BuildStringToSign(FormatTimestamp(vectorTime), a.Scope, canonical.String()); got != tt.stringToSign {
+				t.Errorf("string to sign mismatch\n got: %q\nwant: %q", got, tt.stringToSign)
+			}
+			if !VerifySignature(a, FormatTimestamp(vectorTime), canonical.String(), []string{vectorSecret}) {
+				t.Error("signature verification failed")
+			}
+		})
+	}
+}
+
+func TestSignatureBindsMethodAndBody(t *testing.T) {
+	req := &iamv1.GetUserRequest{Name: "alice"}
+	ctx, err := SignGRPCRequest(context.Background(), vectorMethod, req,
+		vectorAccessKeyID, vectorSecret, vectorTime, nil)
+	if err != nil {
+		t.Fatalf("SignGRPCRequest failed: %v", err)
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	a, err := ParseAuthorization(md.Get(HeaderAuthorization)[0])
+	if err != nil {
+		t.Fatalf("ParseAuthorization failed: %v", err)
+	}
+	timestamp := FormatTimestamp(vectorTime)
+
+	tampered := []struct {
+		name   string
+		method string
+		req    interface{}
+	}{
+		{"different method", "/iam.v1.IAM/CreateUser", req},
+		{"different body", vectorMethod, &iamv1.GetUserRequest{Name: "mallory"}},
+	}
+	for _, tt := range tampered {
+		canonical, err := GRPCCanonicalRequest(tt.method, md, a.SignedHeaders, tt.req)
+		if err != nil {
+			t.Fatalf("%s: GRPCCanonicalRequest failed: %v", tt.name, err)
+		}
+		if VerifySignature(a, timestamp, canonical.String(), []string{vectorSecret}) {
+			t.Errorf("%s: tampered request passed verification", tt.name)
+		}
+	}
+}
+
+func TestParseAuthorization(t *testing.T) {
+	invalid := []string{
+		"",
+		"Bearer token",
+		"IAM-HMAC-SHA256 Credential=AKID/20250115/default/iam, SignedHeaders=x-iam-date, Signature=abc",
+		"IAM-HMAC-SHA256 Credential=AKID/20250115/default/iam/iam_request, Signature=abc",
+		"IAM-HMAC-SHA256 Credential=AKID/20250115/default/iam/iam_request, SignedHeaders=x-iam-date;a, Signature=abc",
+	}
+	for _, header := range invalid {
+		if _, err := ParseAuthorization(header); err == nil {
+			t.Errorf("expected error for %q", header)
+		}
+	}
+}
+
+func TestValidateTimestamp(t *testing.T) {
+	if err := ValidateTimestamp("20250115T120000Z", vectorTime.Add(MaxClockSkew)); err != nil {
+		t.Errorf("timestamp within skew rejected: %v", err)
+	}
+	if err := ValidateTimestamp("20250115T120000Z", vectorTime.Add(MaxClockSkew+time.Second)); err == nil {
+		t.Error("timestamp outside skew accepted")
+	}
+	if err := ValidateTimestamp("2025-01-15T12:00:00Z", vectorTime); err == nil {
+		t.Error("RFC3339 timestamp accepted")
+	}
+}